@@ -0,0 +1,361 @@
+package jsext
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Nigel2392/jsext/v2/css"
+	"github.com/Nigel2392/jsext/v2/errs"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+// Infinite can be used as the number of iterations of an animation.
+var Infinite = math.Inf(1)
+
+// ErrAnimationCanceled is sent on the channel returned by Animation.Done when the animation is canceled.
+const ErrAnimationCanceled = errs.Error("animation canceled")
+
+// Keyframe is a keyframe of the Web Animations API.
+type Keyframe struct {
+	// Properties to animate, in kebab-case or camelCase.
+	// Values are strings or css.Value.
+	Properties StyleMap
+	// Offset of the keyframe in [0..1].
+	// If no keyframe has a non-zero offset, the offsets are spaced evenly.
+	Offset float64
+	// Easing used until the next keyframe.
+	Easing    string
+	Composite string
+}
+
+// AnimationOptions are the timing options of an animation.
+type AnimationOptions struct {
+	ID       string
+	Duration time.Duration
+	Delay    time.Duration
+	EndDelay time.Duration
+	// Iterations defaults to 1, use Infinite to repeat forever.
+	Iterations     float64
+	IterationStart float64
+	// Direction is "normal", "reverse", "alternate" or "alternate-reverse".
+	Direction string
+	// Easing is a timing function like "ease-in-out" or "cubic-bezier(...)".
+	Easing string
+	// Fill is "none", "forwards", "backwards", "both" or "auto".
+	Fill      string
+	Composite string
+}
+
+func (o AnimationOptions) MarshalJS() js.Value {
+	var obj = js.Global().Get("Object").New()
+	obj.Set("duration", float64(o.Duration)/float64(time.Millisecond))
+	if o.ID != "" {
+		obj.Set("id", o.ID)
+	}
+	if o.Delay != 0 {
+		obj.Set("delay", float64(o.Delay)/float64(time.Millisecond))
+	}
+	if o.EndDelay != 0 {
+		obj.Set("endDelay", float64(o.EndDelay)/float64(time.Millisecond))
+	}
+	switch {
+	case math.IsInf(o.Iterations, 1):
+		obj.Set("iterations", js.Global().Get("Infinity"))
+	case o.Iterations > 0:
+		obj.Set("iterations", o.Iterations)
+	}
+	if o.IterationStart != 0 {
+		obj.Set("iterationStart", o.IterationStart)
+	}
+	var strs = map[string]string{
+		"direction": o.Direction,
+		"easing":    o.Easing,
+		"fill":      o.Fill,
+		"composite": o.Composite,
+	}
+	for k, v := range strs {
+		if v != "" {
+			obj.Set(k, v)
+		}
+	}
+	return obj
+}
+
+func keyframesToJS(keyframes []Keyframe) js.Value {
+	var offsets bool
+	for _, k := range keyframes {
+		if k.Offset != 0 {
+			offsets = true
+		}
+	}
+	var arr = js.Global().Get("Array").New(len(keyframes))
+	for i, k := range keyframes {
+		var obj = js.Global().Get("Object").New()
+		for property, value := range k.Properties {
+			var str string
+			switch v := value.(type) {
+			case string:
+				str = v
+			case css.Value:
+				str = v.String()
+			default:
+				obj.Set(camelName(property), value)
+				continue
+			}
+			obj.Set(camelName(property), str)
+		}
+		if offsets {
+			obj.Set("offset", k.Offset)
+		}
+		if k.Easing != "" {
+			obj.Set("easing", k.Easing)
+		}
+		if k.Composite != "" {
+			obj.Set("composite", k.Composite)
+		}
+		arr.SetIndex(i, obj)
+	}
+	return arr
+}
+
+// camelName converts kebab-case property names to camelCase, as used by the Web Animations API.
+func camelName(property string) string {
+	if !strings.Contains(property, "-") || strings.HasPrefix(property, "--") {
+		return property
+	}
+	var parts = strings.Split(property, "-")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// Animation is a handle to a running Web Animation.
+type Animation struct {
+	v    js.Value
+	done chan error
+}
+
+// AnimateKeyframes starts a typed animation on the element.
+func (e Element) AnimateKeyframes(keyframes []Keyframe, opts AnimationOptions) *Animation {
+	return NewAnimation(e.JSValue(), keyframes, opts)
+}
+
+// NewAnimation starts an animation on the target element.
+func NewAnimation(target js.Value, keyframes []Keyframe, opts AnimationOptions) *Animation {
+	return WrapAnimation(target.Call("animate", keyframesToJS(keyframes), opts.MarshalJS()))
+}
+
+// WrapAnimation wraps an existing Animation object, for example one from getAnimations.
+func WrapAnimation(v js.Value) *Animation {
+	return &Animation{v: v}
+}
+
+func (a *Animation) MarshalJS() js.Value {
+	return a.v
+}
+
+func (a *Animation) Value() Value {
+	return Value(a.v)
+}
+
+func (a *Animation) Play() *Animation {
+	a.v.Call("play")
+	return a
+}
+
+func (a *Animation) Pause() *Animation {
+	a.v.Call("pause")
+	return a
+}
+
+// Reverse plays the animation backwards from its current position.
+func (a *Animation) Reverse() *Animation {
+	a.v.Call("reverse")
+	return a
+}
+
+// Cancel removes the effects of the animation, and rejects its completion.
+func (a *Animation) Cancel() *Animation {
+	a.v.Call("cancel")
+	return a
+}
+
+// Finish seeks to the end of the animation.
+func (a *Animation) Finish() *Animation {
+	a.v.Call("finish")
+	return a
+}
+
+// PlayState returns "idle", "running", "paused" or "finished".
+func (a *Animation) PlayState() string {
+	return a.v.Get("playState").String()
+}
+
+func (a *Animation) CurrentTime() time.Duration {
+	var t = a.v.Get("currentTime")
+	if t.IsNull() {
+		return 0
+	}
+	return time.Duration(t.Float() * float64(time.Millisecond))
+}
+
+func (a *Animation) SetCurrentTime(t time.Duration) *Animation {
+	a.v.Set("currentTime", float64(t)/float64(time.Millisecond))
+	return a
+}
+
+func (a *Animation) PlaybackRate() float64 {
+	return a.v.Get("playbackRate").Float()
+}
+
+func (a *Animation) SetPlaybackRate(rate float64) *Animation {
+	a.v.Call("updatePlaybackRate", rate)
+	return a
+}
+
+// Finished returns the promise which resolves when the animation finishes.
+func (a *Animation) Finished() Promise {
+	return Promise{Value(a.v.Get("finished"))}
+}
+
+// Done returns a channel which receives nil when the animation finishes,
+// or ErrAnimationCanceled when it is canceled.
+//
+// The channel receives a single value for each time the animation runs to completion;
+// the same channel is returned until it has received a value.
+func (a *Animation) Done() <-chan error {
+	if a.done != nil {
+		return a.done
+	}
+	var done = make(chan error, 1)
+	var finished = a.v.Get("finished")
+	a.done = done
+	go func() {
+		var _, err = Await(finished)
+		a.done = nil
+		if err != nil {
+			done <- ErrAnimationCanceled
+			return
+		}
+		done <- nil
+	}()
+	return done
+}
+
+// Wait blocks until the animation finishes or is canceled.
+//
+// Wait must not be called from a JavaScript callback, as it blocks the event loop.
+func (a *Animation) Wait() error {
+	return <-a.Done()
+}
+
+// OnFinish calls f each time the animation finishes.
+func (a *Animation) OnFinish(f func(a *Animation)) *Listener {
+	return Listen(a.v, "finish", func(js.Value, Event) {
+		f(a)
+	})
+}
+
+// OnCancel calls f when the animation is canceled.
+func (a *Animation) OnCancel(f func(a *Animation)) *Listener {
+	return Listen(a.v, "cancel", func(js.Value, Event) {
+		f(a)
+	})
+}
+
+// Animations is a group of animations which are controlled together.
+type Animations []*Animation
+
+func (g Animations) Play() Animations {
+	for _, a := range g {
+		a.Play()
+	}
+	return g
+}
+
+func (g Animations) Pause() Animations {
+	for _, a := range g {
+		a.Pause()
+	}
+	return g
+}
+
+func (g Animations) Reverse() Animations {
+	for _, a := range g {
+		a.Reverse()
+	}
+	return g
+}
+
+func (g Animations) Cancel() Animations {
+	for _, a := range g {
+		a.Cancel()
+	}
+	return g
+}
+
+func (g Animations) Finish() Animations {
+	for _, a := range g {
+		a.Finish()
+	}
+	return g
+}
+
+// Done returns a channel which receives once all animations in the group are done,
+// with the first error if any of them was canceled.
+func (g Animations) Done() <-chan error {
+	var done = make(chan error, 1)
+	var chans = make([]<-chan error, len(g))
+	for i, a := range g {
+		chans[i] = a.Done()
+	}
+	go func() {
+		var first error
+		for _, c := range chans {
+			if err := <-c; err != nil && first == nil {
+				first = err
+			}
+		}
+		done <- first
+	}()
+	return done
+}
+
+func (g Animations) Wait() error {
+	return <-g.Done()
+}
+
+// Sequence starts each animation once the previous one has finished.
+//
+// Steps are functions, so an animation only starts when it is its turn.
+// The sequence stops at the first canceled animation, and its error is sent on the channel.
+func Sequence(steps ...func() *Animation) <-chan error {
+	var done = make(chan error, 1)
+	go func() {
+		for _, step := range steps {
+			var a = step()
+			if a == nil {
+				continue
+			}
+			if err := a.Wait(); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+	return done
+}
+
+// PrefersReducedMotion reports whether the user asked to minimize animations.
+func PrefersReducedMotion() bool {
+	var matchMedia = js.Global().Get("matchMedia")
+	if matchMedia.IsUndefined() {
+		return false
+	}
+	return js.Global().Call("matchMedia", "(prefers-reduced-motion: reduce)").Get("matches").Bool()
+}
@@ -0,0 +1,59 @@
+//go:build js && wasm
+// +build js,wasm
+
+package canvas
+
+import (
+	"syscall/js"
+	"time"
+
+	"github.com/Nigel2392/jsext/v2/errs"
+	"github.com/Nigel2392/jsext/v2/files"
+)
+
+const ErrEmptyCanvas errs.Error = "canvas: the canvas has no pixels to export"
+
+// ToBlob encodes the canvas as an image file, with an optional quality between 0 and 1 for lossy formats.
+//
+// It blocks until the image is encoded, so it must not be called from a JavaScript callback.
+// The file can be decoded with the image package, after reading it with files.Read.
+func (c Canvas) ToBlob(mimetype string, quality ...float64) (files.File, error) {
+	var done = make(chan js.Value, 1)
+	var callback js.Func
+	callback = js.FuncOf(func(this js.Value, args []js.Value) any {
+		callback.Release()
+		done <- args[0]
+		return nil
+	})
+	if len(quality) > 0 {
+		c.Call("toBlob", callback, mimetype, quality[0])
+	} else {
+		c.Call("toBlob", callback, mimetype)
+	}
+	var blob = <-done
+	if blob.IsNull() {
+		return files.File{}, ErrEmptyCanvas
+	}
+	var name = "canvas"
+	switch blob.Get("type").String() {
+	case "image/png":
+		name += ".png"
+	case "image/jpeg":
+		name += ".jpg"
+	case "image/webp":
+		name += ".webp"
+	}
+	var file = js.Global().Get("File").New([]any{blob}, name, map[string]any{
+		"type":         blob.Get("type"),
+		"lastModified": time.Now().UnixMilli(),
+	})
+	return files.NewFromJS(file), nil
+}
+
+func (c Canvas) ToPNG() (files.File, error) {
+	return c.ToBlob("image/png")
+}
+
+func (c Canvas) ToJPEG(quality float64) (files.File, error) {
+	return c.ToBlob("image/jpeg", quality)
+}
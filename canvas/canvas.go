@@ -8,6 +8,7 @@ import (
 
 	"github.com/Nigel2392/jsext/v2"
 	"github.com/Nigel2392/jsext/v2/canvas/context"
+	"github.com/Nigel2392/jsext/v2/canvas/webgl"
 )
 
 const (
@@ -72,6 +73,16 @@ func (c Canvas) Context2D() context.Context2D {
 	return context.Context2D(c.Call("getContext", "2d"))
 }
 
+// Batch2D returns a recording context for the 2d context, see context.Batch.
+func (c Canvas) Batch2D() *context.Batch {
+	return context.NewBatch(c.Context2D())
+}
+
+// WebGL2 returns the typed WebGL2 context, attributes are the WebGLContextAttributes.
+func (c Canvas) WebGL2(attributes ...map[string]any) (*webgl.Context, error) {
+	return webgl.New(c.Value(), attributes...)
+}
+
 func (c Canvas) InnerHTML(s ...string) string {
 	if len(s) > 0 {
 		c.Set("innerHTML", s[0])
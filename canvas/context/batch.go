@@ -0,0 +1,667 @@
+//go:build js && wasm
+// +build js,wasm
+
+package context
+
+import (
+	"encoding/binary"
+	"image"
+	"math"
+	"syscall/js"
+
+	"github.com/Nigel2392/jsext/v2/canvas/path"
+)
+
+// Batch records draw commands into a byte buffer, and replays them on the context in a single call.
+//
+// It has the same method set as Context2D. Commands are only drawn once Flush is called,
+// methods which return a value from the context flush the buffer first.
+// Use Flush once per frame, for example at the end of a requestAnimationFrame callback.
+type Batch struct {
+	ctx    Context2D
+	buf    []byte
+	objs   []any
+	target uint32
+	jsBuf  js.Value
+
+	// Values of properties which have been set, so reading them back does not flush.
+	props map[string]any
+}
+
+// NewBatch returns a recording context for ctx.
+func NewBatch(ctx Context2D) *Batch {
+	return &Batch{
+		ctx:   ctx,
+		props: make(map[string]any),
+	}
+}
+
+// Context returns the context the batch draws on.
+func (b *Batch) Context() Context2D {
+	return b.ctx
+}
+
+// Len returns the size of the recorded commands in bytes.
+func (b *Batch) Len() int {
+	return len(b.buf)
+}
+
+// Flush draws the recorded commands, and empties the buffer.
+func (b *Batch) Flush() {
+	if len(b.buf) == 0 {
+		return
+	}
+	if b.jsBuf.IsUndefined() || b.jsBuf.Length() < len(b.buf) {
+		b.jsBuf = js.Global().Get("Uint8Array").New(cap(b.buf))
+	}
+	js.CopyBytesToJS(b.jsBuf, b.buf)
+	var objs any = js.Undefined()
+	if len(b.objs) > 0 {
+		objs = b.objs
+	}
+	replay().Invoke(b.ctx.Value(), b.jsBuf, len(b.buf), objs)
+	b.buf = b.buf[:0]
+	b.objs = b.objs[:0]
+	b.target = 0
+}
+
+// Discard drops the recorded commands without drawing them.
+func (b *Batch) Discard() {
+	b.buf = b.buf[:0]
+	b.objs = b.objs[:0]
+	b.target = 0
+}
+
+func (b *Batch) op(op byte) {
+	b.buf = append(b.buf, op)
+}
+
+func (b *Batch) f64(values ...float64) {
+	for _, v := range values {
+		b.buf = binary.LittleEndian.AppendUint64(b.buf, math.Float64bits(v))
+	}
+}
+
+func (b *Batch) u32(v uint32) {
+	b.buf = binary.LittleEndian.AppendUint32(b.buf, v)
+}
+
+func (b *Batch) str(s string) {
+	b.u32(uint32(len(s)))
+	b.buf = append(b.buf, s...)
+}
+
+func (b *Batch) bool(v bool) {
+	if v {
+		b.buf = append(b.buf, 1)
+	} else {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+// arg encodes a tagged argument of opSet and opCall.
+func (b *Batch) arg(v any) {
+	switch v := v.(type) {
+	case nil:
+		b.buf = append(b.buf, argUndefined)
+	case float64:
+		b.buf = append(b.buf, argNumber)
+		b.f64(v)
+	case float32:
+		b.arg(float64(v))
+	case int:
+		b.arg(float64(v))
+	case string:
+		b.buf = append(b.buf, argString)
+		b.str(v)
+	case bool:
+		if v {
+			b.buf = append(b.buf, argTrue)
+		} else {
+			b.buf = append(b.buf, argFalse)
+		}
+	case *BatchPath:
+		b.buf = append(b.buf, argPath)
+		b.u32(v.id)
+	default:
+		b.buf = append(b.buf, argObject)
+		b.u32(uint32(len(b.objs)))
+		b.objs = append(b.objs, v)
+	}
+}
+
+// on makes the path the target of path commands, 0 is the context.
+func (b *Batch) on(target uint32) {
+	if b.target != target {
+		b.op(opTarget)
+		b.u32(target)
+		b.target = target
+	}
+}
+
+func (b *Batch) pathOp(target uint32, op byte, args ...float64) {
+	b.on(target)
+	b.op(op)
+	b.f64(args...)
+}
+
+func (b *Batch) draw(op byte, p *BatchPath, fillRule []string) {
+	b.op(op)
+	if p != nil {
+		b.u32(p.id)
+	} else {
+		b.u32(0)
+	}
+	if len(fillRule) > 0 {
+		b.str(fillRule[0])
+	} else {
+		b.str("")
+	}
+}
+
+// Value flushes the batch and returns the context.
+func (b *Batch) Value() js.Value {
+	b.Flush()
+	return b.ctx.Value()
+}
+
+// Get flushes the batch and returns the property of the context.
+func (b *Batch) Get(key string) js.Value {
+	b.Flush()
+	return b.ctx.Get(key)
+}
+
+// Set records setting a property of the context.
+func (b *Batch) Set(key string, value interface{}) {
+	b.props[key] = value
+	b.op(opSet)
+	b.str(key)
+	b.arg(value)
+}
+
+// Call flushes the batch and calls the method of the context.
+func (b *Batch) Call(method string, args ...interface{}) js.Value {
+	b.Flush()
+	return b.ctx.Call(method, args...)
+}
+
+// Record records a call of any method of the context, its return value is discarded.
+//
+// Arguments can be numbers, strings, booleans, paths of the batch or JavaScript values.
+func (b *Batch) Record(method string, args ...interface{}) {
+	if len(args) > math.MaxUint8 {
+		panic("context: too many arguments for " + method)
+	}
+	b.op(opCall)
+	b.str(method)
+	b.buf = append(b.buf, byte(len(args)))
+	for _, a := range args {
+		b.arg(a)
+	}
+}
+
+func (b *Batch) Canvas() js.Value {
+	return b.ctx.Canvas()
+}
+
+func (b *Batch) stringProp(key string, v []string) string {
+	if len(v) > 0 {
+		b.Set(key, v[0])
+		return v[0]
+	}
+	if s, ok := b.props[key].(string); ok {
+		return s
+	}
+	return b.Get(key).String()
+}
+
+func (b *Batch) floatProp(key string, v []float64) float64 {
+	if len(v) > 0 {
+		b.Set(key, v[0])
+		return v[0]
+	}
+	if f, ok := b.props[key].(float64); ok {
+		return f
+	}
+	return b.Get(key).Float()
+}
+
+func (b *Batch) Direction(d ...string) string   { return b.stringProp("direction", d) }
+func (b *Batch) FillStyle(s ...string) string   { return b.stringProp("fillStyle", s) }
+func (b *Batch) Filter(f ...string) string      { return b.stringProp("filter", f) }
+func (b *Batch) Font(f ...string) string        { return b.stringProp("font", f) }
+func (b *Batch) FontKerning(f ...string) string { return b.stringProp("fontKerning", f) }
+func (b *Batch) FontStretch(f ...string) string { return b.stringProp("fontStretch", f) }
+func (b *Batch) FontVariantCaps(f ...string) string {
+	return b.stringProp("fontVariantCaps", f)
+}
+func (b *Batch) GlobalAlpha(a ...float64) float64 { return b.floatProp("globalAlpha", a) }
+func (b *Batch) GlobalCompositeOperation(o ...string) string {
+	return b.stringProp("globalCompositeOperation", o)
+}
+
+func (b *Batch) ImageSmoothingEnabled(e ...bool) bool {
+	if len(e) > 0 {
+		b.Set("imageSmoothingEnabled", e[0])
+		return e[0]
+	}
+	if v, ok := b.props["imageSmoothingEnabled"].(bool); ok {
+		return v
+	}
+	return b.Get("imageSmoothingEnabled").Bool()
+}
+
+func (b *Batch) ImageSmoothingQuality(q ...string) string {
+	return b.stringProp("imageSmoothingQuality", q)
+}
+func (b *Batch) LetterSpacing(l ...string) string    { return b.stringProp("letterSpacing", l) }
+func (b *Batch) LineCap(c ...string) string          { return b.stringProp("lineCap", c) }
+func (b *Batch) LineDashOffset(o ...float64) float64 { return b.floatProp("lineDashOffset", o) }
+func (b *Batch) LineJoin(j ...string) string         { return b.stringProp("lineJoin", j) }
+func (b *Batch) LineWidth(w ...float64) float64      { return b.floatProp("lineWidth", w) }
+func (b *Batch) MiterLimit(l ...float64) float64     { return b.floatProp("miterLimit", l) }
+func (b *Batch) ShadowBlur(s ...float64) float64     { return b.floatProp("shadowBlur", s) }
+func (b *Batch) ShadowColor(c ...string) string      { return b.stringProp("shadowColor", c) }
+func (b *Batch) ShadowOffsetX(x ...float64) float64  { return b.floatProp("shadowOffsetX", x) }
+func (b *Batch) ShadowOffsetY(y ...float64) float64  { return b.floatProp("shadowOffsetY", y) }
+func (b *Batch) StrokeStyle(s ...string) string      { return b.stringProp("strokeStyle", s) }
+func (b *Batch) TextAlign(t ...string) string        { return b.stringProp("textAlign", t) }
+func (b *Batch) TextBaseline(t ...string) string     { return b.stringProp("textBaseline", t) }
+func (b *Batch) TextRendering(t ...string) string    { return b.stringProp("textRendering", t) }
+func (b *Batch) WordSpacing(w ...string) string      { return b.stringProp("wordSpacing", w) }
+
+func (b *Batch) Arc(x, y, radius, startAngle, endAngle float64, anticlockwise ...bool) {
+	b.pathOp(0, opArc, x, y, radius, startAngle, endAngle)
+	b.bool(len(anticlockwise) > 0 && anticlockwise[0])
+}
+
+func (b *Batch) ArcTo(x1, y1, x2, y2, radius float64) {
+	b.pathOp(0, opArcTo, x1, y1, x2, y2, radius)
+}
+
+func (b *Batch) BeginPath() {
+	b.op(opBeginPath)
+}
+
+func (b *Batch) BeginPath2(startX, startY float64) {
+	b.BeginPath()
+	b.MoveTo(startX, startY)
+}
+
+func (b *Batch) BezierCurveTo(cp1x, cp1y, cp2x, cp2y, x, y float64) {
+	b.pathOp(0, opBezierCurveTo, cp1x, cp1y, cp2x, cp2y, x, y)
+}
+
+func (b *Batch) ClearRect(x, y, width, height float64) {
+	b.op(opClearRect)
+	b.f64(x, y, width, height)
+}
+
+func (b *Batch) Clip(fillRule ...string) {
+	b.draw(opClip, nil, fillRule)
+}
+
+func (b *Batch) ClosePath() {
+	b.pathOp(0, opClosePath)
+}
+
+func (b *Batch) CreateConicGradient(startAngle, x, y float64) js.Value {
+	return b.ctx.CreateConicGradient(startAngle, x, y)
+}
+
+func (b *Batch) CreateImageData(width, height float64, settings map[string]any) js.Value {
+	return b.ctx.CreateImageData(width, height, settings)
+}
+
+func (b *Batch) CreateImageDataFrom(data js.Value) js.Value {
+	return b.ctx.CreateImageDataFrom(data)
+}
+
+func (b *Batch) CreateLinearGradient(x0, y0, x1, y1 float64) js.Value {
+	return b.ctx.CreateLinearGradient(x0, y0, x1, y1)
+}
+
+func (b *Batch) CreatePattern(image js.Value, repetition string) js.Value {
+	return b.ctx.CreatePattern(image, repetition)
+}
+
+func (b *Batch) CreateRadialGradient(x0, y0, r0, x1, y1, r1 float64) js.Value {
+	return b.ctx.CreateRadialGradient(x0, y0, r0, x1, y1, r1)
+}
+
+func (b *Batch) DrawFocusIfNeeded(element js.Value, path ...path.Path2D) {
+	if len(path) > 0 {
+		b.Record("drawFocusIfNeeded", element, path[0].Value())
+	} else {
+		b.Record("drawFocusIfNeeded", element)
+	}
+}
+
+func (b *Batch) drawImage(image js.Value, settings map[string]any, args ...float64) {
+	var list = make([]any, 0, len(args)+2)
+	list = append(list, image)
+	for _, a := range args {
+		list = append(list, a)
+	}
+	if len(settings) > 0 {
+		list = append(list, settings)
+	}
+	b.Record("drawImage", list...)
+}
+
+func (b *Batch) DrawImage(image js.Value, dx, dy float64, settings map[string]any) {
+	b.drawImage(image, settings, dx, dy)
+}
+
+func (b *Batch) DrawImage2(image js.Value, dx, dy, dw, dh float64, settings map[string]any) {
+	b.drawImage(image, settings, dx, dy, dw, dh)
+}
+
+func (b *Batch) DrawImage3(image js.Value, sx, sy, sw, sh, dx, dy, dw, dh float64, settings map[string]any) {
+	b.drawImage(image, settings, sx, sy, sw, sh, dx, dy, dw, dh)
+}
+
+func (b *Batch) Ellipse(x, y, radiusX, radiusY, rotation, startAngle, endAngle float64, anticlockwise ...bool) {
+	b.pathOp(0, opEllipse, x, y, radiusX, radiusY, rotation, startAngle, endAngle)
+	b.bool(len(anticlockwise) > 0 && anticlockwise[0])
+}
+
+func (b *Batch) Fill(fillRule ...string) {
+	b.draw(opFill, nil, fillRule)
+}
+
+func (b *Batch) FillRect(x, y, width, height float64) {
+	b.op(opFillRect)
+	b.f64(x, y, width, height)
+}
+
+func (b *Batch) text(op byte, text string, x, y float64, maxWidth []float64) {
+	b.op(op)
+	b.str(text)
+	var w = -1.0
+	if len(maxWidth) > 0 {
+		w = maxWidth[0]
+	}
+	b.f64(x, y, w)
+}
+
+func (b *Batch) FillText(text string, x, y float64, maxWidth ...float64) {
+	b.text(opFillText, text, x, y, maxWidth)
+}
+
+func (b *Batch) GetContextAttributes() js.Value {
+	b.Flush()
+	return b.ctx.GetContextAttributes()
+}
+
+func (b *Batch) GetImageData(sx, sy, sw, sh float64, settings ...map[string]any) js.Value {
+	b.Flush()
+	return b.ctx.GetImageData(sx, sy, sw, sh, settings...)
+}
+
+func (b *Batch) GetLineDash() js.Value {
+	b.Flush()
+	return b.ctx.GetLineDash()
+}
+
+func (b *Batch) GetTransform() js.Value {
+	b.Flush()
+	return b.ctx.GetTransform()
+}
+
+func (b *Batch) IsContextLost() bool {
+	return b.ctx.IsContextLost()
+}
+
+func (b *Batch) IsPointInPath(x, y float64, fillRule ...string) bool {
+	b.Flush()
+	return b.ctx.IsPointInPath(x, y, fillRule...)
+}
+
+func (b *Batch) IsPointInPath2(path path.Path2D, x, y float64, fillRule ...string) bool {
+	b.Flush()
+	return b.ctx.IsPointInPath2(path, x, y, fillRule...)
+}
+
+func (b *Batch) IsPointInStroke(x, y float64, path ...string) bool {
+	b.Flush()
+	return b.ctx.IsPointInStroke(x, y, path...)
+}
+
+func (b *Batch) LineTo(x, y float64) {
+	b.pathOp(0, opLineTo, x, y)
+}
+
+func (b *Batch) MeasureText(text string) js.Value {
+	b.Flush()
+	return b.ctx.MeasureText(text)
+}
+
+func (b *Batch) MoveTo(x, y float64) {
+	b.pathOp(0, opMoveTo, x, y)
+}
+
+func (b *Batch) PutImageData(imageData js.Value, dx, dy float64) {
+	b.Record("putImageData", imageData, dx, dy)
+}
+
+func (b *Batch) PutImageData2(imageData js.Value, dx, dy, dirtyX, dirtyY, dirtyWidth, dirtyHeight float64) {
+	b.Record("putImageData", imageData, dx, dy, dirtyX, dirtyY, dirtyWidth, dirtyHeight)
+}
+
+func (b *Batch) QuadraticCurveTo(cpx, cpy, x, y float64) {
+	b.pathOp(0, opQuadraticCurveTo, cpx, cpy, x, y)
+}
+
+func (b *Batch) Rect(x, y, width, height float64) {
+	b.pathOp(0, opRect, x, y, width, height)
+}
+
+// Reset resets the context, and forgets the recorded property values.
+func (b *Batch) Reset() {
+	b.props = make(map[string]any)
+	b.Record("reset")
+}
+
+func (b *Batch) ResetTransform() {
+	b.op(opResetTransform)
+}
+
+func (b *Batch) Restore() {
+	// Restored properties are read from the context again.
+	b.props = make(map[string]any)
+	b.op(opRestore)
+}
+
+func (b *Batch) Rotate(angle float64) {
+	b.op(opRotate)
+	b.f64(angle)
+}
+
+func (b *Batch) RoundRect(x, y, width, height, radius float64) {
+	b.pathOp(0, opRoundRect, x, y, width, height, radius)
+}
+
+func (b *Batch) Save() {
+	b.op(opSave)
+}
+
+func (b *Batch) Scale(x, y float64) {
+	b.op(opScale)
+	b.f64(x, y)
+}
+
+func (b *Batch) ScrollPathIntoView(path ...string) {
+	if len(path) > 0 {
+		b.Record("scrollPathIntoView", path[0])
+	} else {
+		b.Record("scrollPathIntoView")
+	}
+}
+
+func (b *Batch) SetLineDash(segments []float64) {
+	b.op(opSetLineDash)
+	b.u32(uint32(len(segments)))
+	b.f64(segments...)
+}
+
+func (b *Batch) SetTransform(a, bb, c, d, e, f float64) {
+	b.op(opSetTransform)
+	b.f64(a, bb, c, d, e, f)
+}
+
+func (b *Batch) SetTransformMatrix(matrix js.Value) {
+	b.Record("setTransform", matrix)
+}
+
+func (b *Batch) Stroke(path ...string) {
+	if len(path) > 0 {
+		b.Record("stroke", path[0])
+	} else {
+		b.draw(opStroke, nil, nil)
+	}
+}
+
+func (b *Batch) StrokeRect(x, y, width, height float64) {
+	b.op(opStrokeRect)
+	b.f64(x, y, width, height)
+}
+
+func (b *Batch) StrokeText(text string, x, y float64, maxWidth ...float64) {
+	b.text(opStrokeText, text, x, y, maxWidth)
+}
+
+func (b *Batch) Transform(a, bb, c, d, e, f float64) {
+	b.op(opTransform)
+	b.f64(a, bb, c, d, e, f)
+}
+
+func (b *Batch) Translate(x, y float64) {
+	b.op(opTranslate)
+	b.f64(x, y)
+}
+
+// FillPath fills a path of the batch.
+func (b *Batch) FillPath(p *BatchPath, fillRule ...string) {
+	b.draw(opFill, p, fillRule)
+}
+
+// StrokePath strokes a path of the batch.
+func (b *Batch) StrokePath(p *BatchPath) {
+	b.draw(opStroke, p, nil)
+}
+
+// ClipPath clips to a path of the batch.
+func (b *Batch) ClipPath(p *BatchPath, fillRule ...string) {
+	b.draw(opClip, p, fillRule)
+}
+
+var lastPathID uint32
+
+// BatchPath is a Path2D which is built by the commands of a batch.
+//
+// The path lives in JavaScript until Release is called, so it can be reused across frames.
+type BatchPath struct {
+	b  *Batch
+	id uint32
+}
+
+// NewPath records the creation of a new Path2D.
+func (b *Batch) NewPath() *BatchPath {
+	lastPathID++
+	var p = &BatchPath{b: b, id: lastPathID}
+	b.op(opNewPath)
+	b.u32(p.id)
+	return p
+}
+
+// Release records freeing the path, it must not be used afterwards.
+func (p *BatchPath) Release() {
+	p.b.op(opFreePath)
+	p.b.u32(p.id)
+	if p.b.target == p.id {
+		p.b.on(0)
+	}
+}
+
+func (p *BatchPath) AddPath(other *BatchPath) {
+	p.b.op(opAddPath)
+	p.b.u32(p.id)
+	p.b.u32(other.id)
+}
+
+func (p *BatchPath) ClosePath() {
+	p.b.pathOp(p.id, opClosePath)
+}
+
+func (p *BatchPath) MoveTo(x, y float64) {
+	p.b.pathOp(p.id, opMoveTo, x, y)
+}
+
+func (p *BatchPath) LineTo(x, y float64) {
+	p.b.pathOp(p.id, opLineTo, x, y)
+}
+
+func (p *BatchPath) QuadraticCurveTo(cpx, cpy, x, y float64) {
+	p.b.pathOp(p.id, opQuadraticCurveTo, cpx, cpy, x, y)
+}
+
+func (p *BatchPath) BezierCurveTo(cp1x, cp1y, cp2x, cp2y, x, y float64) {
+	p.b.pathOp(p.id, opBezierCurveTo, cp1x, cp1y, cp2x, cp2y, x, y)
+}
+
+func (p *BatchPath) Arc(x, y, radius, startAngle, endAngle float64, anticlockwise ...bool) {
+	p.b.pathOp(p.id, opArc, x, y, radius, startAngle, endAngle)
+	p.b.bool(len(anticlockwise) > 0 && anticlockwise[0])
+}
+
+func (p *BatchPath) ArcTo(x1, y1, x2, y2, radius float64) {
+	p.b.pathOp(p.id, opArcTo, x1, y1, x2, y2, radius)
+}
+
+func (p *BatchPath) Ellipse(x, y, radiusX, radiusY, rotation, startAngle, endAngle float64, anticlockwise ...bool) {
+	p.b.pathOp(p.id, opEllipse, x, y, radiusX, radiusY, rotation, startAngle, endAngle)
+	p.b.bool(len(anticlockwise) > 0 && anticlockwise[0])
+}
+
+func (p *BatchPath) Rect(x, y, width, height float64) {
+	p.b.pathOp(p.id, opRect, x, y, width, height)
+}
+
+func (p *BatchPath) RoundRect(x, y, width, height, radius float64) {
+	p.b.pathOp(p.id, opRoundRect, x, y, width, height, radius)
+}
+
+func (b *Batch) DrawBitmap(img Image, dx, dy float64) {
+	b.Record("drawImage", img.Value(), dx, dy)
+}
+
+func (b *Batch) DrawBitmapScaled(img Image, dx, dy, dw, dh float64) {
+	b.Record("drawImage", img.Value(), dx, dy, dw, dh)
+}
+
+func (b *Batch) GetImageNRGBA(sx, sy, sw, sh int) *image.NRGBA {
+	b.Flush()
+	return b.ctx.GetImageNRGBA(sx, sy, sw, sh)
+}
+
+func (b *Batch) GetImageRGBA(sx, sy, sw, sh int) *image.RGBA {
+	b.Flush()
+	return b.ctx.GetImageRGBA(sx, sy, sw, sh)
+}
+
+func (b *Batch) PutImageNRGBA(img *image.NRGBA, dx, dy int) {
+	b.Flush()
+	b.ctx.PutImageNRGBA(img, dx, dy)
+}
+
+func (b *Batch) PutImageRGBA(img *image.RGBA, dx, dy int) {
+	b.Flush()
+	b.ctx.PutImageRGBA(img, dx, dy)
+}
+
+// PutImage flushes the batch, and writes the image to the context.
+func (b *Batch) PutImage(img image.Image, dx, dy int) {
+	b.Flush()
+	b.ctx.PutImage(img, dx, dy)
+}
@@ -0,0 +1,188 @@
+//go:build js && wasm
+// +build js,wasm
+
+package context
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"syscall/js"
+
+	"github.com/Nigel2392/jsext/v2"
+	"github.com/Nigel2392/jsext/v2/errs"
+	"github.com/Nigel2392/jsext/v2/files"
+)
+
+const ErrImageLoad errs.Error = "context: image could not be loaded"
+
+// Image is an ImageBitmap, it can be drawn on a context without decoding it again.
+type Image js.Value
+
+func (i Image) Value() js.Value {
+	return js.Value(i)
+}
+
+func (i Image) Width() int {
+	return js.Value(i).Get("width").Int()
+}
+
+func (i Image) Height() int {
+	return js.Value(i).Get("height").Int()
+}
+
+// Close releases the memory of the image, it can not be drawn afterwards.
+func (i Image) Close() {
+	js.Value(i).Call("close")
+}
+
+// LoadImage fetches and decodes the image at the url.
+//
+// It blocks until the image is decoded, so it must not be called from a JavaScript callback.
+func LoadImage(url string) (Image, error) {
+	var resp, err = jsext.Await(js.Global().Call("fetch", url))
+	if err != nil {
+		return Image{}, err
+	}
+	if !resp.Get("ok").Bool() {
+		return Image{}, fmt.Errorf("%w: %s returned status %d", ErrImageLoad, url, resp.Get("status").Int())
+	}
+	blob, err := jsext.Await(resp.Call("blob"))
+	if err != nil {
+		return Image{}, err
+	}
+	return bitmap(blob)
+}
+
+// LoadImageFile decodes the image in a file, for example one selected in a file input.
+func LoadImageFile(f files.File) (Image, error) {
+	return bitmap(f.MarshalJS())
+}
+
+// LoadImageBytes decodes an encoded image, like a PNG or JPEG file.
+func LoadImageBytes(data []byte, mimetype string) (Image, error) {
+	var array = js.Global().Get("Uint8Array").New(len(data))
+	js.CopyBytesToJS(array, data)
+	var blob = js.Global().Get("Blob").New([]any{array}, map[string]any{
+		"type": mimetype,
+	})
+	return bitmap(blob)
+}
+
+func bitmap(source js.Value) (Image, error) {
+	var v, err = jsext.Await(js.Global().Call("createImageBitmap", source))
+	if err != nil {
+		return Image{}, fmt.Errorf("%w: %v", ErrImageLoad, err)
+	}
+	return Image(v), nil
+}
+
+// DrawBitmap draws the image at its own size.
+func (c Context2D) DrawBitmap(img Image, dx, dy float64) {
+	c.Call("drawImage", img.Value(), dx, dy)
+}
+
+// DrawBitmapScaled draws the image scaled to the width and height.
+func (c Context2D) DrawBitmapScaled(img Image, dx, dy, dw, dh float64) {
+	c.Call("drawImage", img.Value(), dx, dy, dw, dh)
+}
+
+// GetImageNRGBA copies the pixels of the rectangle into an image.
+//
+// ImageData is not alpha-premultiplied, so the bytes are copied as they are.
+func (c Context2D) GetImageNRGBA(sx, sy, sw, sh int) *image.NRGBA {
+	var img = image.NewNRGBA(image.Rect(0, 0, sw, sh))
+	js.CopyBytesToGo(img.Pix, c.Call("getImageData", sx, sy, sw, sh).Get("data"))
+	return img
+}
+
+// GetImageRGBA copies the pixels of the rectangle into an alpha-premultiplied image.
+func (c Context2D) GetImageRGBA(sx, sy, sw, sh int) *image.RGBA {
+	var img = image.NewRGBA(image.Rect(0, 0, sw, sh))
+	js.CopyBytesToGo(img.Pix, c.Call("getImageData", sx, sy, sw, sh).Get("data"))
+	for i := 0; i < len(img.Pix); i += 4 {
+		var a = uint32(img.Pix[i+3])
+		if a == 0xff {
+			continue
+		}
+		img.Pix[i] = uint8(uint32(img.Pix[i]) * a / 0xff)
+		img.Pix[i+1] = uint8(uint32(img.Pix[i+1]) * a / 0xff)
+		img.Pix[i+2] = uint8(uint32(img.Pix[i+2]) * a / 0xff)
+	}
+	return img
+}
+
+// PutImageNRGBA writes the pixels of the image to the context, with its top left corner at dx, dy.
+func (c Context2D) PutImageNRGBA(img *image.NRGBA, dx, dy int) {
+	c.putPixels(packed(img.Pix, img.Stride, img.Rect), img.Rect.Dx(), img.Rect.Dy(), dx, dy)
+}
+
+// PutImageRGBA writes the pixels of an alpha-premultiplied image to the context.
+func (c Context2D) PutImageRGBA(img *image.RGBA, dx, dy int) {
+	if img.Rect.Empty() {
+		return
+	}
+	var pix = packed(img.Pix, img.Stride, img.Rect)
+	if &pix[0] == &img.Pix[0] {
+		// The pixels are converted in place, so they must not be shared with the image.
+		pix = append([]byte(nil), pix...)
+	}
+	unpremultiply(pix)
+	c.putPixels(pix, img.Rect.Dx(), img.Rect.Dy(), dx, dy)
+}
+
+// unpremultiply converts alpha-premultiplied RGBA pixels to NRGBA in place.
+//
+// Channels larger than the alpha are not valid premultiplied colors, they are clamped to 0xff.
+func unpremultiply(pix []byte) {
+	for i := 0; i+3 < len(pix); i += 4 {
+		var a = uint32(pix[i+3])
+		if a == 0xff || a == 0 {
+			continue
+		}
+		for j := i; j < i+3; j++ {
+			var v = uint32(pix[j]) * 0xff / a
+			if v > 0xff {
+				v = 0xff
+			}
+			pix[j] = uint8(v)
+		}
+	}
+}
+
+// PutImage writes any image to the context, it is converted to NRGBA first if needed.
+func (c Context2D) PutImage(img image.Image, dx, dy int) {
+	switch img := img.(type) {
+	case *image.NRGBA:
+		c.PutImageNRGBA(img, dx, dy)
+	case *image.RGBA:
+		c.PutImageRGBA(img, dx, dy)
+	default:
+		var b = img.Bounds()
+		var n = image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(n, n.Rect, img, b.Min, draw.Src)
+		c.PutImageNRGBA(n, dx, dy)
+	}
+}
+
+func (c Context2D) putPixels(pix []byte, w, h, dx, dy int) {
+	if w == 0 || h == 0 {
+		return
+	}
+	var data = js.Global().Get("Uint8ClampedArray").New(len(pix))
+	js.CopyBytesToJS(data, pix)
+	c.Call("putImageData", js.Global().Get("ImageData").New(data, w, h), dx, dy)
+}
+
+// packed returns the pixels of the rectangle without padding between rows.
+func packed(pix []byte, stride int, r image.Rectangle) []byte {
+	var row = r.Dx() * 4
+	if stride == row && len(pix) == row*r.Dy() {
+		return pix
+	}
+	var out = make([]byte, 0, row*r.Dy())
+	for y := 0; y < r.Dy(); y++ {
+		out = append(out, pix[y*stride:y*stride+row]...)
+	}
+	return out
+}
@@ -0,0 +1,136 @@
+//go:build js && wasm
+// +build js,wasm
+
+package context
+
+import (
+	"syscall/js"
+)
+
+// Opcodes of the command buffer.
+const (
+	opMoveTo byte = iota + 1
+	opLineTo
+	opBezierCurveTo
+	opQuadraticCurveTo
+	opArc
+	opArcTo
+	opEllipse
+	opRect
+	opRoundRect
+	opClosePath
+	opBeginPath
+	opFill
+	opStroke
+	opClip
+	opFillRect
+	opStrokeRect
+	opClearRect
+	opFillText
+	opStrokeText
+	opSave
+	opRestore
+	opTranslate
+	opRotate
+	opScale
+	opTransform
+	opSetTransform
+	opResetTransform
+	opSetLineDash
+	opSet
+	opCall
+	opTarget
+	opNewPath
+	opAddPath
+	opFreePath
+)
+
+// Tags of the arguments of opSet and opCall.
+const (
+	argNumber byte = iota
+	argString
+	argTrue
+	argFalse
+	argObject
+	argPath
+	argUndefined
+)
+
+// replaySource interprets a command buffer, the function is created once and shared by all batches.
+//
+// Paths are kept between calls, they are addressed by their id; id 0 is the context.
+const replaySource = `
+const paths = [];
+const decoder = new TextDecoder();
+return function(ctx, buf, n, objs) {
+	const v = new DataView(buf.buffer, buf.byteOffset, n);
+	let p = 0, t = ctx;
+	const f = () => { const x = v.getFloat64(p, true); p += 8; return x; };
+	const u = () => { const x = v.getUint32(p, true); p += 4; return x; };
+	const s = () => { const l = u(); const x = decoder.decode(buf.subarray(p, p + l)); p += l; return x; };
+	const b = () => v.getUint8(p++) === 1;
+	const a = () => {
+		switch (v.getUint8(p++)) {
+		case 0: return f();
+		case 1: return s();
+		case 2: return true;
+		case 3: return false;
+		case 4: return objs[u()];
+		case 5: return paths[u()];
+		}
+		return undefined;
+	};
+	const path = () => { const id = u(); return id === 0 ? undefined : paths[id]; };
+	const rule = () => { const r = s(); return r === "" ? undefined : r; };
+	const draw = (m) => { const pa = path(), r = rule(); if (pa) { r ? ctx[m](pa, r) : ctx[m](pa); } else { r ? ctx[m](r) : ctx[m](); } };
+	while (p < n) {
+		switch (v.getUint8(p++)) {
+		case 1: t.moveTo(f(), f()); break;
+		case 2: t.lineTo(f(), f()); break;
+		case 3: t.bezierCurveTo(f(), f(), f(), f(), f(), f()); break;
+		case 4: t.quadraticCurveTo(f(), f(), f(), f()); break;
+		case 5: t.arc(f(), f(), f(), f(), f(), b()); break;
+		case 6: t.arcTo(f(), f(), f(), f(), f()); break;
+		case 7: t.ellipse(f(), f(), f(), f(), f(), f(), f(), b()); break;
+		case 8: t.rect(f(), f(), f(), f()); break;
+		case 9: t.roundRect(f(), f(), f(), f(), f()); break;
+		case 10: t.closePath(); break;
+		case 11: ctx.beginPath(); break;
+		case 12: draw("fill"); break;
+		case 13: draw("stroke"); break;
+		case 14: draw("clip"); break;
+		case 15: ctx.fillRect(f(), f(), f(), f()); break;
+		case 16: ctx.strokeRect(f(), f(), f(), f()); break;
+		case 17: ctx.clearRect(f(), f(), f(), f()); break;
+		case 18: { const x = s(), y = f(), z = f(), w = f(); w >= 0 ? ctx.fillText(x, y, z, w) : ctx.fillText(x, y, z); break; }
+		case 19: { const x = s(), y = f(), z = f(), w = f(); w >= 0 ? ctx.strokeText(x, y, z, w) : ctx.strokeText(x, y, z); break; }
+		case 20: ctx.save(); break;
+		case 21: ctx.restore(); break;
+		case 22: ctx.translate(f(), f()); break;
+		case 23: ctx.rotate(f()); break;
+		case 24: ctx.scale(f(), f()); break;
+		case 25: ctx.transform(f(), f(), f(), f(), f(), f()); break;
+		case 26: ctx.setTransform(f(), f(), f(), f(), f(), f()); break;
+		case 27: ctx.resetTransform(); break;
+		case 28: { const l = u(), d = []; for (let i = 0; i < l; i++) d.push(f()); ctx.setLineDash(d); break; }
+		case 29: { const k = s(); ctx[k] = a(); break; }
+		case 30: { const k = s(), l = v.getUint8(p++), args = []; for (let i = 0; i < l; i++) args.push(a()); ctx[k](...args); break; }
+		case 31: { const id = u(); t = id === 0 ? ctx : paths[id]; break; }
+		case 32: paths[u()] = new Path2D(); break;
+		case 33: { const to = paths[u()], from = paths[u()]; to.addPath(from); break; }
+		case 34: delete paths[u()]; break;
+		default: throw new Error("jsext: invalid canvas command at " + (p - 1));
+		}
+	}
+};
+`
+
+var replayFunc js.Value
+
+// replay returns the interpreter of command buffers.
+func replay() js.Value {
+	if replayFunc.IsUndefined() {
+		replayFunc = js.Global().Get("Function").New(replaySource).Invoke()
+	}
+	return replayFunc
+}
@@ -0,0 +1,269 @@
+//go:build js && wasm
+// +build js,wasm
+
+package canvas
+
+import (
+	"math"
+	"syscall/js"
+	"time"
+)
+
+type LoopOptions struct {
+	// Step is the fixed time between updates, it defaults to 1/60th of a second.
+	Step time.Duration
+
+	// MaxFrameTime limits the time simulated in a single frame,
+	// so a slow frame does not cause a spiral of updates. It defaults to 250ms.
+	MaxFrameTime time.Duration
+
+	// RunHidden keeps the loop running while the tab is hidden.
+	RunHidden bool
+
+	// NoResize disables resizing the canvas to its displayed size times the devicePixelRatio.
+	NoResize bool
+
+	// OnResize is called after the canvas was resized, with the new size in device pixels.
+	OnResize func(width, height int, pixelRatio float64)
+}
+
+func (o *LoopOptions) Defaults() {
+	if o.Step <= 0 {
+		o.Step = time.Second / 60
+	}
+	if o.MaxFrameTime <= 0 {
+		o.MaxFrameTime = 250 * time.Millisecond
+	}
+}
+
+// LoopStats are the statistics of a running loop.
+type LoopStats struct {
+	// FPS is the number of frames rendered during the last second.
+	FPS float64
+
+	// FrameTime is the time between the last two frames.
+	FrameTime time.Duration
+
+	// AvgFrameTime is a moving average of the frame time.
+	AvgFrameTime time.Duration
+
+	// MaxFrameTime is the longest frame time during the last second.
+	MaxFrameTime time.Duration
+
+	// Frames and Updates are the total number of rendered frames and updates.
+	Frames  uint64
+	Updates uint64
+
+	// PixelRatio is the devicePixelRatio the canvas was last sized for.
+	PixelRatio float64
+}
+
+// Loop drives a canvas with requestAnimationFrame.
+//
+// Update is called with a fixed time step in seconds, zero or more times per frame.
+// Render is called once per frame, alpha is the fraction of a step which has not been simulated yet,
+// it can be used to interpolate between the previous and the current state.
+type Loop struct {
+	Canvas Canvas
+	Update func(dt float64)
+	Render func(alpha float64)
+
+	opts       LoopOptions
+	frame      js.Func
+	visibility js.Func
+	id         js.Value
+	running    bool
+	paused     bool
+	last       float64
+	lag        time.Duration
+	stats      LoopStats
+	window     float64
+	windowN    int
+	windowMax  time.Duration
+}
+
+// NewLoop returns a loop for the canvas, it is started with Start.
+func NewLoop(c Canvas, update func(dt float64), render func(alpha float64), opts ...LoopOptions) *Loop {
+	var l = &Loop{
+		Canvas: c,
+		Update: update,
+		Render: render,
+	}
+	if len(opts) > 0 {
+		l.opts = opts[0]
+	}
+	l.opts.Defaults()
+	return l
+}
+
+// Loop creates a loop for the canvas, see NewLoop.
+func (c Canvas) Loop(update func(dt float64), render func(alpha float64), opts ...LoopOptions) *Loop {
+	return NewLoop(c, update, render, opts...)
+}
+
+// Start starts the loop, it does nothing if the loop is already running.
+func (l *Loop) Start() *Loop {
+	if l.running {
+		return l
+	}
+	l.running = true
+	l.frame = js.FuncOf(func(this js.Value, args []js.Value) any {
+		l.tick(args[0].Float())
+		return nil
+	})
+	if !l.opts.RunHidden {
+		var document = js.Global().Get("document")
+		l.visibility = js.FuncOf(func(this js.Value, args []js.Value) any {
+			if document.Get("hidden").Bool() {
+				l.Pause()
+			} else {
+				l.Resume()
+			}
+			return nil
+		})
+		document.Call("addEventListener", "visibilitychange", l.visibility)
+		l.paused = document.Get("hidden").Bool()
+	}
+	if !l.paused {
+		l.request()
+	}
+	return l
+}
+
+// Stop stops the loop, and releases its callbacks. It can be started again.
+func (l *Loop) Stop() {
+	if !l.running {
+		return
+	}
+	l.cancel()
+	l.running = false
+	l.paused = false
+	l.frame.Release()
+	if !l.opts.RunHidden {
+		js.Global().Get("document").Call("removeEventListener", "visibilitychange", l.visibility)
+		l.visibility.Release()
+	}
+}
+
+// Pause stops requesting frames until Resume is called.
+//
+// The time spent paused is not simulated.
+func (l *Loop) Pause() {
+	if !l.running || l.paused {
+		return
+	}
+	l.paused = true
+	l.cancel()
+}
+
+func (l *Loop) Resume() {
+	if !l.running || !l.paused {
+		return
+	}
+	l.paused = false
+	l.request()
+}
+
+func (l *Loop) Running() bool {
+	return l.running
+}
+
+func (l *Loop) Paused() bool {
+	return l.paused
+}
+
+func (l *Loop) Stats() LoopStats {
+	return l.stats
+}
+
+func (l *Loop) request() {
+	// The time before the loop was started or resumed is not measured.
+	l.last = -1
+	l.window, l.windowN, l.windowMax = 0, 0, 0
+	l.id = js.Global().Call("requestAnimationFrame", l.frame)
+}
+
+func (l *Loop) cancel() {
+	if !l.id.IsUndefined() {
+		js.Global().Call("cancelAnimationFrame", l.id)
+		l.id = js.Undefined()
+	}
+}
+
+func (l *Loop) tick(now float64) {
+	l.id = js.Global().Call("requestAnimationFrame", l.frame)
+	if !l.opts.NoResize {
+		l.resize()
+	}
+
+	var elapsed time.Duration
+	if l.last >= 0 {
+		elapsed = time.Duration((now - l.last) * float64(time.Millisecond))
+	}
+	l.last = now
+	l.measure(now, elapsed)
+
+	if elapsed > l.opts.MaxFrameTime {
+		elapsed = l.opts.MaxFrameTime
+	}
+	l.lag += elapsed
+	var dt = l.opts.Step.Seconds()
+	for l.lag >= l.opts.Step {
+		if l.Update != nil {
+			l.Update(dt)
+		}
+		l.lag -= l.opts.Step
+		l.stats.Updates++
+	}
+	if l.Render != nil {
+		l.Render(float64(l.lag) / float64(l.opts.Step))
+	}
+	l.stats.Frames++
+}
+
+func (l *Loop) measure(now float64, elapsed time.Duration) {
+	if elapsed == 0 {
+		return
+	}
+	l.stats.FrameTime = elapsed
+	if l.stats.AvgFrameTime == 0 {
+		l.stats.AvgFrameTime = elapsed
+	} else {
+		l.stats.AvgFrameTime += (elapsed - l.stats.AvgFrameTime) / 10
+	}
+	if elapsed > l.windowMax {
+		l.windowMax = elapsed
+	}
+	l.windowN++
+	if l.window == 0 {
+		l.window = now
+	}
+	if now-l.window >= 1000 {
+		l.stats.FPS = float64(l.windowN) * 1000 / (now - l.window)
+		l.stats.MaxFrameTime = l.windowMax
+		l.window, l.windowN, l.windowMax = now, 0, 0
+	}
+}
+
+// resize sets the size of the canvas to its displayed size in device pixels.
+func (l *Loop) resize() {
+	var ratio = js.Global().Get("devicePixelRatio").Float()
+	if ratio <= 0 || math.IsNaN(ratio) {
+		ratio = 1
+	}
+	var v = l.Canvas.Value()
+	var width = int(math.Round(v.Get("clientWidth").Float() * ratio))
+	var height = int(math.Round(v.Get("clientHeight").Float() * ratio))
+	if width == 0 || height == 0 {
+		return
+	}
+	l.stats.PixelRatio = ratio
+	if width == v.Get("width").Int() && height == v.Get("height").Int() {
+		return
+	}
+	v.Set("width", width)
+	v.Set("height", height)
+	if l.opts.OnResize != nil {
+		l.opts.OnResize(width, height, ratio)
+	}
+}
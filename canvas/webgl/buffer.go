@@ -0,0 +1,68 @@
+//go:build js && wasm
+// +build js,wasm
+
+package webgl
+
+import (
+	"syscall/js"
+)
+
+type Buffer struct {
+	ctx    *Context
+	v      js.Value
+	Target BufferTarget
+	Usage  Usage
+	size   int
+}
+
+func (c *Context) NewBuffer(target BufferTarget, usage Usage) *Buffer {
+	return &Buffer{
+		ctx:    c,
+		v:      c.v.Call("createBuffer"),
+		Target: target,
+		Usage:  usage,
+	}
+}
+
+func (b *Buffer) Value() js.Value {
+	return b.v
+}
+
+func (b *Buffer) Bind() {
+	b.ctx.v.Call("bindBuffer", uint32(b.Target), b.v)
+}
+
+// Size returns the size of the data in bytes.
+func (b *Buffer) Size() int {
+	return b.size
+}
+
+// Data uploads the data, replacing the contents of the buffer.
+//
+// The data is a []byte, []float32, []uint16, []uint32, or any value encoding/binary can write,
+// like a slice of vertex structs.
+func (b *Buffer) Data(data any) error {
+	var bytes, err = bytesOf(data)
+	if err != nil {
+		return err
+	}
+	b.Bind()
+	b.ctx.v.Call("bufferData", uint32(b.Target), uint8Array(bytes), uint32(b.Usage))
+	b.size = len(bytes)
+	return nil
+}
+
+// SubData replaces part of the buffer, starting at offset in bytes.
+func (b *Buffer) SubData(offset int, data any) error {
+	var bytes, err = bytesOf(data)
+	if err != nil {
+		return err
+	}
+	b.Bind()
+	b.ctx.v.Call("bufferSubData", uint32(b.Target), offset, uint8Array(bytes))
+	return nil
+}
+
+func (b *Buffer) Delete() {
+	b.ctx.v.Call("deleteBuffer", b.v)
+}
@@ -0,0 +1,275 @@
+//go:build js && wasm
+// +build js,wasm
+
+package webgl
+
+// Typed WebGL2 enums, the values are the same as the constants on WebGL2RenderingContext.
+
+type ShaderType uint32
+
+const (
+	VertexShader   ShaderType = 0x8B31
+	FragmentShader ShaderType = 0x8B30
+)
+
+func (t ShaderType) String() string {
+	switch t {
+	case VertexShader:
+		return "vertex shader"
+	case FragmentShader:
+		return "fragment shader"
+	}
+	return "unknown shader"
+}
+
+type BufferTarget uint32
+
+const (
+	ArrayBuffer             BufferTarget = 0x8892
+	ElementArrayBuffer      BufferTarget = 0x8893
+	UniformBuffer           BufferTarget = 0x8A11
+	CopyReadBuffer          BufferTarget = 0x8F36
+	CopyWriteBuffer         BufferTarget = 0x8F37
+	TransformFeedbackBuffer BufferTarget = 0x8C8E
+	PixelPackBuffer         BufferTarget = 0x88EB
+	PixelUnpackBuffer       BufferTarget = 0x88EC
+)
+
+type Usage uint32
+
+const (
+	StaticDraw  Usage = 0x88E4
+	DynamicDraw Usage = 0x88E8
+	StreamDraw  Usage = 0x88E0
+	StaticRead  Usage = 0x88E5
+	DynamicRead Usage = 0x88E9
+	StreamRead  Usage = 0x88E1
+	StaticCopy  Usage = 0x88E6
+	DynamicCopy Usage = 0x88EA
+	StreamCopy  Usage = 0x88E2
+)
+
+type DataType uint32
+
+const (
+	Byte          DataType = 0x1400
+	UnsignedByte  DataType = 0x1401
+	Short         DataType = 0x1402
+	UnsignedShort DataType = 0x1403
+	Int           DataType = 0x1404
+	UnsignedInt   DataType = 0x1405
+	Float         DataType = 0x1406
+	HalfFloat     DataType = 0x140B
+)
+
+// Size returns the size of a value of the type in bytes.
+func (t DataType) Size() int {
+	switch t {
+	case Byte, UnsignedByte:
+		return 1
+	case Short, UnsignedShort, HalfFloat:
+		return 2
+	}
+	return 4
+}
+
+func (t DataType) integer() bool {
+	return t != Float && t != HalfFloat
+}
+
+type Primitive uint32
+
+const (
+	Points        Primitive = 0x0000
+	Lines         Primitive = 0x0001
+	LineLoop      Primitive = 0x0002
+	LineStrip     Primitive = 0x0003
+	Triangles     Primitive = 0x0004
+	TriangleStrip Primitive = 0x0005
+	TriangleFan   Primitive = 0x0006
+)
+
+type Capability uint32
+
+const (
+	Blend             Capability = 0x0BE2
+	CullFace          Capability = 0x0B44
+	DepthTest         Capability = 0x0B71
+	Dither            Capability = 0x0BD0
+	PolygonOffsetFill Capability = 0x8037
+	RasterizerDiscard Capability = 0x8C89
+	SampleCoverage    Capability = 0x80A0
+	ScissorTest       Capability = 0x0C11
+	StencilTest       Capability = 0x0B90
+)
+
+type ClearMask uint32
+
+const (
+	ColorBufferBit   ClearMask = 0x4000
+	DepthBufferBit   ClearMask = 0x0100
+	StencilBufferBit ClearMask = 0x0400
+)
+
+type BlendFactor uint32
+
+const (
+	Zero                  BlendFactor = 0
+	One                   BlendFactor = 1
+	SrcColor              BlendFactor = 0x0300
+	OneMinusSrcColor      BlendFactor = 0x0301
+	SrcAlpha              BlendFactor = 0x0302
+	OneMinusSrcAlpha      BlendFactor = 0x0303
+	DstAlpha              BlendFactor = 0x0304
+	OneMinusDstAlpha      BlendFactor = 0x0305
+	DstColor              BlendFactor = 0x0306
+	OneMinusDstColor      BlendFactor = 0x0307
+	SrcAlphaSaturate      BlendFactor = 0x0308
+	ConstantColor         BlendFactor = 0x8001
+	OneMinusConstantColor BlendFactor = 0x8002
+	ConstantAlpha         BlendFactor = 0x8003
+	OneMinusConstantAlpha BlendFactor = 0x8004
+)
+
+type CompareFunc uint32
+
+const (
+	Never    CompareFunc = 0x0200
+	Less     CompareFunc = 0x0201
+	Equal    CompareFunc = 0x0202
+	LEqual   CompareFunc = 0x0203
+	Greater  CompareFunc = 0x0204
+	NotEqual CompareFunc = 0x0205
+	GEqual   CompareFunc = 0x0206
+	Always   CompareFunc = 0x0207
+)
+
+type Face uint32
+
+const (
+	Front        Face = 0x0404
+	Back         Face = 0x0405
+	FrontAndBack Face = 0x0408
+)
+
+type TextureTarget uint32
+
+const (
+	Texture2D      TextureTarget = 0x0DE1
+	TextureCubeMap TextureTarget = 0x8513
+	Texture3D      TextureTarget = 0x806F
+	Texture2DArray TextureTarget = 0x8C1A
+)
+
+// TextureFormat is an internal or pixel format of a texture or renderbuffer.
+type TextureFormat uint32
+
+const (
+	Red              TextureFormat = 0x1903
+	RG               TextureFormat = 0x8227
+	RGB              TextureFormat = 0x1907
+	RGBA             TextureFormat = 0x1908
+	R8               TextureFormat = 0x8229
+	RG8              TextureFormat = 0x822B
+	RGB8             TextureFormat = 0x8051
+	RGBA8            TextureFormat = 0x8058
+	SRGB8Alpha8      TextureFormat = 0x8C43
+	R16F             TextureFormat = 0x822D
+	RGBA16F          TextureFormat = 0x881A
+	R32F             TextureFormat = 0x822E
+	RGBA32F          TextureFormat = 0x8814
+	DepthComponent   TextureFormat = 0x1902
+	DepthStencil     TextureFormat = 0x84F9
+	DepthComponent16 TextureFormat = 0x81A5
+	DepthComponent24 TextureFormat = 0x81A6
+	DepthComponent32 TextureFormat = 0x8CAC
+	Depth24Stencil8  TextureFormat = 0x88F0
+)
+
+type Filter uint32
+
+const (
+	Nearest              Filter = 0x2600
+	Linear               Filter = 0x2601
+	NearestMipmapNearest Filter = 0x2700
+	LinearMipmapNearest  Filter = 0x2701
+	NearestMipmapLinear  Filter = 0x2702
+	LinearMipmapLinear   Filter = 0x2703
+)
+
+type Wrap uint32
+
+const (
+	Repeat         Wrap = 0x2901
+	ClampToEdge    Wrap = 0x812F
+	MirroredRepeat Wrap = 0x8370
+)
+
+type FramebufferTarget uint32
+
+const (
+	FramebufferAll  FramebufferTarget = 0x8D40
+	ReadFramebuffer FramebufferTarget = 0x8CA8
+	DrawFramebuffer FramebufferTarget = 0x8CA9
+)
+
+type Attachment uint32
+
+const (
+	ColorAttachment0       Attachment = 0x8CE0
+	DepthAttachment        Attachment = 0x8D00
+	StencilAttachment      Attachment = 0x8D20
+	DepthStencilAttachment Attachment = 0x821A
+)
+
+// ColorAttachment returns the i-th color attachment.
+func ColorAttachment(i int) Attachment {
+	return ColorAttachment0 + Attachment(i)
+}
+
+// Error is a value returned by getError.
+type Error uint32
+
+const (
+	NoError                     Error = 0
+	InvalidEnum                 Error = 0x0500
+	InvalidValue                Error = 0x0501
+	InvalidOperation            Error = 0x0502
+	OutOfMemory                 Error = 0x0505
+	InvalidFramebufferOperation Error = 0x0506
+	ContextLostWebGL            Error = 0x9242
+)
+
+func (e Error) Error() string {
+	switch e {
+	case NoError:
+		return "webgl: no error"
+	case InvalidEnum:
+		return "webgl: invalid enum"
+	case InvalidValue:
+		return "webgl: invalid value"
+	case InvalidOperation:
+		return "webgl: invalid operation"
+	case OutOfMemory:
+		return "webgl: out of memory"
+	case InvalidFramebufferOperation:
+		return "webgl: invalid framebuffer operation"
+	case ContextLostWebGL:
+		return "webgl: context lost"
+	}
+	return "webgl: unknown error"
+}
+
+// Parameters which are only used internally.
+const (
+	compileStatus       = 0x8B81
+	linkStatus          = 0x8B82
+	texture0            = 0x84C0
+	textureMagFilter    = 0x2800
+	textureMinFilter    = 0x2801
+	textureWrapS        = 0x2802
+	textureWrapT        = 0x2803
+	renderbuffer        = 0x8D41
+	framebufferComplete = 0x8CD5
+	unpackFlipY         = 0x9240
+)
@@ -0,0 +1,69 @@
+//go:build js && wasm
+// +build js,wasm
+
+package webgl
+
+import (
+	"fmt"
+	"syscall/js"
+)
+
+type Framebuffer struct {
+	ctx           *Context
+	v             js.Value
+	renderbuffers []js.Value
+}
+
+func (c *Context) NewFramebuffer() *Framebuffer {
+	return &Framebuffer{ctx: c, v: c.v.Call("createFramebuffer")}
+}
+
+func (f *Framebuffer) Value() js.Value {
+	return f.v
+}
+
+// Bind makes draw calls render to the framebuffer.
+func (f *Framebuffer) Bind() {
+	f.ctx.v.Call("bindFramebuffer", uint32(FramebufferAll), f.v)
+}
+
+// BindDefaultFramebuffer makes draw calls render to the canvas again.
+func (c *Context) BindDefaultFramebuffer() {
+	c.v.Call("bindFramebuffer", uint32(FramebufferAll), nil)
+}
+
+// AttachTexture attaches level 0 of a 2D texture.
+func (f *Framebuffer) AttachTexture(attachment Attachment, t *Texture) {
+	f.Bind()
+	f.ctx.v.Call("framebufferTexture2D", uint32(FramebufferAll), uint32(attachment), uint32(t.Target), t.v, 0)
+}
+
+// AttachRenderbuffer creates a renderbuffer and attaches it, for example a depth buffer with DepthComponent24.
+//
+// The renderbuffer is deleted with the framebuffer.
+func (f *Framebuffer) AttachRenderbuffer(attachment Attachment, format TextureFormat, width, height int) {
+	var gl = f.ctx.v
+	var rb = gl.Call("createRenderbuffer")
+	gl.Call("bindRenderbuffer", renderbuffer, rb)
+	gl.Call("renderbufferStorage", renderbuffer, uint32(format), width, height)
+	f.Bind()
+	gl.Call("framebufferRenderbuffer", uint32(FramebufferAll), uint32(attachment), renderbuffer, rb)
+	f.renderbuffers = append(f.renderbuffers, rb)
+}
+
+// Check returns an error if the framebuffer can not be rendered to.
+func (f *Framebuffer) Check() error {
+	f.Bind()
+	var status = f.ctx.v.Call("checkFramebufferStatus", uint32(FramebufferAll)).Int()
+	if status != framebufferComplete {
+		return fmt.Errorf("%w: status 0x%X", ErrIncompleteFramebuffer, status)
+	}
+	return nil
+}
+
+func (f *Framebuffer) Delete() {
+	for _, rb := range f.renderbuffers {
+		f.ctx.v.Call("deleteRenderbuffer", rb)
+	}
+	f.ctx.v.Call("deleteFramebuffer", f.v)
+}
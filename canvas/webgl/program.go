@@ -0,0 +1,196 @@
+//go:build js && wasm
+// +build js,wasm
+
+package webgl
+
+import (
+	"fmt"
+	"strings"
+	"syscall/js"
+)
+
+type Shader struct {
+	ctx  *Context
+	v    js.Value
+	Type ShaderType
+}
+
+// NewShader compiles a shader, the error contains the info log if compiling failed.
+func (c *Context) NewShader(typ ShaderType, source string) (*Shader, error) {
+	var v = c.v.Call("createShader", uint32(typ))
+	c.v.Call("shaderSource", v, source)
+	c.v.Call("compileShader", v)
+	if !c.v.Call("getShaderParameter", v, compileStatus).Bool() {
+		var log = strings.TrimSpace(c.v.Call("getShaderInfoLog", v).String())
+		c.v.Call("deleteShader", v)
+		return nil, fmt.Errorf("%w: %s: %s", ErrCompile, typ, log)
+	}
+	return &Shader{ctx: c, v: v, Type: typ}, nil
+}
+
+func (s *Shader) Value() js.Value {
+	return s.v
+}
+
+func (s *Shader) Delete() {
+	s.ctx.v.Call("deleteShader", s.v)
+}
+
+// Program is a linked program, locations of uniforms and attributes are cached by name.
+type Program struct {
+	ctx      *Context
+	v        js.Value
+	uniforms map[string]js.Value
+	attribs  map[string]int
+}
+
+// NewProgram compiles the shaders and links them, the shaders are deleted afterwards.
+func (c *Context) NewProgram(vertexSource, fragmentSource string) (*Program, error) {
+	var vs, err = c.NewShader(VertexShader, vertexSource)
+	if err != nil {
+		return nil, err
+	}
+	defer vs.Delete()
+	fs, err := c.NewShader(FragmentShader, fragmentSource)
+	if err != nil {
+		return nil, err
+	}
+	defer fs.Delete()
+	return c.Link(vs, fs)
+}
+
+// Link links the shaders into a program, the error contains the info log if linking failed.
+func (c *Context) Link(shaders ...*Shader) (*Program, error) {
+	var v = c.v.Call("createProgram")
+	for _, s := range shaders {
+		c.v.Call("attachShader", v, s.v)
+	}
+	c.v.Call("linkProgram", v)
+	if !c.v.Call("getProgramParameter", v, linkStatus).Bool() {
+		var log = strings.TrimSpace(c.v.Call("getProgramInfoLog", v).String())
+		c.v.Call("deleteProgram", v)
+		return nil, fmt.Errorf("%w: %s", ErrLink, log)
+	}
+	for _, s := range shaders {
+		c.v.Call("detachShader", v, s.v)
+	}
+	return &Program{
+		ctx:      c,
+		v:        v,
+		uniforms: make(map[string]js.Value),
+		attribs:  make(map[string]int),
+	}, nil
+}
+
+func (p *Program) Value() js.Value {
+	return p.v
+}
+
+func (p *Program) Use() {
+	p.ctx.use(p)
+}
+
+func (p *Program) Delete() {
+	if p.ctx.program == p {
+		p.ctx.program = nil
+	}
+	p.ctx.v.Call("deleteProgram", p.v)
+}
+
+// AttribLocation returns the location of an attribute, or -1 if the program does not use it.
+func (p *Program) AttribLocation(name string) int {
+	if l, ok := p.attribs[name]; ok {
+		return l
+	}
+	var l = p.ctx.v.Call("getAttribLocation", p.v, name).Int()
+	p.attribs[name] = l
+	return l
+}
+
+// UniformLocation returns the location of a uniform, it is null if the program does not use it.
+func (p *Program) UniformLocation(name string) js.Value {
+	if l, ok := p.uniforms[name]; ok {
+		return l
+	}
+	var l = p.ctx.v.Call("getUniformLocation", p.v, name)
+	p.uniforms[name] = l
+	return l
+}
+
+// uniform makes the program current and calls the setter, uniforms the program does not use are ignored.
+func (p *Program) uniform(method, name string, args ...any) {
+	var l = p.UniformLocation(name)
+	if l.IsNull() {
+		return
+	}
+	p.ctx.use(p)
+	p.ctx.v.Call(method, append([]any{l}, args...)...)
+}
+
+func (p *Program) Uniform1f(name string, x float32) {
+	p.uniform("uniform1f", name, x)
+}
+
+func (p *Program) Uniform2f(name string, x, y float32) {
+	p.uniform("uniform2f", name, x, y)
+}
+
+func (p *Program) Uniform3f(name string, x, y, z float32) {
+	p.uniform("uniform3f", name, x, y, z)
+}
+
+func (p *Program) Uniform4f(name string, x, y, z, w float32) {
+	p.uniform("uniform4f", name, x, y, z, w)
+}
+
+// Uniform1i sets an int uniform, or the texture unit of a sampler.
+func (p *Program) Uniform1i(name string, x int32) {
+	p.uniform("uniform1i", name, x)
+}
+
+func (p *Program) Uniform2i(name string, x, y int32) {
+	p.uniform("uniform2i", name, x, y)
+}
+
+func (p *Program) Uniform3i(name string, x, y, z int32) {
+	p.uniform("uniform3i", name, x, y, z)
+}
+
+func (p *Program) Uniform4i(name string, x, y, z, w int32) {
+	p.uniform("uniform4i", name, x, y, z, w)
+}
+
+func (p *Program) Uniform1fv(name string, values []float32) {
+	p.uniform("uniform1fv", name, float32Array(values))
+}
+
+func (p *Program) Uniform2fv(name string, values []float32) {
+	p.uniform("uniform2fv", name, float32Array(values))
+}
+
+func (p *Program) Uniform3fv(name string, values []float32) {
+	p.uniform("uniform3fv", name, float32Array(values))
+}
+
+func (p *Program) Uniform4fv(name string, values []float32) {
+	p.uniform("uniform4fv", name, float32Array(values))
+}
+
+func (p *Program) Uniform1iv(name string, values []int32) {
+	p.uniform("uniform1iv", name, int32Array(values))
+}
+
+// UniformMatrix2f sets a mat2 uniform, the matrix is in column-major order.
+func (p *Program) UniformMatrix2f(name string, m [4]float32) {
+	p.uniform("uniformMatrix2fv", name, false, float32Array(m[:]))
+}
+
+// UniformMatrix3f sets a mat3 uniform, the matrix is in column-major order.
+func (p *Program) UniformMatrix3f(name string, m [9]float32) {
+	p.uniform("uniformMatrix3fv", name, false, float32Array(m[:]))
+}
+
+// UniformMatrix4f sets a mat4 uniform, the matrix is in column-major order.
+func (p *Program) UniformMatrix4f(name string, m [16]float32) {
+	p.uniform("uniformMatrix4fv", name, false, float32Array(m[:]))
+}
@@ -0,0 +1,97 @@
+//go:build js && wasm
+// +build js,wasm
+
+package webgl
+
+import (
+	"image"
+	"syscall/js"
+)
+
+type Texture struct {
+	ctx    *Context
+	v      js.Value
+	Target TextureTarget
+	Width  int
+	Height int
+}
+
+// NewTexture2D creates a 2D texture with linear filtering, clamped to the edges.
+func (c *Context) NewTexture2D() *Texture {
+	var t = &Texture{ctx: c, v: c.v.Call("createTexture"), Target: Texture2D}
+	t.SetFilter(Linear, Linear)
+	t.SetWrap(ClampToEdge, ClampToEdge)
+	return t
+}
+
+func (t *Texture) Value() js.Value {
+	return t.v
+}
+
+// Bind binds the texture to a texture unit, the unit is the value of the sampler uniform.
+func (t *Texture) Bind(unit int) {
+	t.ctx.v.Call("activeTexture", texture0+unit)
+	t.ctx.v.Call("bindTexture", uint32(t.Target), t.v)
+}
+
+func (t *Texture) bind() {
+	t.ctx.v.Call("bindTexture", uint32(t.Target), t.v)
+}
+
+func (t *Texture) SetFilter(min, mag Filter) {
+	t.bind()
+	t.ctx.v.Call("texParameteri", uint32(t.Target), textureMinFilter, uint32(min))
+	t.ctx.v.Call("texParameteri", uint32(t.Target), textureMagFilter, uint32(mag))
+}
+
+func (t *Texture) SetWrap(s, tw Wrap) {
+	t.bind()
+	t.ctx.v.Call("texParameteri", uint32(t.Target), textureWrapS, uint32(s))
+	t.ctx.v.Call("texParameteri", uint32(t.Target), textureWrapT, uint32(tw))
+}
+
+// Image uploads an ImageBitmap, image, video or canvas element.
+//
+// Set flipY to put the first row of the image at the bottom, where texture coordinates start.
+func (t *Texture) Image(source js.Value, flipY bool) {
+	t.bind()
+	t.ctx.v.Call("pixelStorei", unpackFlipY, flipY)
+	t.ctx.v.Call("texImage2D", uint32(t.Target), 0, uint32(RGBA), uint32(RGBA), uint32(UnsignedByte), source)
+	t.ctx.v.Call("pixelStorei", unpackFlipY, false)
+	t.Width, t.Height = source.Get("width").Int(), source.Get("height").Int()
+}
+
+// RGBA uploads the pixels of an image, rows are copied as they are.
+func (t *Texture) RGBA(img *image.RGBA) {
+	var w, h = img.Rect.Dx(), img.Rect.Dy()
+	var pix = img.Pix
+	if img.Stride != 4*w {
+		pix = make([]byte, 0, 4*w*h)
+		for y := 0; y < h; y++ {
+			pix = append(pix, img.Pix[y*img.Stride:y*img.Stride+4*w]...)
+		}
+	}
+	t.bind()
+	t.ctx.v.Call("texImage2D", uint32(t.Target), 0, uint32(RGBA8), w, h, 0, uint32(RGBA), uint32(UnsignedByte), uint8Array(pix[:4*w*h]))
+	t.Width, t.Height = w, h
+}
+
+// Storage allocates an empty texture, for example to render to with a framebuffer.
+func (t *Texture) Storage(width, height int, format TextureFormat, levels ...int) {
+	var l = 1
+	if len(levels) > 0 {
+		l = levels[0]
+	}
+	t.bind()
+	t.ctx.v.Call("texStorage2D", uint32(t.Target), l, uint32(format), width, height)
+	t.Width, t.Height = width, height
+}
+
+func (t *Texture) GenerateMipmap() {
+	t.bind()
+	t.ctx.v.Call("generateMipmap", uint32(t.Target))
+}
+
+func (t *Texture) Delete() {
+	t.ctx.v.Call("deleteTexture", t.v)
+}
@@ -0,0 +1,164 @@
+//go:build js && wasm
+// +build js,wasm
+
+package webgl
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"syscall/js"
+)
+
+// Attribute describes one vertex attribute in a buffer.
+type Attribute struct {
+	Name string
+	// Size is the number of components, 1 to 4.
+	Size int
+	Type DataType
+	// Normalized maps integers to 0..1 or -1..1, instead of passing them as integers.
+	Normalized bool
+	// Offset is in bytes from the start of the vertex.
+	Offset int
+	// Divisor is the number of instances which share a value, 0 is once per vertex.
+	Divisor int
+}
+
+// Layout describes the attributes of interleaved vertices.
+type Layout struct {
+	Stride     int
+	Attributes []Attribute
+}
+
+// LayoutOf describes the layout of a vertex struct.
+//
+// Fields are numbers, or arrays of 2 to 4 numbers. The tag `gl:"a_name,normalized,divisor=1"` sets the
+// attribute name (the field name by default) and options; fields tagged `gl:"-"` and blank fields are skipped,
+// but still take up space. The struct is packed like encoding/binary writes it, so it can be passed to Buffer.Data.
+func LayoutOf[T any]() Layout {
+	var l, err = layoutOf(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+func layoutOf(t reflect.Type) (Layout, error) {
+	if t.Kind() != reflect.Struct {
+		return Layout{}, fmt.Errorf("%w: %s is not a struct", ErrUnsupportedLayoutField, t)
+	}
+	var l Layout
+	for i := 0; i < t.NumField(); i++ {
+		var f = t.Field(i)
+		var typ, size, ok = fieldType(f.Type)
+		if !ok {
+			return Layout{}, fmt.Errorf("%w: %s.%s", ErrUnsupportedLayoutField, t, f.Name)
+		}
+		var offset = l.Stride
+		l.Stride += typ.Size() * size
+
+		var tag = f.Tag.Get("gl")
+		if tag == "-" || f.Name == "_" {
+			continue
+		}
+		var a = Attribute{Name: f.Name, Size: size, Type: typ, Offset: offset}
+		var parts = strings.Split(tag, ",")
+		if parts[0] != "" {
+			a.Name = parts[0]
+		}
+		for _, opt := range parts[1:] {
+			switch {
+			case opt == "normalized":
+				a.Normalized = true
+			case strings.HasPrefix(opt, "divisor="):
+				fmt.Sscan(strings.TrimPrefix(opt, "divisor="), &a.Divisor)
+			}
+		}
+		l.Attributes = append(l.Attributes, a)
+	}
+	return l, nil
+}
+
+func fieldType(t reflect.Type) (DataType, int, bool) {
+	var size = 1
+	if t.Kind() == reflect.Array {
+		size = t.Len()
+		t = t.Elem()
+		if size < 1 || size > 4 {
+			return 0, 0, false
+		}
+	}
+	switch t.Kind() {
+	case reflect.Float32:
+		return Float, size, true
+	case reflect.Int8:
+		return Byte, size, true
+	case reflect.Uint8:
+		return UnsignedByte, size, true
+	case reflect.Int16:
+		return Short, size, true
+	case reflect.Uint16:
+		return UnsignedShort, size, true
+	case reflect.Int32:
+		return Int, size, true
+	case reflect.Uint32:
+		return UnsignedInt, size, true
+	}
+	return 0, 0, false
+}
+
+type VertexArray struct {
+	ctx *Context
+	v   js.Value
+}
+
+func (c *Context) NewVertexArray() *VertexArray {
+	return &VertexArray{ctx: c, v: c.v.Call("createVertexArray")}
+}
+
+func (a *VertexArray) Value() js.Value {
+	return a.v
+}
+
+func (a *VertexArray) Bind() {
+	a.ctx.v.Call("bindVertexArray", a.v)
+}
+
+func (a *VertexArray) Unbind() {
+	a.ctx.v.Call("bindVertexArray", nil)
+}
+
+// Attributes points the attributes of the program at the buffer, as described by the layout.
+//
+// Attributes which the program does not use are skipped. Integer attributes which are not normalized
+// are passed to the shader as integers, so they must be declared as int or uint vectors.
+func (a *VertexArray) Attributes(program *Program, buffer *Buffer, layout Layout) {
+	var gl = a.ctx.v
+	a.Bind()
+	buffer.Bind()
+	for _, attr := range layout.Attributes {
+		var location = program.AttribLocation(attr.Name)
+		if location < 0 {
+			continue
+		}
+		gl.Call("enableVertexAttribArray", location)
+		if attr.Type.integer() && !attr.Normalized {
+			gl.Call("vertexAttribIPointer", location, attr.Size, uint32(attr.Type), layout.Stride, attr.Offset)
+		} else {
+			gl.Call("vertexAttribPointer", location, attr.Size, uint32(attr.Type), attr.Normalized, layout.Stride, attr.Offset)
+		}
+		gl.Call("vertexAttribDivisor", location, attr.Divisor)
+	}
+	a.Unbind()
+}
+
+// Elements binds an element array buffer to the vertex array, for DrawElements.
+func (a *VertexArray) Elements(buffer *Buffer) {
+	a.Bind()
+	a.ctx.v.Call("bindBuffer", uint32(ElementArrayBuffer), buffer.v)
+	a.Unbind()
+}
+
+func (a *VertexArray) Delete() {
+	a.ctx.v.Call("deleteVertexArray", a.v)
+}
@@ -0,0 +1,218 @@
+//go:build js && wasm
+// +build js,wasm
+
+// Package webgl is a typed layer over WebGL2.
+//
+// Shaders, programs, buffers, textures, vertex arrays and framebuffers are Go objects,
+// enums are typed constants, and compile or link failures are returned as errors with the info log.
+//
+//	gl, err := canvas.FromQuerySelector("#gl").WebGL2()
+//	program, err := gl.NewProgram(vertexSource, fragmentSource)
+//
+//	type Vertex struct {
+//		Position [2]float32 `gl:"a_position"`
+//		Color    [4]uint8   `gl:"a_color,normalized"`
+//	}
+//
+//	var vbo = gl.NewBuffer(webgl.ArrayBuffer, webgl.StaticDraw)
+//	vbo.Data(vertices)
+//	var vao = gl.NewVertexArray()
+//	vao.Attributes(program, vbo, webgl.LayoutOf[Vertex]())
+//
+//	program.Uniform1f("u_time", t)
+//	vao.Bind()
+//	gl.DrawArrays(webgl.Triangles, 0, len(vertices))
+package webgl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"syscall/js"
+
+	"github.com/Nigel2392/jsext/v2/errs"
+)
+
+const (
+	ErrNotSupported           errs.Error = "webgl: WebGL2 is not supported"
+	ErrCompile                errs.Error = "webgl: shader failed to compile"
+	ErrLink                   errs.Error = "webgl: program failed to link"
+	ErrIncompleteFramebuffer  errs.Error = "webgl: framebuffer is incomplete"
+	ErrUnsupportedData        errs.Error = "webgl: unsupported data type"
+	ErrUnsupportedLayoutField errs.Error = "webgl: unsupported vertex layout field"
+)
+
+// Context is a WebGL2RenderingContext.
+type Context struct {
+	v       js.Value
+	program *Program
+}
+
+// New returns the WebGL2 context of the canvas element, attributes are the WebGLContextAttributes.
+func New(canvas js.Value, attributes ...map[string]any) (*Context, error) {
+	var v js.Value
+	if len(attributes) > 0 {
+		v = canvas.Call("getContext", "webgl2", attributes[0])
+	} else {
+		v = canvas.Call("getContext", "webgl2")
+	}
+	if v.IsNull() || v.IsUndefined() {
+		return nil, ErrNotSupported
+	}
+	return &Context{v: v}, nil
+}
+
+func (c *Context) Value() js.Value {
+	return c.v
+}
+
+func (c *Context) Call(method string, args ...interface{}) js.Value {
+	return c.v.Call(method, args...)
+}
+
+func (c *Context) DrawingBufferWidth() int {
+	return c.v.Get("drawingBufferWidth").Int()
+}
+
+func (c *Context) DrawingBufferHeight() int {
+	return c.v.Get("drawingBufferHeight").Int()
+}
+
+func (c *Context) IsContextLost() bool {
+	return c.v.Call("isContextLost").Bool()
+}
+
+// Err returns the first error flag which is set, or nil.
+func (c *Context) Err() error {
+	var e = Error(c.v.Call("getError").Int())
+	if e == NoError {
+		return nil
+	}
+	return e
+}
+
+func (c *Context) Viewport(x, y, width, height int) {
+	c.v.Call("viewport", x, y, width, height)
+}
+
+func (c *Context) Scissor(x, y, width, height int) {
+	c.v.Call("scissor", x, y, width, height)
+}
+
+func (c *Context) ClearColor(r, g, b, a float32) {
+	c.v.Call("clearColor", r, g, b, a)
+}
+
+func (c *Context) ClearDepth(depth float32) {
+	c.v.Call("clearDepth", depth)
+}
+
+func (c *Context) Clear(mask ClearMask) {
+	c.v.Call("clear", uint32(mask))
+}
+
+func (c *Context) Enable(caps ...Capability) {
+	for _, capability := range caps {
+		c.v.Call("enable", uint32(capability))
+	}
+}
+
+func (c *Context) Disable(caps ...Capability) {
+	for _, capability := range caps {
+		c.v.Call("disable", uint32(capability))
+	}
+}
+
+func (c *Context) BlendFunc(src, dst BlendFactor) {
+	c.v.Call("blendFunc", uint32(src), uint32(dst))
+}
+
+func (c *Context) DepthFunc(f CompareFunc) {
+	c.v.Call("depthFunc", uint32(f))
+}
+
+func (c *Context) DepthMask(write bool) {
+	c.v.Call("depthMask", write)
+}
+
+func (c *Context) CullFace(face Face) {
+	c.v.Call("cullFace", uint32(face))
+}
+
+func (c *Context) DrawArrays(mode Primitive, first, count int) {
+	c.v.Call("drawArrays", uint32(mode), first, count)
+}
+
+// DrawElements draws using the bound element array buffer, offset is in bytes.
+func (c *Context) DrawElements(mode Primitive, count int, typ DataType, offset int) {
+	c.v.Call("drawElements", uint32(mode), count, uint32(typ), offset)
+}
+
+func (c *Context) DrawArraysInstanced(mode Primitive, first, count, instances int) {
+	c.v.Call("drawArraysInstanced", uint32(mode), first, count, instances)
+}
+
+func (c *Context) DrawElementsInstanced(mode Primitive, count int, typ DataType, offset, instances int) {
+	c.v.Call("drawElementsInstanced", uint32(mode), count, uint32(typ), offset, instances)
+}
+
+// use makes the program current, if it is not already.
+func (c *Context) use(p *Program) {
+	if c.program != p {
+		c.v.Call("useProgram", p.v)
+		c.program = p
+	}
+}
+
+// bytesOf encodes slices of numbers, or of fixed size structs, in little endian.
+func bytesOf(data any) ([]byte, error) {
+	switch d := data.(type) {
+	case []byte:
+		return d, nil
+	case []float32:
+		var b = make([]byte, 4*len(d))
+		for i, v := range d {
+			binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(v))
+		}
+		return b, nil
+	case []uint16:
+		var b = make([]byte, 2*len(d))
+		for i, v := range d {
+			binary.LittleEndian.PutUint16(b[2*i:], v)
+		}
+		return b, nil
+	case []uint32:
+		var b = make([]byte, 4*len(d))
+		for i, v := range d {
+			binary.LittleEndian.PutUint32(b[4*i:], v)
+		}
+		return b, nil
+	}
+	if binary.Size(data) < 0 {
+		return nil, ErrUnsupportedData
+	}
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.LittleEndian, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func uint8Array(b []byte) js.Value {
+	var a = js.Global().Get("Uint8Array").New(len(b))
+	js.CopyBytesToJS(a, b)
+	return a
+}
+
+func float32Array(values []float32) js.Value {
+	var b, _ = bytesOf(values)
+	return js.Global().Get("Float32Array").New(uint8Array(b).Get("buffer"))
+}
+
+func int32Array(values []int32) js.Value {
+	var b = make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(b[4*i:], uint32(v))
+	}
+	return js.Global().Get("Int32Array").New(uint8Array(b).Get("buffer"))
+}
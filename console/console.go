@@ -1,11 +1,9 @@
-//go:build js && wasm
-// +build js,wasm
-
 package console
 
 import (
 	"fmt"
-	"syscall/js"
+
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 // MISSING:
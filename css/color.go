@@ -0,0 +1,568 @@
+package css
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Nigel2392/jsext/v2/errs"
+)
+
+// AlphaColor is a Color with an alpha channel in [0..1].
+type AlphaColor struct {
+	Color
+	A float64
+}
+
+// WithAlpha returns the color with the alpha channel set.
+func (col Color) WithAlpha(a float64) AlphaColor {
+	return AlphaColor{Color: col, A: clamp01(a)}
+}
+
+// String returns the color as a hex color if it is opaque, otherwise as rgb() with alpha.
+func (c AlphaColor) String() string {
+	if c.A >= 1 {
+		return c.Clamped().Hex()
+	}
+	var r, g, b = c.Clamped().RGB255()
+	return fmt.Sprintf("rgb(%d %d %d / %s)", r, g, b, formatFloat(c.A))
+}
+
+// Over composites the color over an opaque background.
+func (c AlphaColor) Over(background Color) Color {
+	return Color{
+		R: c.R*c.A + background.R*(1-c.A),
+		G: c.G*c.A + background.G*(1-c.A),
+		B: c.B*c.A + background.B*(1-c.A),
+	}
+}
+
+// Parse parses any CSS color:
+// hex colors, named colors, transparent, rgb(), rgba(), hsl(), hsla(), hwb(), lab(), lch(), oklab() and oklch().
+func Parse(s string) (AlphaColor, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "transparent" {
+		return AlphaColor{}, nil
+	}
+	if hex, ok := hexMap[s]; ok {
+		s = hex
+	}
+	if strings.HasPrefix(s, "#") {
+		return parseHex(s)
+	}
+
+	var open = strings.IndexByte(s, '(')
+	if open < 0 || !strings.HasSuffix(s, ")") {
+		return AlphaColor{}, errs.Error("css: invalid color " + s)
+	}
+	var name = s[:open]
+	var args, alpha, err = splitColorArgs(s[open+1 : len(s)-1])
+	if err != nil {
+		return AlphaColor{}, errs.Error("css: invalid color " + s)
+	}
+	if len(args) != 3 {
+		return AlphaColor{}, errs.Error("css: invalid color " + s)
+	}
+
+	var c Color
+	var v [3]float64
+	switch name {
+	case "rgb", "rgba":
+		for i, arg := range args {
+			if v[i], err = parseNumber(arg, 1, 1.0/255); err != nil {
+				break
+			}
+		}
+		c = Color{v[0], v[1], v[2]}
+	case "hsl", "hsla", "hwb":
+		if v[0], err = parseHue(args[0]); err != nil {
+			break
+		}
+		for i := 1; i < 3 && err == nil; i++ {
+			v[i], err = parseNumber(args[i], 1, 0.01)
+		}
+		if name == "hwb" {
+			c = Hwb(v[0], v[1], v[2])
+		} else {
+			c = Hsl(v[0], v[1], v[2])
+		}
+	case "lab", "oklab":
+		var lScale, abScale = 100.0, 125.0
+		if name == "oklab" {
+			lScale, abScale = 1, 0.4
+		}
+		if v[0], err = parseNumber(args[0], lScale, 1); err != nil {
+			break
+		}
+		for i := 1; i < 3 && err == nil; i++ {
+			v[i], err = parseNumber(args[i], abScale, 1)
+		}
+		if name == "oklab" {
+			c = OkLab(v[0], v[1], v[2])
+		} else {
+			c = Lab(v[0], v[1], v[2])
+		}
+	case "lch", "oklch":
+		var lScale, cScale = 100.0, 150.0
+		if name == "oklch" {
+			lScale, cScale = 1, 0.4
+		}
+		if v[0], err = parseNumber(args[0], lScale, 1); err != nil {
+			break
+		}
+		if v[1], err = parseNumber(args[1], cScale, 1); err != nil {
+			break
+		}
+		if v[2], err = parseHue(args[2]); err != nil {
+			break
+		}
+		if name == "oklch" {
+			c = OkLch(v[0], v[1], v[2])
+		} else {
+			c = Lch(v[0], v[1], v[2])
+		}
+	default:
+		return AlphaColor{}, errs.Error("css: unsupported color function " + name)
+	}
+	if err != nil {
+		return AlphaColor{}, errs.Error("css: invalid color " + s)
+	}
+
+	var a = 1.0
+	if alpha != "" {
+		if a, err = parseNumber(alpha, 1, 1); err != nil {
+			return AlphaColor{}, errs.Error("css: invalid alpha in color " + s)
+		}
+	}
+	return c.Clamped().WithAlpha(a), nil
+}
+
+// MustParse is like Parse, but panics if the color is invalid.
+func MustParse(s string) AlphaColor {
+	var c, err = Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func parseHex(s string) (AlphaColor, error) {
+	var digits = s[1:]
+	switch len(digits) {
+	case 3, 4:
+		var expanded = make([]byte, 0, len(digits)*2)
+		for i := 0; i < len(digits); i++ {
+			expanded = append(expanded, digits[i], digits[i])
+		}
+		digits = string(expanded)
+	case 6, 8:
+	default:
+		return AlphaColor{}, errs.Error("css: invalid hex color " + s)
+	}
+	var v [4]float64
+	v[3] = 1
+	for i := 0; i < len(digits)/2; i++ {
+		var n, err = strconv.ParseUint(digits[i*2:i*2+2], 16, 8)
+		if err != nil {
+			return AlphaColor{}, errs.Error("css: invalid hex color " + s)
+		}
+		v[i] = float64(n) / 255
+	}
+	return AlphaColor{Color: Color{v[0], v[1], v[2]}, A: v[3]}, nil
+}
+
+// splitColorArgs splits the arguments of a color function,
+// in both the legacy comma separated and the space separated syntax.
+func splitColorArgs(s string) (args []string, alpha string, err error) {
+	if before, after, ok := strings.Cut(s, "/"); ok {
+		s, alpha = before, strings.TrimSpace(after)
+	}
+	if strings.Contains(s, ",") {
+		for _, arg := range strings.Split(s, ",") {
+			args = append(args, strings.TrimSpace(arg))
+		}
+	} else {
+		args = strings.Fields(s)
+	}
+	if len(args) == 4 && alpha == "" {
+		args, alpha = args[:3], args[3]
+	}
+	for _, arg := range args {
+		if arg == "" {
+			return nil, "", errs.Error("css: empty color argument")
+		}
+	}
+	return args, alpha, nil
+}
+
+// parseNumber parses a number or percentage, 100% equals percentScale,
+// plain numbers are multiplied by numberScale.
+func parseNumber(s string, percentScale, numberScale float64) (float64, error) {
+	if s == "none" {
+		return 0, nil
+	}
+	if strings.HasSuffix(s, "%") {
+		var v, err = strconv.ParseFloat(s[:len(s)-1], 64)
+		return v / 100 * percentScale, err
+	}
+	var v, err = strconv.ParseFloat(s, 64)
+	return v * numberScale, err
+}
+
+// parseHue parses a hue angle to degrees.
+func parseHue(s string) (float64, error) {
+	var units = []struct {
+		suffix string
+		scale  float64
+	}{
+		{"deg", 1},
+		{"grad", 0.9},
+		{"rad", 180 / math.Pi},
+		{"turn", 360},
+	}
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			var v, err = strconv.ParseFloat(strings.TrimSuffix(s, u.suffix), 64)
+			return normalizeHue(v * u.scale), err
+		}
+	}
+	var v, err = parseNumber(s, 1, 1)
+	return normalizeHue(v), err
+}
+
+func normalizeHue(h float64) float64 {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
+
+// Clamped returns the color with its channels clamped to [0..1], for colors outside the sRGB gamut.
+func (col Color) Clamped() Color {
+	return Color{clamp01(col.R), clamp01(col.G), clamp01(col.B)}
+}
+
+// RGBString returns the color as rgb().
+func (col Color) RGBString() string {
+	var r, g, b = col.Clamped().RGB255()
+	return fmt.Sprintf("rgb(%d %d %d)", r, g, b)
+}
+
+// HslString returns the color as hsl().
+func (col Color) HslString() string {
+	var h, s, l = col.Hsl()
+	return "hsl(" + formatFloat(h) + " " + formatFloat(s*100) + "% " + formatFloat(l*100) + "%)"
+}
+
+// OkLchString returns the color as oklch().
+func (col Color) OkLchString() string {
+	var l, c, h = col.OkLch()
+	return "oklch(" + formatFloat(l) + " " + formatFloat(c) + " " + formatFloat(h) + ")"
+}
+
+// Hsl creates a new Color given a Hue in [0..360], a Saturation and a Lightness in [0..1].
+func Hsl(h, s, l float64) Color {
+	var v = l + s*math.Min(l, 1-l)
+	var sv float64
+	if v != 0 {
+		sv = 2 * (1 - l/v)
+	}
+	return Hsv(normalizeHue(h), sv, v)
+}
+
+// Hsl returns the Hue in [0..360], the Saturation and the Lightness in [0..1].
+func (col Color) Hsl() (h, s, l float64) {
+	var v float64
+	h, _, v = col.Hsv()
+	var min = math.Min(math.Min(col.R, col.G), col.B)
+	l = (v + min) / 2
+	if l > 0 && l < 1 {
+		s = (v - l) / math.Min(l, 1-l)
+	}
+	return h, s, l
+}
+
+// Hwb creates a new Color given a Hue in [0..360], a Whiteness and a Blackness in [0..1].
+func Hwb(h, w, b float64) Color {
+	if w+b >= 1 {
+		var gray = w / (w + b)
+		return Color{gray, gray, gray}
+	}
+	return Hsv(normalizeHue(h), 1-w/(1-b), 1-b)
+}
+
+// Hwb returns the Hue in [0..360], the Whiteness and the Blackness in [0..1].
+func (col Color) Hwb() (h, w, b float64) {
+	var s, v float64
+	h, s, v = col.Hsv()
+	return h, (1 - s) * v, 1 - v
+}
+
+func linearize(c float64) float64 {
+	var sign = 1.0
+	if c < 0 {
+		sign, c = -1, -c
+	}
+	if c <= 0.04045 {
+		return sign * c / 12.92
+	}
+	return sign * math.Pow((c+0.055)/1.055, 2.4)
+}
+
+func delinearize(c float64) float64 {
+	var sign = 1.0
+	if c < 0 {
+		sign, c = -1, -c
+	}
+	if c <= 0.0031308 {
+		return sign * c * 12.92
+	}
+	return sign * (1.055*math.Pow(c, 1/2.4) - 0.055)
+}
+
+// LinearRGB returns the color in linear sRGB.
+func (col Color) LinearRGB() (r, g, b float64) {
+	return linearize(col.R), linearize(col.G), linearize(col.B)
+}
+
+// LinearRGB creates a new Color from linear sRGB.
+func LinearRGB(r, g, b float64) Color {
+	return Color{delinearize(r), delinearize(g), delinearize(b)}
+}
+
+// XYZ returns the color in CIE XYZ with a D65 white point.
+func (col Color) XYZ() (x, y, z float64) {
+	var r, g, b = col.LinearRGB()
+	x = 0.4123908*r + 0.3575843*g + 0.1804808*b
+	y = 0.2126390*r + 0.7151687*g + 0.0721923*b
+	z = 0.0193308*r + 0.1191948*g + 0.9505322*b
+	return x, y, z
+}
+
+// XYZ creates a new Color from CIE XYZ with a D65 white point.
+func XYZ(x, y, z float64) Color {
+	return LinearRGB(
+		3.2409699*x-1.5373832*y-0.4986108*z,
+		-0.9692436*x+1.8759675*y+0.0415551*z,
+		0.0556301*x-0.2039770*y+1.0569715*z,
+	)
+}
+
+// CIE Lab constants, lab() in CSS uses a D50 white point.
+const (
+	labEpsilon = 216.0 / 24389.0
+	labKappa   = 24389.0 / 27.0
+	d50X       = 0.3457 / 0.3585
+	d50Z       = (1.0 - 0.3457 - 0.3585) / 0.3585
+)
+
+// Lab returns the color in CIE Lab, with L in [0..100].
+func (col Color) Lab() (l, a, b float64) {
+	var x65, y65, z65 = col.XYZ()
+	// Bradford adaptation from D65 to D50.
+	var x = 1.0479298*x65 + 0.0229468*y65 - 0.0501922*z65
+	var y = 0.0296278*x65 + 0.9904344*y65 - 0.0170738*z65
+	var z = -0.0092430*x65 + 0.0150552*y65 + 0.7518743*z65
+
+	var f = func(t float64) float64 {
+		if t > labEpsilon {
+			return math.Cbrt(t)
+		}
+		return (labKappa*t + 16) / 116
+	}
+	var fx, fy, fz = f(x / d50X), f(y), f(z / d50Z)
+	return 116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)
+}
+
+// Lab creates a new Color from CIE Lab, with L in [0..100].
+func Lab(l, a, b float64) Color {
+	var fy = (l + 16) / 116
+	var fx = a/500 + fy
+	var fz = fy - b/200
+	var finv = func(f float64) float64 {
+		if f*f*f > labEpsilon {
+			return f * f * f
+		}
+		return (116*f - 16) / labKappa
+	}
+	var x, z = finv(fx) * d50X, finv(fz) * d50Z
+	var y = l / labKappa
+	if l > labKappa*labEpsilon {
+		y = fy * fy * fy
+	}
+	// Bradford adaptation from D50 to D65.
+	return XYZ(
+		0.9554734*x-0.0230985*y+0.0632593*z,
+		-0.0283697*x+1.0099955*y+0.0210414*z,
+		0.0123140*x-0.0205077*y+1.3303659*z,
+	)
+}
+
+// Lch returns the color in CIE LCH, with L in [0..100] and the hue in [0..360].
+func (col Color) Lch() (l, c, h float64) {
+	var a, b float64
+	l, a, b = col.Lab()
+	c, h = toPolar(a, b)
+	return l, c, h
+}
+
+// Lch creates a new Color from CIE LCH.
+func Lch(l, c, h float64) Color {
+	var a, b = fromPolar(c, h)
+	return Lab(l, a, b)
+}
+
+// OkLab returns the color in Oklab, with L in [0..1].
+func (col Color) OkLab() (l, a, b float64) {
+	var r, g, bl = col.LinearRGB()
+	var lc = math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*bl)
+	var mc = math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*bl)
+	var sc = math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*bl)
+	l = 0.2104542553*lc + 0.7936177850*mc - 0.0040720468*sc
+	a = 1.9779984951*lc - 2.4285922050*mc + 0.4505937099*sc
+	b = 0.0259040371*lc + 0.7827717662*mc - 0.8086757660*sc
+	return l, a, b
+}
+
+// OkLab creates a new Color from Oklab, with L in [0..1].
+func OkLab(l, a, b float64) Color {
+	var lc = l + 0.3963377774*a + 0.2158037573*b
+	var mc = l - 0.1055613458*a - 0.0638541728*b
+	var sc = l - 0.0894841775*a - 1.2914855480*b
+	lc, mc, sc = lc*lc*lc, mc*mc*mc, sc*sc*sc
+	return LinearRGB(
+		4.0767416621*lc-3.3077115913*mc+0.2309699292*sc,
+		-1.2684380046*lc+2.6097574011*mc-0.3413193965*sc,
+		-0.0041960863*lc-0.7034186147*mc+1.7076147010*sc,
+	)
+}
+
+// OkLch returns the color in Oklch, with L in [0..1] and the hue in [0..360].
+func (col Color) OkLch() (l, c, h float64) {
+	var a, b float64
+	l, a, b = col.OkLab()
+	c, h = toPolar(a, b)
+	return l, c, h
+}
+
+// OkLch creates a new Color from Oklch.
+func OkLch(l, c, h float64) Color {
+	var a, b = fromPolar(c, h)
+	return OkLab(l, a, b)
+}
+
+func toPolar(a, b float64) (c, h float64) {
+	c = math.Hypot(a, b)
+	if c < 1e-6 {
+		return 0, 0
+	}
+	return c, normalizeHue(math.Atan2(b, a) * 180 / math.Pi)
+}
+
+func fromPolar(c, h float64) (a, b float64) {
+	var rad = h * math.Pi / 180
+	return c * math.Cos(rad), c * math.Sin(rad)
+}
+
+// Lighten increases the HSL lightness by amount in [0..1].
+func (col Color) Lighten(amount float64) Color {
+	var h, s, l = col.Hsl()
+	return Hsl(h, s, clamp01(l+amount))
+}
+
+// Darken decreases the HSL lightness by amount in [0..1].
+func (col Color) Darken(amount float64) Color {
+	return col.Lighten(-amount)
+}
+
+// Saturate increases the HSL saturation by amount in [0..1].
+func (col Color) Saturate(amount float64) Color {
+	var h, s, l = col.Hsl()
+	return Hsl(h, clamp01(s+amount), l)
+}
+
+// Desaturate decreases the HSL saturation by amount in [0..1].
+func (col Color) Desaturate(amount float64) Color {
+	return col.Saturate(-amount)
+}
+
+// Mix mixes the color with other in Oklab, like color-mix() does by default.
+//
+// A weight of 0 returns the color, a weight of 1 returns other.
+func (col Color) Mix(other Color, weight float64) Color {
+	weight = clamp01(weight)
+	var l1, a1, b1 = col.OkLab()
+	var l2, a2, b2 = other.OkLab()
+	return OkLab(
+		l1+(l2-l1)*weight,
+		a1+(a2-a1)*weight,
+		b1+(b2-b1)*weight,
+	).Clamped()
+}
+
+// Luminance returns the relative luminance as defined by WCAG.
+func (col Color) Luminance() float64 {
+	var r, g, b = col.Clamped().LinearRGB()
+	return 0.2126*r + 0.7152*g + 0.0722*b
+}
+
+// ContrastRatio returns the WCAG contrast ratio between the colors, in [1..21].
+func (col Color) ContrastRatio(other Color) float64 {
+	var l1, l2 = col.Luminance(), other.Luminance()
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+// WCAG contrast ratios.
+const (
+	ContrastAA      = 4.5
+	ContrastAALarge = 3.0
+	ContrastAAA     = 7.0
+)
+
+// IsLight reports whether black text has more contrast on the color than white text.
+func (col Color) IsLight() bool {
+	return col.ContrastRatio(Color{0, 0, 0}) > col.ContrastRatio(Color{1, 1, 1})
+}
+
+// ContrastText returns black or white, whichever has the most contrast on the color.
+func (col Color) ContrastText() Color {
+	if col.IsLight() {
+		return Color{0, 0, 0}
+	}
+	return Color{1, 1, 1}
+}
+
+// EnsureContrast changes the Oklch lightness of the color until its contrast ratio against
+// the background is at least ratio, keeping the hue.
+//
+// If the ratio cannot be reached, black or white is returned.
+func (col Color) EnsureContrast(background Color, ratio float64) Color {
+	if col.ContrastRatio(background) >= ratio {
+		return col
+	}
+	var l, c, h = col.OkLch()
+	var step = 0.01
+	if background.IsLight() {
+		step = -step
+	}
+	for l = l + step; l >= 0 && l <= 1; l += step {
+		var adjusted = OkLch(l, c, h).Clamped()
+		if adjusted.ContrastRatio(background) >= ratio {
+			return adjusted
+		}
+	}
+	return background.ContrastText()
+}
@@ -1,11 +1,11 @@
-//go:build js && wasm
-// +build js,wasm
-
 package css
 
 import "strconv"
 
 // General colors for use in CSS. Can be overridden.
+//
+// Deprecated: these are only read once, when DefaultLight is created.
+// Use a Theme and Var instead, which can be changed at runtime.
 var (
 	COLOR_MAIN        = "white"           // Main color - white
 	COLOR_ONE         = "#222e50"         // COLOR_ONE - dark blue
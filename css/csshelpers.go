@@ -1,6 +1,3 @@
-//go:build js && wasm
-// +build js,wasm
-
 package css
 
 import (
@@ -122,6 +119,7 @@ var hexMap = map[string]string{
 	"plum":                 "#dda0dd",
 	"powderblue":           "#b0e0e6",
 	"purple":               "#800080",
+	"rebeccapurple":        "#663399",
 	"red":                  "#ff0000",
 	"rosybrown":            "#bc8f8f",
 	"royalblue":            "#4169e1",
@@ -343,9 +341,11 @@ func SeenColor(col Color) string {
 }
 
 // If the right format is not given, we will return the color as is.
+//
+// The color can be in any syntax supported by Parse.
 func FormatRGBA(color, format string) string {
 	var ret string
-	var col, err = Hex(color)
+	var col, err = Parse(color)
 	if err == nil {
 		var r, g, b = col.RGB255()
 		ret = fmt.Sprintf(format, r, g, b)
@@ -0,0 +1,353 @@
+package css
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+// StyleSheet builds CSS from Go, with class names and keyframes scoped to the sheet.
+//
+// Class names are generated as <name>-<class>-<hash>, so two sheets
+// using the same class names do not collide.
+//
+// Example:
+//
+//	var sheet = css.NewStyleSheet("jsext-card")
+//	var card = sheet.Class("card", func(r *css.Rule) {
+//		r.Set("padding", css.ToRem(1))
+//		r.Rule("&:hover", func(r *css.Rule) {
+//			r.Set("background-color", css.COLOR_FOUR)
+//		})
+//		r.Media("(max-width: 600px)", func(r *css.Rule) {
+//			r.Set("padding", "0")
+//		})
+//	})
+//	sheet.Inject()
+type StyleSheet struct {
+	name      string
+	classes   map[string]string
+	rules     []*Rule
+	keyframes []*Keyframes
+	sheet     js.Value
+	style     js.Value
+}
+
+// NewStyleSheet returns an empty stylesheet, the name is used to scope the class names.
+func NewStyleSheet(name string) *StyleSheet {
+	return &StyleSheet{
+		name:    name,
+		classes: make(map[string]string),
+		sheet:   js.Undefined(),
+		style:   js.Undefined(),
+	}
+}
+
+func (s *StyleSheet) Name() string {
+	return s.name
+}
+
+// ClassName returns the scoped class name for the name, without a leading dot.
+func (s *StyleSheet) ClassName(name string) string {
+	if cls, ok := s.classes[name]; ok {
+		return cls
+	}
+	var h = fnv.New32a()
+	h.Write([]byte(s.name))
+	h.Write([]byte{0})
+	h.Write([]byte(name))
+	var cls = name + "-" + strconv.FormatUint(uint64(h.Sum32()), 36)
+	if s.name != "" {
+		cls = s.name + "-" + cls
+	}
+	s.classes[name] = cls
+	return cls
+}
+
+// Selector returns the selector for the scoped class name, for use in other rules.
+func (s *StyleSheet) Selector(name string) string {
+	return "." + s.ClassName(name)
+}
+
+// Class adds a rule for the scoped class, and returns the class name.
+func (s *StyleSheet) Class(name string, fn func(r *Rule)) string {
+	var cls = s.ClassName(name)
+	s.Rule("."+cls, fn)
+	return cls
+}
+
+// Rule adds a rule for a selector, which is not scoped.
+func (s *StyleSheet) Rule(selector string, fn func(r *Rule)) *StyleSheet {
+	var r = &Rule{selector: selector}
+	if fn != nil {
+		fn(r)
+	}
+	s.rules = append(s.rules, r)
+	return s
+}
+
+// Media adds rules which only apply when the media query matches.
+//
+// Rules added to r need full selectors.
+func (s *StyleSheet) Media(query string, fn func(r *Rule)) *StyleSheet {
+	var r = &Rule{}
+	r.Media(query, fn)
+	s.rules = append(s.rules, r)
+	return s
+}
+
+// Keyframes adds a scoped @keyframes rule, and returns the name to use for animation-name.
+func (s *StyleSheet) Keyframes(name string, fn func(k *Keyframes)) string {
+	var k = &Keyframes{name: s.ClassName(name)}
+	if fn != nil {
+		fn(k)
+	}
+	s.keyframes = append(s.keyframes, k)
+	return k.name
+}
+
+// String returns the CSS of the stylesheet.
+func (s *StyleSheet) String() string {
+	var b strings.Builder
+	for _, r := range s.rules {
+		r.write(&b, "")
+	}
+	for _, k := range s.keyframes {
+		k.write(&b)
+	}
+	return b.String()
+}
+
+// Inject adds the stylesheet to the document.
+//
+// The stylesheet is adopted through a constructable CSSStyleSheet if the browser supports it,
+// otherwise a style element is appended to the head.
+// Calling Inject again updates the CSS of the already injected stylesheet.
+func (s *StyleSheet) Inject() {
+	var document = js.Global().Get("document")
+	var text = s.String()
+	if s.injected() {
+		s.update(text)
+		return
+	}
+	if existing, ok := injected[s.name]; ok && s.name != "" {
+		// Another instance with the same name has already been injected,
+		// components creating their sheet on each call share it.
+		s.sheet, s.style = existing.sheet, existing.style
+		s.update(text)
+		return
+	}
+
+	var constructor = js.Global().Get("CSSStyleSheet")
+	var adopted = document.Get("adoptedStyleSheets")
+	if constructor.Truthy() && adopted.Truthy() && constructor.Get("prototype").Get("replaceSync").Truthy() {
+		s.sheet = constructor.New()
+		s.sheet.Call("replaceSync", text)
+		document.Set("adoptedStyleSheets", adopted.Call("concat", []any{s.sheet}))
+	} else {
+		s.style = document.Call("createElement", "style")
+		s.style.Set("textContent", text)
+		document.Get("head").Call("appendChild", s.style)
+	}
+	if s.name != "" {
+		injected[s.name] = s
+	}
+}
+
+// Remove removes the stylesheet from the document.
+func (s *StyleSheet) Remove() {
+	if !s.injected() {
+		return
+	}
+	if s.sheet.Truthy() {
+		var document = js.Global().Get("document")
+		var adopted = document.Get("adoptedStyleSheets")
+		var sheets = make([]any, 0, adopted.Length())
+		for i := 0; i < adopted.Length(); i++ {
+			if !adopted.Index(i).Equal(s.sheet) {
+				sheets = append(sheets, adopted.Index(i))
+			}
+		}
+		document.Set("adoptedStyleSheets", sheets)
+	}
+	if s.style.Truthy() {
+		s.style.Call("remove")
+	}
+	if other, ok := injected[s.name]; ok && other.sheet.Equal(s.sheet) && other.style.Equal(s.style) {
+		delete(injected, s.name)
+	}
+	s.sheet, s.style = js.Undefined(), js.Undefined()
+}
+
+func (s *StyleSheet) injected() bool {
+	return s.sheet.Truthy() || s.style.Truthy()
+}
+
+func (s *StyleSheet) update(text string) {
+	if s.sheet.Truthy() {
+		s.sheet.Call("replaceSync", text)
+		return
+	}
+	s.style.Set("textContent", text)
+	// The style element is added again if it was removed from the document,
+	// for example when the document is reset between server-side renders.
+	if !s.style.Get("isConnected").Truthy() {
+		js.Global().Get("document").Get("head").Call("appendChild", s.style)
+	}
+}
+
+// Injected stylesheets by name.
+var injected = make(map[string]*StyleSheet)
+
+type declaration struct {
+	property string
+	value    string
+}
+
+type mediaRule struct {
+	query string
+	rule  *Rule
+}
+
+// Rule is a CSS rule with declarations, nested rules and media queries.
+type Rule struct {
+	selector string
+	decls    []declaration
+	children []*Rule
+	media    []mediaRule
+}
+
+// Set adds a declaration to the rule.
+func (r *Rule) Set(property, value string) *Rule {
+	r.decls = append(r.decls, declaration{property, value})
+	return r
+}
+
+// Rule adds a nested rule.
+//
+// A & in the selector is replaced with the selector of the parent,
+// otherwise the selector matches descendants of the parent.
+func (r *Rule) Rule(selector string, fn func(r *Rule)) *Rule {
+	var child = &Rule{selector: selector}
+	if fn != nil {
+		fn(child)
+	}
+	r.children = append(r.children, child)
+	return r
+}
+
+// Media adds declarations and rules which only apply when the media query matches.
+func (r *Rule) Media(query string, fn func(r *Rule)) *Rule {
+	var child = &Rule{selector: "&"}
+	if fn != nil {
+		fn(child)
+	}
+	r.media = append(r.media, mediaRule{query, child})
+	return r
+}
+
+func (r *Rule) write(b *strings.Builder, parent string) {
+	var selector = resolveSelector(parent, r.selector)
+	if len(r.decls) > 0 && selector != "" {
+		b.WriteString(selector)
+		b.WriteString(" {")
+		writeDeclarations(b, r.decls)
+		b.WriteString("}\n")
+	}
+	for _, child := range r.children {
+		child.write(b, selector)
+	}
+	for _, m := range r.media {
+		b.WriteString("@media ")
+		b.WriteString(m.query)
+		b.WriteString(" {\n")
+		m.rule.write(b, selector)
+		b.WriteString("}\n")
+	}
+}
+
+func writeDeclarations(b *strings.Builder, decls []declaration) {
+	for _, d := range decls {
+		b.WriteString(d.property)
+		b.WriteString(": ")
+		b.WriteString(d.value)
+		b.WriteString(";")
+	}
+}
+
+// Keyframes is a @keyframes rule.
+type Keyframes struct {
+	name   string
+	frames []*Rule
+}
+
+func (k *Keyframes) Name() string {
+	return k.name
+}
+
+// Frame adds a keyframe, the selector is "from", "to" or a percentage.
+func (k *Keyframes) Frame(selector string, fn func(r *Rule)) *Keyframes {
+	var r = &Rule{selector: selector}
+	if fn != nil {
+		fn(r)
+	}
+	k.frames = append(k.frames, r)
+	return k
+}
+
+func (k *Keyframes) write(b *strings.Builder) {
+	b.WriteString("@keyframes ")
+	b.WriteString(k.name)
+	b.WriteString(" {\n")
+	for _, f := range k.frames {
+		b.WriteString(f.selector)
+		b.WriteString(" {")
+		writeDeclarations(b, f.decls)
+		b.WriteString("}\n")
+	}
+	b.WriteString("}\n")
+}
+
+// resolveSelector combines the selector of a nested rule with the selector of its parent.
+func resolveSelector(parent, selector string) string {
+	if parent == "" {
+		return strings.ReplaceAll(selector, "&", "")
+	}
+	if selector == "" {
+		return parent
+	}
+	var parents = splitSelector(parent)
+	var result []string
+	for _, s := range splitSelector(selector) {
+		for _, p := range parents {
+			if strings.Contains(s, "&") {
+				result = append(result, strings.ReplaceAll(s, "&", p))
+			} else {
+				result = append(result, p+" "+s)
+			}
+		}
+	}
+	return strings.Join(result, ", ")
+}
+
+// splitSelector splits a selector list on commas which are not within parentheses or brackets.
+func splitSelector(selector string) []string {
+	var parts []string
+	var depth, start int
+	for i, c := range selector {
+		switch c {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(selector[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(selector[start:]))
+}
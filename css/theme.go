@@ -0,0 +1,224 @@
+package css
+
+import (
+	"sort"
+
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+// Tokens of the theme, each is emitted as a custom property prefixed with --jsext-.
+const (
+	ColorPrimary      = "color-primary"
+	ColorPrimaryLight = "color-primary-light"
+	ColorSecondary    = "color-secondary"
+	ColorTertiary     = "color-tertiary"
+	ColorAccent       = "color-accent"
+	ColorInfo         = "color-info"
+	ColorDanger       = "color-danger"
+	ColorDangerHover  = "color-danger-hover"
+	ColorOnDanger     = "color-on-danger"
+	ColorBackground   = "color-background"
+	ColorText         = "color-text"
+	ColorBorder       = "color-border"
+	ColorHover        = "color-hover"
+	ColorBackdrop     = "color-backdrop"
+	ColorOnBackdrop   = "color-on-backdrop"
+)
+
+// VarPrefix is the prefix of the custom properties of the theme.
+const VarPrefix = "--jsext-"
+
+// ThemeAttribute is set on the document element to force a mode.
+const ThemeAttribute = "data-jsext-theme"
+
+type ThemeMode string
+
+const (
+	// ThemeAuto follows prefers-color-scheme.
+	ThemeAuto  ThemeMode = "auto"
+	ThemeLight ThemeMode = "light"
+	ThemeDark  ThemeMode = "dark"
+)
+
+// Palette maps tokens to CSS values.
+type Palette map[string]string
+
+// Merge returns a copy of the palette with the values of other set on it.
+func (p Palette) Merge(other Palette) Palette {
+	var m = make(Palette, len(p)+len(other))
+	for k, v := range p {
+		m[k] = v
+	}
+	for k, v := range other {
+		m[k] = v
+	}
+	return m
+}
+
+// DefaultLight is the light palette, and the fallback for tokens a theme does not set.
+var DefaultLight = Palette{
+	ColorPrimary:      COLOR_ONE,
+	ColorPrimaryLight: COLOR_ONE_LIGHTER,
+	ColorSecondary:    COLOR_TWO,
+	ColorTertiary:     COLOR_THREE,
+	ColorAccent:       COLOR_FOUR,
+	ColorInfo:         COLOR_FIVE,
+	ColorDanger:       COLOR_SIX,
+	ColorDangerHover:  "#910c0c",
+	ColorOnDanger:     "#fff",
+	ColorBackground:   "#fff",
+	ColorText:         "#232323",
+	ColorBorder:       "#ccc",
+	ColorHover:        "rgba(0, 0, 0, 0.08)",
+	ColorBackdrop:     BACKGROUND_COLOR,
+	ColorOnBackdrop:   COLOR_MAIN,
+}
+
+// DefaultDark is the dark palette of the default theme.
+var DefaultDark = DefaultLight.Merge(Palette{
+	ColorPrimary:      "#6f86c6",
+	ColorPrimaryLight: "#8ea2e0",
+	ColorAccent:       "#3a4468",
+	ColorBackground:   "#1e1e1e",
+	ColorText:         "#e6e6e6",
+	ColorBorder:       "#444",
+	ColorHover:        "rgba(255, 255, 255, 0.08)",
+	ColorBackdrop:     "rgba(0, 0, 0, 0.7)",
+})
+
+// Theme sets the custom properties used by the components.
+//
+// The dark palette is used when the user prefers a dark color scheme,
+// unless a mode is forced with SetThemeMode.
+type Theme struct {
+	Light Palette
+	// Dark is optional, without it the light palette is always used.
+	Dark Palette
+}
+
+func NewTheme(light, dark Palette) *Theme {
+	return &Theme{Light: light, Dark: dark}
+}
+
+// DefaultTheme returns a theme with the default palettes.
+func DefaultTheme() *Theme {
+	return NewTheme(DefaultLight.Merge(nil), DefaultDark.Merge(nil))
+}
+
+var currentTheme *Theme
+
+// CurrentTheme returns the theme which was last applied, or nil.
+func CurrentTheme() *Theme {
+	return currentTheme
+}
+
+// VarName returns the name of the custom property for the token.
+func VarName(token string) string {
+	return VarPrefix + token
+}
+
+// Var returns a var() reference to the token, with the default light value as fallback.
+//
+// Components use Var so they follow the current theme, and still work when no theme was applied.
+func Var(token string) string {
+	if fallback, ok := DefaultLight[token]; ok {
+		return "var(" + VarName(token) + ", " + fallback + ")"
+	}
+	return "var(" + VarName(token) + ")"
+}
+
+// StyleSheet returns the stylesheet which declares the custom properties of the theme.
+func (t *Theme) StyleSheet() *StyleSheet {
+	var s = NewStyleSheet("jsext-theme")
+	var on = "[" + ThemeAttribute + "=\"dark\"]"
+	var off = "[" + ThemeAttribute + "=\"light\"]"
+	s.Rule(":root", func(r *Rule) {
+		setPalette(r, t.Light)
+	})
+	if len(t.Dark) == 0 {
+		return s
+	}
+	s.Media("(prefers-color-scheme: dark)", func(r *Rule) {
+		r.Rule(":root:not("+off+")", func(r *Rule) {
+			setPalette(r, t.Dark)
+		})
+	})
+	s.Rule(":root"+on, func(r *Rule) {
+		setPalette(r, t.Dark)
+	})
+	return s
+}
+
+func setPalette(r *Rule, p Palette) {
+	var tokens = make([]string, 0, len(p))
+	for token := range p {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+	for _, token := range tokens {
+		r.Set(VarName(token), p[token])
+	}
+}
+
+// Apply injects the theme into the document, replacing the theme applied before.
+func (t *Theme) Apply() {
+	// Stylesheets are injected once per name, so this updates the previous theme.
+	t.StyleSheet().Inject()
+	currentTheme = t
+}
+
+// SetThemeMode forces the light or dark palette, ThemeAuto follows prefers-color-scheme again.
+func SetThemeMode(mode ThemeMode) {
+	var root = js.Global().Get("document").Get("documentElement")
+	if mode == ThemeAuto || mode == "" {
+		root.Call("removeAttribute", ThemeAttribute)
+		return
+	}
+	root.Call("setAttribute", ThemeAttribute, string(mode))
+}
+
+// GetThemeMode returns the forced mode, or ThemeAuto.
+func GetThemeMode() ThemeMode {
+	var v = js.Global().Get("document").Get("documentElement").Call("getAttribute", ThemeAttribute)
+	if v.IsNull() || v.IsUndefined() {
+		return ThemeAuto
+	}
+	return ThemeMode(v.String())
+}
+
+// PaletteFromColor generates a palette from a base color.
+//
+// The colors are adjusted so text in them meets WCAG AA contrast against the background of the palette.
+func PaletteFromColor(base Color, dark bool) Palette {
+	var defaults = DefaultLight
+	if dark {
+		defaults = DefaultDark
+	}
+	var background = MustParse(defaults[ColorBackground]).Color
+	var text = background.ContrastText().Mix(background, 0.1)
+	var primary = base.EnsureContrast(background, ContrastAA)
+	var danger = MustParse(defaults[ColorDanger]).Color.EnsureContrast(background, ContrastAALarge)
+	var triadic = primary.Triadic()
+	var accent = primary.Mix(background, 0.8)
+
+	return defaults.Merge(Palette{
+		ColorPrimary:      primary.Hex(),
+		ColorPrimaryLight: primary.Mix(background, 0.2).EnsureContrast(background, ContrastAALarge).Hex(),
+		ColorSecondary:    triadic[0].EnsureContrast(background, ContrastAALarge).Hex(),
+		ColorTertiary:     triadic[1].EnsureContrast(background, ContrastAALarge).Hex(),
+		ColorAccent:       accent.Hex(),
+		ColorInfo:         primary.Complementary().EnsureContrast(background, ContrastAA).Hex(),
+		ColorDanger:       danger.Hex(),
+		ColorDangerHover:  danger.Mix(text, 0.3).Hex(),
+		ColorOnDanger:     danger.ContrastText().Hex(),
+		ColorBackground:   background.Hex(),
+		ColorText:         text.EnsureContrast(background, ContrastAAA).Hex(),
+		ColorBorder:       text.Mix(background, 0.75).Hex(),
+		ColorHover:        primary.WithAlpha(0.12).String(),
+	})
+}
+
+// ThemeFromColor generates a theme with light and dark palettes from a base color.
+func ThemeFromColor(base Color) *Theme {
+	return NewTheme(PaletteFromColor(base, false), PaletteFromColor(base, true))
+}
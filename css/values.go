@@ -0,0 +1,329 @@
+package css
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Nigel2392/jsext/v2/errs"
+)
+
+// Value is a typed CSS value, which can be set on a jsext.Style.
+type Value interface {
+	String() string
+}
+
+// Keyword is a CSS keyword, or any other value which is used as it is.
+type Keyword string
+
+func (k Keyword) String() string {
+	return string(k)
+}
+
+// Common keywords.
+const (
+	Auto    Keyword = "auto"
+	None    Keyword = "none"
+	Inherit Keyword = "inherit"
+	Initial Keyword = "initial"
+	Unset   Keyword = "unset"
+)
+
+// Length is a CSS length or percentage.
+type Length struct {
+	Value float64
+	Unit  string
+}
+
+func (l Length) String() string {
+	if l.Value == 0 && l.Unit != "%" {
+		return "0"
+	}
+	return formatFloat(l.Value) + l.Unit
+}
+
+func Px(v float64) Length      { return Length{v, "px"} }
+func Rem(v float64) Length     { return Length{v, "rem"} }
+func Em(v float64) Length      { return Length{v, "em"} }
+func Percent(v float64) Length { return Length{v, "%"} }
+func Vw(v float64) Length      { return Length{v, "vw"} }
+func Vh(v float64) Length      { return Length{v, "vh"} }
+func Ch(v float64) Length      { return Length{v, "ch"} }
+func Fr(v float64) Length      { return Length{v, "fr"} }
+
+// Angle is a CSS angle.
+type Angle struct {
+	Value float64
+	Unit  string
+}
+
+func (a Angle) String() string {
+	return formatFloat(a.Value) + a.Unit
+}
+
+// Degrees returns the angle in degrees.
+func (a Angle) Degrees() float64 {
+	switch a.Unit {
+	case "rad":
+		return a.Value * 180 / math.Pi
+	case "grad":
+		return a.Value * 0.9
+	case "turn":
+		return a.Value * 360
+	}
+	return a.Value
+}
+
+func Deg(v float64) Angle  { return Angle{v, "deg"} }
+func Rad(v float64) Angle  { return Angle{v, "rad"} }
+func Turn(v float64) Angle { return Angle{v, "turn"} }
+
+// Time is a CSS duration.
+type Time struct {
+	Value float64
+	Unit  string
+}
+
+func (t Time) String() string {
+	return formatFloat(t.Value) + t.Unit
+}
+
+func Ms(v float64) Time      { return Time{v, "ms"} }
+func Seconds(v float64) Time { return Time{v, "s"} }
+
+// Number is a unitless CSS number, like an opacity or z-index.
+type Number float64
+
+func (n Number) String() string {
+	return formatFloat(float64(n))
+}
+
+// String returns the color as a hex color.
+func (col Color) String() string {
+	return col.Clamped().Hex()
+}
+
+// Expr is a CSS expression, like calc() or var().
+type Expr string
+
+func (e Expr) String() string {
+	return string(e)
+}
+
+// Calc returns a calc() expression, the parts are joined with spaces.
+//
+//	css.Calc(css.Percent(100), "-", css.Px(20)) // calc(100% - 20px)
+func Calc(parts ...any) Expr {
+	return Expr("calc(" + joinValues(" ", parts) + ")")
+}
+
+func Min(values ...any) Expr {
+	return Expr("min(" + joinValues(", ", values) + ")")
+}
+
+func Max(values ...any) Expr {
+	return Expr("max(" + joinValues(", ", values) + ")")
+}
+
+func Clamp(min, preferred, max any) Expr {
+	return Expr("clamp(" + joinValues(", ", []any{min, preferred, max}) + ")")
+}
+
+// VarOf returns a var() expression for a custom property, the name includes the leading dashes.
+func VarOf(name string, fallback ...any) Expr {
+	if len(fallback) > 0 {
+		return Expr("var(" + name + ", " + joinValues(", ", fallback) + ")")
+	}
+	return Expr("var(" + name + ")")
+}
+
+// Transform is a list of CSS transform functions.
+type Transform []string
+
+func (t Transform) String() string {
+	if len(t) == 0 {
+		return "none"
+	}
+	return strings.Join(t, " ")
+}
+
+// Then returns the transform followed by the other transforms.
+func (t Transform) Then(other ...Transform) Transform {
+	var list = append(Transform(nil), t...)
+	for _, o := range other {
+		list = append(list, o...)
+	}
+	return list
+}
+
+func transform(name string, args ...any) Transform {
+	return Transform{name + "(" + joinValues(", ", args) + ")"}
+}
+
+func Translate(x, y Value) Transform { return transform("translate", x, y) }
+func TranslateX(x Value) Transform   { return transform("translateX", x) }
+func TranslateY(y Value) Transform   { return transform("translateY", y) }
+func Scale(x float64, y ...float64) Transform {
+	if len(y) > 0 {
+		return transform("scale", Number(x), Number(y[0]))
+	}
+	return transform("scale", Number(x))
+}
+func Rotate(a Angle) Transform       { return transform("rotate", a) }
+func Skew(x, y Angle) Transform      { return transform("skew", x, y) }
+func Perspective(d Length) Transform { return transform("perspective", d) }
+
+func joinValues(sep string, values []any) string {
+	var parts = make([]string, len(values))
+	for i, v := range values {
+		parts[i] = valueString(v)
+	}
+	return strings.Join(parts, sep)
+}
+
+// valueString formats a Value, string or number as CSS.
+func valueString(v any) string {
+	switch v := v.(type) {
+	case Value:
+		return v.String()
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return formatFloat(v)
+	}
+	return ""
+}
+
+// Keywords of common properties.
+var (
+	Display = struct {
+		None, Block, Inline, InlineBlock, Flex, InlineFlex, Grid, InlineGrid, Contents, Table Keyword
+	}{"none", "block", "inline", "inline-block", "flex", "inline-flex", "grid", "inline-grid", "contents", "table"}
+
+	Position = struct {
+		Static, Relative, Absolute, Fixed, Sticky Keyword
+	}{"static", "relative", "absolute", "fixed", "sticky"}
+
+	FlexDirection = struct {
+		Row, RowReverse, Column, ColumnReverse Keyword
+	}{"row", "row-reverse", "column", "column-reverse"}
+
+	JustifyContent = struct {
+		Start, End, Center, SpaceBetween, SpaceAround, SpaceEvenly, Stretch Keyword
+	}{"flex-start", "flex-end", "center", "space-between", "space-around", "space-evenly", "stretch"}
+
+	AlignItems = struct {
+		Start, End, Center, Baseline, Stretch Keyword
+	}{"flex-start", "flex-end", "center", "baseline", "stretch"}
+
+	Overflow = struct {
+		Visible, Hidden, Clip, Scroll, Auto Keyword
+	}{"visible", "hidden", "clip", "scroll", "auto"}
+
+	Visibility = struct {
+		Visible, Hidden, Collapse Keyword
+	}{"visible", "hidden", "collapse"}
+
+	TextAlign = struct {
+		Left, Right, Center, Justify, Start, End Keyword
+	}{"left", "right", "center", "justify", "start", "end"}
+
+	Cursor = struct {
+		Auto, Default, Pointer, Text, Move, NotAllowed, Grab, Grabbing, Wait Keyword
+	}{"auto", "default", "pointer", "text", "move", "not-allowed", "grab", "grabbing", "wait"}
+)
+
+var lengthUnits = []string{
+	"px", "rem", "em", "%", "vw", "vh", "vmin", "vmax", "ch", "ex",
+	"fr", "cm", "mm", "in", "pt", "pc", "svh", "lvh", "dvh",
+}
+
+// ParseLength parses a length like "4px" or "50%", a plain 0 has no unit.
+func ParseLength(s string) (Length, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	for _, unit := range lengthUnits {
+		if num, ok := cutUnit(s, unit); ok {
+			var v, err = strconv.ParseFloat(num, 64)
+			if err != nil {
+				break
+			}
+			return Length{v, unit}, nil
+		}
+	}
+	if v, err := strconv.ParseFloat(s, 64); err == nil && v == 0 {
+		return Length{}, nil
+	}
+	return Length{}, errs.Error("css: invalid length " + s)
+}
+
+// ParseAngle parses an angle like "90deg" or "0.5turn".
+func ParseAngle(s string) (Angle, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	for _, unit := range []string{"deg", "grad", "rad", "turn"} {
+		if num, ok := cutUnit(s, unit); ok {
+			if v, err := strconv.ParseFloat(num, 64); err == nil {
+				return Angle{v, unit}, nil
+			}
+			break
+		}
+	}
+	return Angle{}, errs.Error("css: invalid angle " + s)
+}
+
+// ParseTime parses a duration like "200ms" or "1.5s".
+func ParseTime(s string) (Time, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	for _, unit := range []string{"ms", "s"} {
+		if num, ok := cutUnit(s, unit); ok {
+			if v, err := strconv.ParseFloat(num, 64); err == nil {
+				return Time{v, unit}, nil
+			}
+			break
+		}
+	}
+	return Time{}, errs.Error("css: invalid time " + s)
+}
+
+// ParseValue parses a single CSS value into the most specific Value:
+// a Number, Length, Angle, Time, AlphaColor or Expr, otherwise a Keyword.
+func ParseValue(s string) Value {
+	s = strings.TrimSpace(s)
+	if v, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(v, 0) {
+		return Number(v)
+	}
+	if l, err := ParseLength(s); err == nil {
+		return l
+	}
+	if a, err := ParseAngle(s); err == nil {
+		return a
+	}
+	if t, err := ParseTime(s); err == nil {
+		return t
+	}
+	if c, err := Parse(s); err == nil {
+		return c
+	}
+	if strings.HasSuffix(s, ")") && strings.IndexByte(s, '(') > 0 {
+		return Expr(s)
+	}
+	return Keyword(s)
+}
+
+// cutUnit returns the number before the unit, if s ends with the unit.
+func cutUnit(s, unit string) (string, bool) {
+	if !strings.HasSuffix(s, unit) {
+		return "", false
+	}
+	var num = s[:len(s)-len(unit)]
+	if num == "" {
+		return "", false
+	}
+	// Letters before the unit belong to a longer unit, for example "vmin" is not "in".
+	var last = num[len(num)-1]
+	if last >= 'a' && last <= 'z' {
+		return "", false
+	}
+	return num, true
+}
@@ -1,7 +1,7 @@
 package dom
 
 import (
-	"syscall/js"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 func Walk(nodetypes []NodeType, e js.Value, fn func(Node)) {
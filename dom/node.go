@@ -2,7 +2,8 @@ package dom
 
 import (
 	"strings"
-	"syscall/js"
+
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 type Node struct {
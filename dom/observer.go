@@ -0,0 +1,491 @@
+package dom
+
+import (
+	"sync"
+
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+// Rect is a DOMRect.
+type Rect struct {
+	X, Y, Width, Height      float64
+	Top, Right, Bottom, Left float64
+}
+
+func rectOf(v js.Value) Rect {
+	if v.IsNull() || v.IsUndefined() {
+		return Rect{}
+	}
+	return Rect{
+		X:      v.Get("x").Float(),
+		Y:      v.Get("y").Float(),
+		Width:  v.Get("width").Float(),
+		Height: v.Get("height").Float(),
+		Top:    v.Get("top").Float(),
+		Right:  v.Get("right").Float(),
+		Bottom: v.Get("bottom").Float(),
+		Left:   v.Get("left").Float(),
+	}
+}
+
+func nodesOf(list js.Value) []js.Value {
+	var nodes = make([]js.Value, list.Length())
+	for i := range nodes {
+		nodes[i] = list.Index(i)
+	}
+	return nodes
+}
+
+func stringOrEmpty(v js.Value) string {
+	if v.IsNull() || v.IsUndefined() {
+		return ""
+	}
+	return v.String()
+}
+
+// queue delivers values to a channel in order, without blocking the JavaScript callback which pushes them.
+type queue[T any] struct {
+	mu     sync.Mutex
+	items  []T
+	closed bool
+	notify chan struct{}
+	out    chan T
+}
+
+func newQueue[T any]() *queue[T] {
+	var q = &queue[T]{
+		notify: make(chan struct{}, 1),
+		out:    make(chan T),
+	}
+	go q.run()
+	return q
+}
+
+func (q *queue[T]) push(v T) {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.items = append(q.items, v)
+	q.mu.Unlock()
+	q.signal()
+}
+
+func (q *queue[T]) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.signal()
+}
+
+func (q *queue[T]) signal() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (q *queue[T]) run() {
+	for range q.notify {
+		for {
+			q.mu.Lock()
+			if len(q.items) == 0 {
+				var closed = q.closed
+				q.mu.Unlock()
+				if closed {
+					close(q.out)
+					return
+				}
+				break
+			}
+			var v = q.items[0]
+			q.items = q.items[1:]
+			q.mu.Unlock()
+			q.out <- v
+		}
+	}
+}
+
+// IntersectionObserverOptions are the options of an IntersectionObserver.
+type IntersectionObserverOptions struct {
+	// Root defaults to the viewport.
+	Root js.Value
+	// RootMargin grows or shrinks the root, like "0px 0px 200px 0px".
+	RootMargin string
+	// Threshold is a list of ratios at which the callback is called.
+	Threshold []float64
+}
+
+func (o IntersectionObserverOptions) MarshalJS() js.Value {
+	var obj = js.Global().Get("Object").New()
+	if !o.Root.IsUndefined() && !o.Root.IsNull() {
+		obj.Set("root", o.Root)
+	}
+	if o.RootMargin != "" {
+		obj.Set("rootMargin", o.RootMargin)
+	}
+	if len(o.Threshold) > 0 {
+		var arr = make([]any, len(o.Threshold))
+		for i, t := range o.Threshold {
+			arr[i] = t
+		}
+		obj.Set("threshold", arr)
+	}
+	return obj
+}
+
+// IntersectionEntry is an IntersectionObserverEntry.
+type IntersectionEntry struct {
+	Target             js.Value
+	IsIntersecting     bool
+	IntersectionRatio  float64
+	BoundingClientRect Rect
+	IntersectionRect   Rect
+	RootBounds         Rect
+	Time               float64
+}
+
+func intersectionEntries(list js.Value) []IntersectionEntry {
+	var entries = make([]IntersectionEntry, list.Length())
+	for i := range entries {
+		var e = list.Index(i)
+		entries[i] = IntersectionEntry{
+			Target:             e.Get("target"),
+			IsIntersecting:     e.Get("isIntersecting").Bool(),
+			IntersectionRatio:  e.Get("intersectionRatio").Float(),
+			BoundingClientRect: rectOf(e.Get("boundingClientRect")),
+			IntersectionRect:   rectOf(e.Get("intersectionRect")),
+			RootBounds:         rectOf(e.Get("rootBounds")),
+			Time:               e.Get("time").Float(),
+		}
+	}
+	return entries
+}
+
+// IntersectionObserver reports when targets enter or leave the root.
+//
+// Entries are delivered to the callback, or to the channel returned by Entries if the callback is nil.
+type IntersectionObserver struct {
+	v        js.Value
+	fn       js.Func
+	callback func(entries []IntersectionEntry, o *IntersectionObserver)
+	queue    *queue[[]IntersectionEntry]
+}
+
+func NewIntersectionObserver(callback func(entries []IntersectionEntry, o *IntersectionObserver), opts ...IntersectionObserverOptions) *IntersectionObserver {
+	var o = &IntersectionObserver{callback: callback}
+	if callback == nil {
+		o.queue = newQueue[[]IntersectionEntry]()
+	}
+	o.fn = js.FuncOf(func(this js.Value, args []js.Value) any {
+		var entries = intersectionEntries(args[0])
+		if o.callback != nil {
+			o.callback(entries, o)
+		} else {
+			o.queue.push(entries)
+		}
+		return nil
+	})
+	var options IntersectionObserverOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	o.v = js.Global().Get("IntersectionObserver").New(o.fn, options.MarshalJS())
+	return o
+}
+
+// Entries returns the channel entries are sent on, it is nil if a callback was given.
+//
+// The channel is closed on Disconnect.
+func (o *IntersectionObserver) Entries() <-chan []IntersectionEntry {
+	if o.queue == nil {
+		return nil
+	}
+	return o.queue.out
+}
+
+func (o *IntersectionObserver) Observe(targets ...js.Value) *IntersectionObserver {
+	for _, t := range targets {
+		o.v.Call("observe", t)
+	}
+	return o
+}
+
+func (o *IntersectionObserver) Unobserve(targets ...js.Value) *IntersectionObserver {
+	for _, t := range targets {
+		o.v.Call("unobserve", t)
+	}
+	return o
+}
+
+// Disconnect stops observing all targets, and releases the callback.
+func (o *IntersectionObserver) Disconnect() {
+	if o.v.IsUndefined() {
+		return
+	}
+	o.v.Call("disconnect")
+	o.v = js.Undefined()
+	o.fn.Release()
+	if o.queue != nil {
+		o.queue.close()
+	}
+}
+
+// OnVisible calls f once, when the target becomes visible, for example to lazy load it.
+func OnVisible(target js.Value, f func(target js.Value), opts ...IntersectionObserverOptions) *IntersectionObserver {
+	return NewIntersectionObserver(func(entries []IntersectionEntry, o *IntersectionObserver) {
+		for _, e := range entries {
+			if e.IsIntersecting {
+				o.Disconnect()
+				f(e.Target)
+				return
+			}
+		}
+	}, opts...).Observe(target)
+}
+
+// Size is a ResizeObserverSize.
+type Size struct {
+	InlineSize float64
+	BlockSize  float64
+}
+
+func sizeOf(list js.Value) Size {
+	if list.IsUndefined() || list.IsNull() {
+		return Size{}
+	}
+	// Older browsers return a single size instead of a list.
+	if list.Get("length").IsUndefined() {
+		return Size{list.Get("inlineSize").Float(), list.Get("blockSize").Float()}
+	}
+	if list.Length() == 0 {
+		return Size{}
+	}
+	return Size{list.Index(0).Get("inlineSize").Float(), list.Index(0).Get("blockSize").Float()}
+}
+
+// ResizeEntry is a ResizeObserverEntry.
+type ResizeEntry struct {
+	Target         js.Value
+	ContentRect    Rect
+	BorderBoxSize  Size
+	ContentBoxSize Size
+}
+
+func resizeEntries(list js.Value) []ResizeEntry {
+	var entries = make([]ResizeEntry, list.Length())
+	for i := range entries {
+		var e = list.Index(i)
+		entries[i] = ResizeEntry{
+			Target:         e.Get("target"),
+			ContentRect:    rectOf(e.Get("contentRect")),
+			BorderBoxSize:  sizeOf(e.Get("borderBoxSize")),
+			ContentBoxSize: sizeOf(e.Get("contentBoxSize")),
+		}
+	}
+	return entries
+}
+
+// ResizeObserver reports changes to the size of targets.
+//
+// Entries are delivered to the callback, or to the channel returned by Entries if the callback is nil.
+type ResizeObserver struct {
+	v        js.Value
+	fn       js.Func
+	callback func(entries []ResizeEntry, o *ResizeObserver)
+	queue    *queue[[]ResizeEntry]
+}
+
+func NewResizeObserver(callback func(entries []ResizeEntry, o *ResizeObserver)) *ResizeObserver {
+	var o = &ResizeObserver{callback: callback}
+	if callback == nil {
+		o.queue = newQueue[[]ResizeEntry]()
+	}
+	o.fn = js.FuncOf(func(this js.Value, args []js.Value) any {
+		var entries = resizeEntries(args[0])
+		if o.callback != nil {
+			o.callback(entries, o)
+		} else {
+			o.queue.push(entries)
+		}
+		return nil
+	})
+	o.v = js.Global().Get("ResizeObserver").New(o.fn)
+	return o
+}
+
+func (o *ResizeObserver) Entries() <-chan []ResizeEntry {
+	if o.queue == nil {
+		return nil
+	}
+	return o.queue.out
+}
+
+// Observe observes the targets, box is "content-box" (default), "border-box" or "device-pixel-content-box".
+func (o *ResizeObserver) Observe(target js.Value, box ...string) *ResizeObserver {
+	if len(box) > 0 && box[0] != "" {
+		o.v.Call("observe", target, map[string]any{"box": box[0]})
+	} else {
+		o.v.Call("observe", target)
+	}
+	return o
+}
+
+func (o *ResizeObserver) Unobserve(targets ...js.Value) *ResizeObserver {
+	for _, t := range targets {
+		o.v.Call("unobserve", t)
+	}
+	return o
+}
+
+// Disconnect stops observing all targets, and releases the callback.
+func (o *ResizeObserver) Disconnect() {
+	if o.v.IsUndefined() {
+		return
+	}
+	o.v.Call("disconnect")
+	o.v = js.Undefined()
+	o.fn.Release()
+	if o.queue != nil {
+		o.queue.close()
+	}
+}
+
+// MutationObserverOptions are the options passed to MutationObserver.observe.
+type MutationObserverOptions struct {
+	ChildList             bool
+	Attributes            bool
+	CharacterData         bool
+	Subtree               bool
+	AttributeOldValue     bool
+	CharacterDataOldValue bool
+	// AttributeFilter limits the observed attributes.
+	AttributeFilter []string
+}
+
+func (o MutationObserverOptions) MarshalJS() js.Value {
+	var obj = js.Global().Get("Object").New()
+	obj.Set("childList", o.ChildList)
+	obj.Set("subtree", o.Subtree)
+	// Setting these to false when they would be implied throws.
+	if o.Attributes || o.AttributeOldValue || len(o.AttributeFilter) > 0 {
+		obj.Set("attributes", true)
+	}
+	if o.CharacterData || o.CharacterDataOldValue {
+		obj.Set("characterData", true)
+	}
+	if o.AttributeOldValue {
+		obj.Set("attributeOldValue", true)
+	}
+	if o.CharacterDataOldValue {
+		obj.Set("characterDataOldValue", true)
+	}
+	if len(o.AttributeFilter) > 0 {
+		var arr = make([]any, len(o.AttributeFilter))
+		for i, a := range o.AttributeFilter {
+			arr[i] = a
+		}
+		obj.Set("attributeFilter", arr)
+	}
+	return obj
+}
+
+type MutationType string
+
+const (
+	MutationChildList     MutationType = "childList"
+	MutationAttributes    MutationType = "attributes"
+	MutationCharacterData MutationType = "characterData"
+)
+
+// MutationRecord is a MutationRecord.
+type MutationRecord struct {
+	Type               MutationType
+	Target             js.Value
+	AddedNodes         []js.Value
+	RemovedNodes       []js.Value
+	PreviousSibling    js.Value
+	NextSibling        js.Value
+	AttributeName      string
+	AttributeNamespace string
+	// OldValue is only set if the old value was requested in the options.
+	OldValue string
+}
+
+func mutationRecords(list js.Value) []MutationRecord {
+	var records = make([]MutationRecord, list.Length())
+	for i := range records {
+		var r = list.Index(i)
+		records[i] = MutationRecord{
+			Type:               MutationType(r.Get("type").String()),
+			Target:             r.Get("target"),
+			AddedNodes:         nodesOf(r.Get("addedNodes")),
+			RemovedNodes:       nodesOf(r.Get("removedNodes")),
+			PreviousSibling:    r.Get("previousSibling"),
+			NextSibling:        r.Get("nextSibling"),
+			AttributeName:      stringOrEmpty(r.Get("attributeName")),
+			AttributeNamespace: stringOrEmpty(r.Get("attributeNamespace")),
+			OldValue:           stringOrEmpty(r.Get("oldValue")),
+		}
+	}
+	return records
+}
+
+// MutationObserver reports changes to the DOM.
+//
+// Records are delivered to the callback, or to the channel returned by Records if the callback is nil.
+type MutationObserver struct {
+	v        js.Value
+	fn       js.Func
+	callback func(records []MutationRecord, o *MutationObserver)
+	queue    *queue[[]MutationRecord]
+}
+
+func NewMutationObserver(callback func(records []MutationRecord, o *MutationObserver)) *MutationObserver {
+	var o = &MutationObserver{callback: callback}
+	if callback == nil {
+		o.queue = newQueue[[]MutationRecord]()
+	}
+	o.fn = js.FuncOf(func(this js.Value, args []js.Value) any {
+		var records = mutationRecords(args[0])
+		if o.callback != nil {
+			o.callback(records, o)
+		} else {
+			o.queue.push(records)
+		}
+		return nil
+	})
+	o.v = js.Global().Get("MutationObserver").New(o.fn)
+	return o
+}
+
+func (o *MutationObserver) Records() <-chan []MutationRecord {
+	if o.queue == nil {
+		return nil
+	}
+	return o.queue.out
+}
+
+func (o *MutationObserver) Observe(target js.Value, opts MutationObserverOptions) *MutationObserver {
+	o.v.Call("observe", target, opts.MarshalJS())
+	return o
+}
+
+// TakeRecords returns the pending records, which are then not delivered.
+func (o *MutationObserver) TakeRecords() []MutationRecord {
+	return mutationRecords(o.v.Call("takeRecords"))
+}
+
+// Disconnect stops observing all targets, and releases the callback.
+func (o *MutationObserver) Disconnect() {
+	if o.v.IsUndefined() {
+		return
+	}
+	o.v.Call("disconnect")
+	o.v = js.Undefined()
+	o.fn.Release()
+	if o.queue != nil {
+		o.queue.close()
+	}
+}
@@ -0,0 +1,362 @@
+package dom
+
+import (
+	"strings"
+
+	"github.com/Nigel2392/jsext/v2/errs"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+// Policy is an allowlist of the HTML which is allowed through the sanitizer.
+//
+// Anything which is not explicitly allowed is removed;
+// elements which are not allowed are unwrapped, keeping their text,
+// unless they are listed in DropContent.
+type Policy struct {
+	// Allowed tags, lower case.
+	Tags map[string]bool
+	// Attributes allowed on any allowed tag.
+	Attributes map[string]bool
+	// Attributes allowed per tag.
+	TagAttributes map[string]map[string]bool
+	// Attributes which contain URLs, their scheme is checked against URLSchemes.
+	URLAttributes map[string]bool
+	// Allowed URL schemes, for example "https" or "mailto".
+	URLSchemes map[string]bool
+	// Allowed CSS properties in style attributes, the style attribute is removed if empty.
+	CSSProperties map[string]bool
+	// Tags which are removed together with their content.
+	DropContent map[string]bool
+
+	AllowRelativeURLs   bool
+	AllowDataAttributes bool
+	AllowComments       bool
+
+	trusted     js.Value
+	passthrough bool
+}
+
+// NewPolicy returns an empty policy, which only allows text.
+func NewPolicy() *Policy {
+	return &Policy{
+		Tags:          make(map[string]bool),
+		Attributes:    make(map[string]bool),
+		TagAttributes: make(map[string]map[string]bool),
+		URLAttributes: set("href", "src", "cite", "action", "formaction", "poster", "background", "xlink:href"),
+		URLSchemes:    make(map[string]bool),
+		CSSProperties: make(map[string]bool),
+		DropContent: set(
+			"script", "style", "template", "iframe", "object", "embed",
+			"noscript", "noembed", "noframes", "frame", "frameset",
+			"applet", "title", "head", "meta", "link", "base",
+			"svg", "math", "textarea", "select", "option",
+		),
+		trusted: js.Undefined(),
+	}
+}
+
+// UGCPolicy returns a policy which is suitable for user generated content.
+//
+// It allows common formatting, links, images and tables.
+func UGCPolicy() *Policy {
+	return NewPolicy().
+		AllowTags(
+			"a", "abbr", "b", "blockquote", "br", "caption", "cite", "code",
+			"dd", "del", "details", "div", "dl", "dt", "em", "figcaption",
+			"figure", "h1", "h2", "h3", "h4", "h5", "h6", "hr", "i", "img",
+			"ins", "kbd", "li", "mark", "ol", "p", "pre", "q", "s", "small",
+			"span", "strike", "strong", "sub", "summary", "sup", "table",
+			"tbody", "td", "tfoot", "th", "thead", "tr", "u", "ul",
+		).
+		AllowAttributes("", "title", "lang", "dir").
+		AllowAttributes("a", "href").
+		AllowAttributes("img", "src", "alt", "width", "height").
+		AllowAttributes("td", "colspan", "rowspan").
+		AllowAttributes("th", "colspan", "rowspan", "scope").
+		AllowAttributes("ol", "start", "reversed").
+		AllowAttributes("blockquote", "cite").
+		AllowAttributes("q", "cite").
+		AllowURLSchemes("http", "https", "mailto").
+		AllowCSSProperties(
+			"text-align", "color", "background-color",
+			"font-weight", "font-style", "text-decoration",
+		)
+}
+
+// DefaultPolicy is used when no policy is passed to the Safe* functions.
+var DefaultPolicy = UGCPolicy()
+
+func set(values ...string) map[string]bool {
+	var m = make(map[string]bool, len(values))
+	for _, v := range values {
+		m[v] = true
+	}
+	return m
+}
+
+func (p *Policy) AllowTags(tags ...string) *Policy {
+	for _, tag := range tags {
+		p.Tags[strings.ToLower(tag)] = true
+	}
+	return p
+}
+
+// AllowAttributes allows the attributes on the tag, or on all tags if tag is empty.
+func (p *Policy) AllowAttributes(tag string, attrs ...string) *Policy {
+	var m = p.Attributes
+	if tag != "" {
+		tag = strings.ToLower(tag)
+		if p.TagAttributes[tag] == nil {
+			p.TagAttributes[tag] = make(map[string]bool)
+		}
+		m = p.TagAttributes[tag]
+	}
+	for _, attr := range attrs {
+		m[strings.ToLower(attr)] = true
+	}
+	return p
+}
+
+func (p *Policy) AllowURLSchemes(schemes ...string) *Policy {
+	p.AllowRelativeURLs = true
+	for _, scheme := range schemes {
+		p.URLSchemes[strings.ToLower(scheme)] = true
+	}
+	return p
+}
+
+func (p *Policy) AllowCSSProperties(props ...string) *Policy {
+	for _, prop := range props {
+		p.CSSProperties[strings.ToLower(prop)] = true
+	}
+	return p
+}
+
+// Sanitize parses the HTML and returns it with everything not allowed by the policy removed.
+func (p *Policy) Sanitize(html string) string {
+	var doc = p.parse(html)
+	p.SanitizeNode(doc.Body())
+	return doc.Body().Get("innerHTML").String()
+}
+
+// parse parses the HTML, when Trusted Types are enforced the
+// policy lets the unsanitized HTML through only for parsing it.
+func (p *Policy) parse(html string) Document {
+	if p.trusted.IsUndefined() || p.trusted.IsNull() {
+		return Parse(html)
+	}
+	p.passthrough = true
+	var trusted = p.trusted.Call("createHTML", html)
+	p.passthrough = false
+	return Document(domParser.Call("parseFromString", trusted, "text/html"))
+}
+
+// SanitizeDocument removes everything not allowed by the policy from the body of the document.
+func (p *Policy) SanitizeDocument(doc Document) Document {
+	p.SanitizeNode(doc.Body())
+	return doc
+}
+
+// SanitizeNode removes everything not allowed by the policy from the children of the node.
+//
+// The node itself is not checked.
+func (p *Policy) SanitizeNode(root js.Value) {
+	var nodes []Node
+	Walk(AllNodeTypes, root, func(n Node) {
+		if n.Depth > 0 {
+			nodes = append(nodes, n)
+		}
+	})
+	// Children are handled before their parents,
+	// so unwrapping an element does not skip its children.
+	for i := len(nodes) - 1; i >= 0; i-- {
+		p.sanitize(nodes[i])
+	}
+}
+
+func (p *Policy) sanitize(n Node) {
+	switch n.NodeType() {
+	case NodeTypeText:
+		return
+	case NodeTypeComment:
+		if !p.AllowComments {
+			n.Value.Call("remove")
+		}
+		return
+	case NodeTypeElement:
+	default:
+		n.Value.Call("remove")
+		return
+	}
+
+	var tag = strings.ToLower(n.NodeName())
+	if p.DropContent[tag] {
+		n.Value.Call("remove")
+		return
+	}
+	if !p.Tags[tag] {
+		unwrap(n)
+		return
+	}
+
+	var attrs = n.Get("attributes")
+	var names = make([]string, attrs.Length())
+	for i := range names {
+		names[i] = attrs.Index(i).Get("name").String()
+	}
+	for _, name := range names {
+		var value = n.Value.Call("getAttribute", name).String()
+		var lower = strings.ToLower(name)
+		switch {
+		case !p.attributeAllowed(tag, lower):
+			n.Value.Call("removeAttribute", name)
+		case lower == "style":
+			if style := p.sanitizeStyle(value); style != "" {
+				n.Value.Call("setAttribute", name, style)
+			} else {
+				n.Value.Call("removeAttribute", name)
+			}
+		case p.URLAttributes[lower] && !p.URLAllowed(value):
+			n.Value.Call("removeAttribute", name)
+		}
+	}
+}
+
+func (p *Policy) attributeAllowed(tag, attr string) bool {
+	if strings.HasPrefix(attr, "on") {
+		return false
+	}
+	if attr == "style" {
+		return len(p.CSSProperties) > 0
+	}
+	if p.AllowDataAttributes && strings.HasPrefix(attr, "data-") {
+		return true
+	}
+	return p.Attributes[attr] || p.TagAttributes[tag][attr]
+}
+
+// URLAllowed reports whether the URL is allowed by the policy.
+func (p *Policy) URLAllowed(url string) bool {
+	// Browsers ignore whitespace and control characters in the scheme.
+	var cleaned = strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, url)
+	var colon = strings.IndexByte(cleaned, ':')
+	if colon < 0 || strings.IndexAny(cleaned[:colon], "/?#") >= 0 {
+		return p.AllowRelativeURLs
+	}
+	return p.URLSchemes[strings.ToLower(cleaned[:colon])]
+}
+
+func (p *Policy) sanitizeStyle(style string) string {
+	var decls []string
+	for _, decl := range strings.Split(style, ";") {
+		var prop, value, ok = strings.Cut(decl, ":")
+		if !ok {
+			continue
+		}
+		prop = strings.ToLower(strings.TrimSpace(prop))
+		value = strings.TrimSpace(value)
+		if !p.CSSProperties[prop] || !safeCSSValue(value) {
+			continue
+		}
+		decls = append(decls, prop+": "+value)
+	}
+	return strings.Join(decls, "; ")
+}
+
+func safeCSSValue(value string) bool {
+	var lower = strings.ToLower(value)
+	for _, bad := range []string{"url(", "expression(", "javascript:", "\\", "@import", "<", ">"} {
+		if strings.Contains(lower, bad) {
+			return false
+		}
+	}
+	return value != ""
+}
+
+// unwrap replaces the element with its children.
+func unwrap(n Node) {
+	var parent = n.Get("parentNode")
+	for {
+		var child = n.Get("firstChild")
+		if child.IsNull() {
+			break
+		}
+		parent.Call("insertBefore", child, n.Value)
+	}
+	n.Value.Call("remove")
+}
+
+// TrustedTypes creates a Trusted Types policy with the name, which sanitizes HTML with p.
+//
+// Once created, HTML returns TrustedHTML instead of a string.
+// Using the name "default" makes the browser sanitize every string assigned to innerHTML.
+func (p *Policy) TrustedTypes(name string) error {
+	var trustedTypes = js.Global().Get("trustedTypes")
+	if trustedTypes.IsUndefined() || trustedTypes.IsNull() {
+		return errs.Error("dom: trusted types are not supported")
+	}
+	var createHTML = js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) == 0 {
+			return ""
+		}
+		if p.passthrough {
+			return args[0].String()
+		}
+		return p.Sanitize(args[0].String())
+	})
+	var policy js.Value
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = errs.Error("dom: could not create trusted types policy " + name)
+			}
+		}()
+		policy = trustedTypes.Call("createPolicy", name, map[string]any{
+			"createHTML": createHTML,
+		})
+	}()
+	if err != nil {
+		createHTML.Release()
+		return err
+	}
+	p.trusted = policy
+	return nil
+}
+
+// HTML sanitizes the HTML, and returns a value which can be assigned to innerHTML.
+//
+// This is TrustedHTML if a Trusted Types policy has been created, otherwise a string.
+func (p *Policy) HTML(html string) any {
+	if !p.trusted.IsUndefined() && !p.trusted.IsNull() {
+		return p.trusted.Call("createHTML", html)
+	}
+	return p.Sanitize(html)
+}
+
+// ParseSafe parses the HTML and sanitizes the body of the document.
+//
+// If no policy is given, DefaultPolicy is used.
+func ParseSafe(html string, policy ...*Policy) Document {
+	var p = DefaultPolicy
+	if len(policy) > 0 && policy[0] != nil {
+		p = policy[0]
+	}
+	return p.SanitizeDocument(p.parse(html))
+}
+
+// SetSafeInnerHTML sanitizes the HTML and sets it as the innerHTML of the element.
+//
+// If no policy is given, DefaultPolicy is used.
+func SetSafeInnerHTML(e js.Value, html string, policy ...*Policy) {
+	var p = DefaultPolicy
+	if len(policy) > 0 && policy[0] != nil {
+		p = policy[0]
+	}
+	e.Set("innerHTML", p.HTML(html))
+}
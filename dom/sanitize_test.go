@@ -0,0 +1,154 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package dom
+
+import (
+	"testing"
+
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+func TestSanitize(t *testing.T) {
+	var tests = []struct {
+		name string
+		html string
+		want string
+	}{
+		{"text", `plain & <b>bold</b>`, `plain &amp; <b>bold</b>`},
+		{"event handlers", `<p onclick="alert(1)" ONMOUSEOVER="alert(2)" title="t">x</p>`, `<p title="t">x</p>`},
+		{"unknown attribute", `<p class="c" id="i">x</p>`, `<p>x</p>`},
+		{"javascript href", `<a href="javascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"obfuscated javascript href", `<a href=" jav&#x09;ascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"upper case javascript href", `<a href="JAVASCRIPT:alert(1)">x</a>`, `<a>x</a>`},
+		{"data src", `<img src="data:text/html;base64,PHNjcmlwdD4=" alt="a">`, `<img alt="a"/>`},
+		{"allowed urls", `<a href="https://example.com/a?b#c">x</a><a href="/relative">y</a><a href="mailto:a@b.c">z</a>`,
+			`<a href="https://example.com/a?b#c">x</a><a href="/relative">y</a><a href="mailto:a@b.c">z</a>`},
+		{"script", `<p>a<script>alert(1)</script>b</p>`, `<p>ab</p>`},
+		{"nested disallowed", `<section><article><b>keep</b><iframe src="x"><p>drop</p></iframe></article></section>`, `<b>keep</b>`},
+		{"disallowed in allowed", `<ul><li><form><input value="x"><em>text</em></form></li></ul>`, `<ul><li><em>text</em></li></ul>`},
+		{"svg", `<svg><a xlink:href="javascript:alert(1)">x</a></svg>after`, `after`},
+		{"comments", `a<!-- comment -->b`, `ab`},
+		{"style", `<p style="color: red; position: fixed; background-color: blue">x</p>`, `<p style="color: red; background-color: blue">x</p>`},
+		{"style url", `<p style="color: red; background-color: url(javascript:alert(1))">x</p>`, `<p style="color: red">x</p>`},
+		{"style expression", `<p style="color: expression(alert(1))">x</p>`, `<p>x</p>`},
+		{"style escapes", `<p style="color: \72 ed">x</p>`, `<p>x</p>`},
+		{"style import", `<p style="color: red; @import 'x'">x</p>`, `<p style="color: red">x</p>`},
+	}
+	for _, test := range tests {
+		if got := UGCPolicy().Sanitize(test.html); got != test.want {
+			t.Errorf("%s: Sanitize(%s) = %s, want %s", test.name, test.html, got, test.want)
+		}
+	}
+}
+
+func TestSanitizeURLAttributes(t *testing.T) {
+	var p = NewPolicy().
+		AllowTags("use", "video").
+		AllowAttributes("use", "xlink:href").
+		AllowAttributes("video", "src", "poster").
+		AllowURLSchemes("https")
+	var tests = []struct {
+		html string
+		want string
+	}{
+		{`<use xlink:href="javascript:alert(1)"></use>`, `<use></use>`},
+		{`<use xlink:href="https://example.com/icons.svg#a"></use>`, `<use xlink:href="https://example.com/icons.svg#a"></use>`},
+		{`<video src="data:video/mp4;base64,AAAA" poster="vbscript:x"></video>`, `<video></video>`},
+		{`<video src="http://example.com/a.mp4"></video>`, `<video></video>`},
+	}
+	for _, test := range tests {
+		if got := p.Sanitize(test.html); got != test.want {
+			t.Errorf("Sanitize(%s) = %s, want %s", test.html, got, test.want)
+		}
+	}
+}
+
+func TestSanitizeEmptyPolicy(t *testing.T) {
+	var got = NewPolicy().Sanitize(`<p style="color: red" onclick="x"><a href="https://example.com">link</a></p>`)
+	if got != "link" {
+		t.Errorf("Sanitize = %s, want link", got)
+	}
+}
+
+func TestURLAllowed(t *testing.T) {
+	var p = NewPolicy().AllowURLSchemes("https")
+	var tests = map[string]bool{
+		"https://example.com":     true,
+		"HTTPS://example.com":     true,
+		"/path":                   true,
+		"path/to:x":               true,
+		"#fragment":               true,
+		"javascript:alert(1)":     false,
+		"java\nscript:alert(1)":   false,
+		"\x01javascript:alert(1)": false,
+		"data:text/html,x":        false,
+		"http://example.com":      false,
+	}
+	for url, want := range tests {
+		if got := p.URLAllowed(url); got != want {
+			t.Errorf("URLAllowed(%q) = %v, want %v", url, got, want)
+		}
+	}
+	if p := NewPolicy(); p.URLAllowed("/relative") {
+		t.Errorf("an empty policy allowed a relative URL")
+	}
+}
+
+// fakeTrustedTypes installs a trustedTypes object which records the created policies.
+func fakeTrustedTypes(t *testing.T) map[string]js.Value {
+	var policies = make(map[string]js.Value)
+	var trustedTypes = js.Global().Get("Object").New()
+	trustedTypes.Set("createPolicy", js.FuncOf(func(this js.Value, args []js.Value) any {
+		var name = args[0].String()
+		if _, ok := policies[name]; ok {
+			panic(js.Error{Value: js.Global().Get("TypeError").New("policy " + name + " exists")})
+		}
+		var createHTML = args[1].Get("createHTML")
+		var policy = js.Global().Get("Object").New()
+		policy.Set("createHTML", js.FuncOf(func(this js.Value, args []js.Value) any {
+			return createHTML.Invoke(args[0])
+		}))
+		policies[name] = policy
+		return policy
+	}))
+	js.Global().Set("trustedTypes", trustedTypes)
+	t.Cleanup(func() {
+		js.Global().Delete("trustedTypes")
+	})
+	return policies
+}
+
+func TestTrustedTypes(t *testing.T) {
+	var p = UGCPolicy()
+	if err := p.TrustedTypes("jsext"); err == nil {
+		t.Fatal("expected an error without trusted types support")
+	}
+
+	var policies = fakeTrustedTypes(t)
+	if err := p.TrustedTypes("jsext"); err != nil {
+		t.Fatal(err)
+	}
+	if err := UGCPolicy().TrustedTypes("jsext"); err == nil {
+		t.Fatal("expected an error for a duplicate policy name")
+	}
+
+	// The policy sanitizes HTML created through it.
+	var html = `<b onclick="x">a</b><script>alert(1)</script>`
+	if got := policies["jsext"].Call("createHTML", html).String(); got != "<b>a</b>" {
+		t.Errorf("createHTML = %s, want <b>a</b>", got)
+	}
+	if got := p.HTML(html).(js.Value).String(); got != "<b>a</b>" {
+		t.Errorf("HTML = %s, want <b>a</b>", got)
+	}
+	// Sanitize parses through the policy without sanitizing twice, and still sanitizes the result.
+	if got := p.Sanitize(html); got != "<b>a</b>" {
+		t.Errorf("Sanitize = %s, want <b>a</b>", got)
+	}
+
+	var div = js.Global().Get("document").Call("createElement", "div")
+	SetSafeInnerHTML(div, `<i>ok</i><img src="javascript:x">`, p)
+	if got := div.Get("innerHTML").String(); got != "<i>ok</i><img/>" {
+		t.Errorf("innerHTML = %s, want <i>ok</i><img/>", got)
+	}
+}
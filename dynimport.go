@@ -1,11 +1,9 @@
-//go:build js && wasm
-// +build js,wasm
-
 package jsext
 
 import (
 	"strings"
-	"syscall/js"
+
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 // Imports keeps track of all imported files.
@@ -1,16 +1,12 @@
-//go:build js && wasm
-// +build js,wasm
-
 package jsext
 
 import (
-	"syscall/js"
-
 	"github.com/Nigel2392/jsext/v2/export"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 // See js.go.init() for the initialization of this variable onto the global export object.
-var Runtime export.Export = export.NewFromValue(Eval("new EventTarget();").Value())
+var Runtime export.Export = export.NewFromValue(js.Global().Get("EventTarget").New())
 
 // Emit an event on the global Runtime object.
 func EventEmit(name string, args ...interface{}) Value {
@@ -21,21 +17,22 @@ func EventEmit(name string, args ...interface{}) Value {
 }
 
 // Listen for an event on the global Runtime object.
-func EventOn(name string, f func(args ...interface{})) Value {
-	return Value(Runtime.MarshalJS().Call("addEventListener", name, js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		var jsArgs = args[0].Get("args")
-		var arguments = ArrayToSlice(jsArgs)
+//
+// The returned Listener can be used to remove it again.
+func EventOn(name string, f func(args ...interface{})) *Listener {
+	return Listen(Runtime.MarshalJS(), name, func(_ js.Value, event Event) {
+		var arguments = ArrayToSlice(event.Get("args"))
 		f(arguments...)
-		return nil
-
-	})))
+	})
 }
 
 // Listen for multiple events on the global Runtime object.
-func EventOnMultiple(f func(args ...interface{}), names ...string) []Value {
-	var values []Value = make([]Value, len(names))
+//
+// The returned group can be used to remove all of the listeners at once.
+func EventOnMultiple(f func(args ...interface{}), names ...string) *Listeners {
+	var group = &Listeners{}
 	for _, name := range names {
-		values = append(values, EventOn(name, f))
+		group.Add(EventOn(name, f))
 	}
-	return values
+	return group
 }
@@ -0,0 +1,454 @@
+package export
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Nigel2392/jsext/v2/jsc"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+// Constructor is an exported class, which can be described in TypeScript.
+type Constructor interface {
+	ClassName() string
+	Value() js.Value
+	// Declare returns the declaration of the class, modifiers like "export declare " are written before class.
+	Declare(d *Declarations, indent, modifiers string) string
+	Release()
+}
+
+// instanceKey is the property of instances which holds the id of their Go value.
+const instanceKey = "__jsextInstance"
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Class exports a Go struct type as a javascript class.
+//
+// new Name(...) calls the constructor, with arguments decoded like those of a Func.
+// The exported methods of *T are methods of the instances, with a lower case first letter,
+// and the fields of T are properties with a getter and setter, named by their js, jsc or json tag.
+// Values are converted with jsc, a *T is passed as an instance of the class.
+//
+// A Go value has a single instance while it is alive, returning the same *T returns the same object.
+// The Go value is released when the instance is garbage collected, with a FinalizationRegistry.
+//
+//	type Counter struct {
+//		Count int `js:"count"`
+//	}
+//
+//	func (c *Counter) Add(n int) int {
+//		c.Count += n
+//		return c.Count
+//	}
+//
+//	export.NewClass("Counter", func(start int) (*Counter, error) {
+//		return &Counter{Count: start}, nil
+//	}).Register()
+//
+//	// const c = new Counter(1); c.add(2); c.count === 3
+type Class[T any] struct {
+	Name string
+	// Doc is written as a comment above the class in TypeScript declarations.
+	Doc string
+
+	construct func(args []js.Value) (*T, error)
+	params    []Param
+	instances map[int]*T
+	// ids and refs find the live instance of a Go value, refs are WeakRefs so instances can still be collected.
+	ids      map[*T]int
+	refs     map[int]js.Value
+	nextID   int
+	value    js.Value
+	registry js.Value
+	funcs    []js.Func
+}
+
+func NewClass[T, Args any](name string, constructor func(args Args) (*T, error)) *Class[T] {
+	return &Class[T]{
+		Name: name,
+		construct: func(args []js.Value) (*T, error) {
+			var a Args
+			if err := decodeArgs(args, &a); err != nil {
+				return nil, err
+			}
+			return constructor(a)
+		},
+		params:    (&Func[Args, *T]{}).Signature().Params,
+		instances: make(map[int]*T),
+		ids:       make(map[*T]int),
+		refs:      make(map[int]js.Value),
+	}
+}
+
+func (c *Class[T]) ClassName() string {
+	return c.Name
+}
+
+// Register sets the class on the window.
+func (c *Class[T]) Register() *Class[T] {
+	js.Global().Set(c.Name, c.Value())
+	return c
+}
+
+// Len returns the number of instances which have not been released.
+func (c *Class[T]) Len() int {
+	return len(c.instances)
+}
+
+// Get returns the Go value of an instance.
+func (c *Class[T]) Get(instance js.Value) (*T, bool) {
+	if instance.Type() != js.TypeObject {
+		return nil, false
+	}
+	var id = instance.Get(instanceKey)
+	if id.Type() != js.TypeNumber {
+		return nil, false
+	}
+	var t, ok = c.instances[id.Int()]
+	return t, ok
+}
+
+// Wrap returns the instance of a Go value, a new instance is created if it has none.
+func (c *Class[T]) Wrap(t *T) js.Value {
+	if id, ok := c.ids[t]; ok {
+		if instance := c.refs[id].Call("deref"); !instance.IsUndefined() {
+			return instance
+		}
+	}
+	var instance = js.Global().Get("Object").Call("create", c.Value().Get("prototype"))
+	c.bind(instance, t)
+	return instance
+}
+
+// Dispose releases the Go value of an instance, without waiting for it to be garbage collected.
+func (c *Class[T]) Dispose(instance js.Value) {
+	var id = instance.Get(instanceKey)
+	if id.Type() == js.TypeNumber {
+		c.forget(id.Int())
+		c.registry.Call("unregister", instance)
+	}
+}
+
+// Release releases the functions of the class, its instances can no longer be used.
+func (c *Class[T]) Release() {
+	// Live instances are unregistered first, the cleanup callback is released below.
+	for _, ref := range c.refs {
+		if instance := ref.Call("deref"); !instance.IsUndefined() {
+			c.registry.Call("unregister", instance)
+		}
+	}
+	for _, f := range c.funcs {
+		f.Release()
+	}
+	c.funcs = nil
+	c.instances = make(map[int]*T)
+	c.ids = make(map[*T]int)
+	c.refs = make(map[int]js.Value)
+	c.value = js.Undefined()
+}
+
+func (c *Class[T]) bind(instance js.Value, t *T) {
+	c.nextID++
+	c.instances[c.nextID] = t
+	c.ids[t] = c.nextID
+	c.refs[c.nextID] = js.Global().Get("WeakRef").New(instance)
+	var desc = js.Global().Get("Object").New()
+	desc.Set("value", c.nextID)
+	js.Global().Get("Object").Call("defineProperty", instance, instanceKey, desc)
+	c.registry.Call("register", instance, c.nextID, instance)
+}
+
+// forget removes the Go value of the instance with the id.
+func (c *Class[T]) forget(id int) {
+	if t, ok := c.instances[id]; ok && c.ids[t] == id {
+		delete(c.ids, t)
+	}
+	delete(c.instances, id)
+	delete(c.refs, id)
+}
+
+// Value returns the constructor of the class.
+func (c *Class[T]) Value() js.Value {
+	if !c.value.IsUndefined() {
+		return c.value
+	}
+	c.registry = js.Global().Get("FinalizationRegistry").New(c.funcOf(func(this js.Value, args []js.Value) any {
+		c.forget(args[0].Int())
+		return nil
+	}))
+
+	c.value = wrap(c.funcOf(func(this js.Value, args []js.Value) any {
+		if !this.InstanceOf(c.value) {
+			return throw(js.Global().Get("TypeError").New("Class constructor " + c.Name + " cannot be invoked without 'new'"))
+		}
+		return c.call(func() (js.Value, error) {
+			var t, err = c.construct(args)
+			if err != nil {
+				return js.Undefined(), err
+			}
+			c.bind(this, t)
+			return js.Undefined(), nil
+		})
+	}))
+	var proto = c.value.Get("prototype")
+	if proto.Type() != js.TypeObject {
+		proto = js.Global().Get("Object").New()
+		c.value.Set("prototype", proto)
+	}
+	var constructor = js.Global().Get("Object").New()
+	constructor.Set("value", c.value)
+	constructor.Set("writable", true)
+	constructor.Set("configurable", true)
+	js.Global().Get("Object").Call("defineProperty", proto, "constructor", constructor)
+
+	var methods = make(map[string]bool)
+	var ptr = reflect.TypeOf((*T)(nil))
+	for i := 0; i < ptr.NumMethod(); i++ {
+		var m = ptr.Method(i)
+		if m.Name == "MarshalJS" || m.Name == "UnmarshalJS" {
+			continue
+		}
+		var name = lowerFirst(m.Name)
+		methods[name] = true
+		proto.Set(name, c.method(m.Index))
+	}
+
+	var typ = ptr.Elem()
+	if typ.Kind() != reflect.Struct {
+		return c.value
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		var field = typ.Field(i)
+		var name, _, ok = jsc.FieldName(field)
+		if !ok || !field.IsExported() || methods[name] {
+			continue
+		}
+		js.Global().Get("Object").Call("defineProperty", proto, name, c.property(i))
+	}
+	return c.value
+}
+
+func (c *Class[T]) funcOf(fn func(this js.Value, args []js.Value) any) js.Func {
+	var f = js.FuncOf(fn)
+	c.funcs = append(c.funcs, f)
+	return f
+}
+
+// call calls fn, errors and panics are thrown.
+func (c *Class[T]) call(fn func() (js.Value, error)) any {
+	var v, err = c.protect(fn)
+	if err != nil {
+		return throw(errorValue(err))
+	}
+	return v
+}
+
+func (c *Class[T]) protect(fn func() (js.Value, error)) (v js.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			v, err = js.Undefined(), fmt.Errorf("%s: panic: %v", c.Name, r)
+		}
+	}()
+	return fn()
+}
+
+func (c *Class[T]) instance(this js.Value) (*T, error) {
+	var t, ok = c.Get(this)
+	if !ok {
+		return nil, js.Error{Value: js.Global().Get("TypeError").New("Illegal invocation: not a live " + c.Name)}
+	}
+	return t, nil
+}
+
+func (c *Class[T]) method(index int) js.Value {
+	return wrap(c.funcOf(func(this js.Value, args []js.Value) any {
+		return c.call(func() (js.Value, error) {
+			var t, err = c.instance(this)
+			if err != nil {
+				return js.Undefined(), err
+			}
+			var m = reflect.ValueOf(t).Method(index)
+			in, err := c.arguments(m.Type(), args)
+			if err != nil {
+				return js.Undefined(), err
+			}
+			return c.results(m.Call(in))
+		})
+	}))
+}
+
+func (c *Class[T]) property(index int) js.Value {
+	var desc = js.Global().Get("Object").New()
+	desc.Set("enumerable", true)
+	desc.Set("configurable", true)
+	desc.Set("get", wrap(c.funcOf(func(this js.Value, args []js.Value) any {
+		return c.call(func() (js.Value, error) {
+			var t, err = c.instance(this)
+			if err != nil {
+				return js.Undefined(), err
+			}
+			return c.valueOf(reflect.ValueOf(t).Elem().Field(index))
+		})
+	})))
+	desc.Set("set", wrap(c.funcOf(func(this js.Value, args []js.Value) any {
+		return c.call(func() (js.Value, error) {
+			var t, err = c.instance(this)
+			if err != nil {
+				return js.Undefined(), err
+			}
+			var field = reflect.ValueOf(t).Elem().Field(index)
+			var v = reflect.New(field.Type()).Elem()
+			if len(args) > 0 {
+				if err = c.decode(args[0], v); err != nil {
+					return js.Undefined(), err
+				}
+			}
+			field.Set(v)
+			return js.Undefined(), nil
+		})
+	})))
+	return desc
+}
+
+func (c *Class[T]) arguments(ft reflect.Type, args []js.Value) ([]reflect.Value, error) {
+	var in = make([]reflect.Value, 0, len(args))
+	for i := 0; i < ft.NumIn(); i++ {
+		var typ = ft.In(i)
+		if ft.IsVariadic() && i == ft.NumIn()-1 {
+			for j := i; j < len(args); j++ {
+				var v = reflect.New(typ.Elem()).Elem()
+				if err := c.decode(args[j], v); err != nil {
+					return nil, &argumentError{index: j, err: err}
+				}
+				in = append(in, v)
+			}
+			break
+		}
+		var v = reflect.New(typ).Elem()
+		if i < len(args) {
+			if err := c.decode(args[i], v); err != nil {
+				return nil, &argumentError{index: i, err: err}
+			}
+		}
+		in = append(in, v)
+	}
+	return in, nil
+}
+
+func (c *Class[T]) results(out []reflect.Value) (js.Value, error) {
+	if n := len(out); n > 0 && out[n-1].Type() == errorType {
+		if !out[n-1].IsNil() {
+			return js.Undefined(), out[n-1].Interface().(error)
+		}
+		out = out[:n-1]
+	}
+	switch len(out) {
+	case 0:
+		return js.Undefined(), nil
+	case 1:
+		return c.valueOf(out[0])
+	}
+	var array = js.Global().Get("Array").New(len(out))
+	for i, o := range out {
+		var v, err = c.valueOf(o)
+		if err != nil {
+			return js.Undefined(), err
+		}
+		array.SetIndex(i, v)
+	}
+	return array, nil
+}
+
+// decode decodes an argument, instances of the class are passed as their Go value.
+func (c *Class[T]) decode(src js.Value, dst reflect.Value) error {
+	if dst.Type() == reflect.TypeOf((*T)(nil)) {
+		if t, ok := c.Get(src); ok {
+			dst.Set(reflect.ValueOf(t))
+			return nil
+		}
+	}
+	return decode(src, dst)
+}
+
+// valueOf encodes a result, a *T is returned as an instance of the class.
+func (c *Class[T]) valueOf(v reflect.Value) (js.Value, error) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func:
+		if v.IsNil() {
+			return js.Null(), nil
+		}
+	}
+	if t, ok := v.Interface().(*T); ok {
+		return c.Wrap(t), nil
+	}
+	return jsc.ValueOf(v.Interface())
+}
+
+func (c *Class[T]) Declare(d *Declarations, indent, modifiers string) string {
+	var ptr = reflect.TypeOf((*T)(nil))
+	d.types[ptr.Elem()] = c.Name
+	d.names[c.Name] = true
+
+	var b strings.Builder
+	writeDoc(&b, c.Doc, indent)
+	b.WriteString(indent + modifiers + "class " + c.Name + " {\n")
+	b.WriteString(indent + "\tconstructor" + d.params(c.params) + ";\n")
+
+	var methods = make(map[string]bool)
+	for i := 0; i < ptr.NumMethod(); i++ {
+		var m = ptr.Method(i)
+		if m.Name != "MarshalJS" && m.Name != "UnmarshalJS" {
+			methods[lowerFirst(m.Name)] = true
+		}
+	}
+	if typ := ptr.Elem(); typ.Kind() == reflect.Struct {
+		for i := 0; i < typ.NumField(); i++ {
+			var field = typ.Field(i)
+			var name, _, ok = jsc.FieldName(field)
+			if !ok || !field.IsExported() || methods[name] {
+				continue
+			}
+			b.WriteString(indent + "\t" + property(name) + ": " + d.Type(field.Type) + ";\n")
+		}
+	}
+	for i := 0; i < ptr.NumMethod(); i++ {
+		var m = ptr.Method(i)
+		if !methods[lowerFirst(m.Name)] {
+			continue
+		}
+		b.WriteString(indent + "\t" + property(lowerFirst(m.Name)) + c.methodSignature(d, m.Type) + ";\n")
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// methodSignature returns the TypeScript signature of a method, its first input is the receiver.
+func (c *Class[T]) methodSignature(d *Declarations, ft reflect.Type) string {
+	var params []string
+	for i := 1; i < ft.NumIn(); i++ {
+		var name = "arg" + strconv.Itoa(i)
+		if ft.IsVariadic() && i == ft.NumIn()-1 {
+			params = append(params, "..."+name+": "+arrayOf(d.Type(ft.In(i).Elem())))
+			continue
+		}
+		params = append(params, name+": "+d.Type(ft.In(i)))
+	}
+	var results []string
+	for i := 0; i < ft.NumOut(); i++ {
+		if i == ft.NumOut()-1 && ft.Out(i) == errorType {
+			break
+		}
+		results = append(results, d.Type(ft.Out(i)))
+	}
+	var result = "void"
+	switch len(results) {
+	case 1:
+		result = results[0]
+	case 0:
+	default:
+		result = "[" + strings.Join(results, ", ") + "]"
+	}
+	return "(" + strings.Join(params, ", ") + "): " + result
+}
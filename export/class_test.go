@@ -0,0 +1,51 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package export
+
+import (
+	"testing"
+)
+
+type counter struct {
+	Count int `js:"count"`
+}
+
+func (c *counter) Add(n int) int {
+	c.Count += n
+	return c.Count
+}
+
+func (c *counter) Self() *counter {
+	return c
+}
+
+func TestClassWrapSameInstance(t *testing.T) {
+	var class = NewClass("Counter", func(start int) (*counter, error) {
+		return &counter{Count: start}, nil
+	})
+	defer class.Release()
+
+	var c = class.Value().New(1)
+	if got := c.Call("add", 2).Int(); got != 3 {
+		t.Errorf("add(2) = %d, want 3", got)
+	}
+	if got := c.Get("count").Int(); got != 3 {
+		t.Errorf("count = %d, want 3", got)
+	}
+	if !c.Call("self").Equal(c) {
+		t.Errorf("self() returned a new object")
+	}
+
+	var g = &counter{}
+	if !class.Wrap(g).Equal(class.Wrap(g)) {
+		t.Errorf("Wrap returned a new object for the same value")
+	}
+	if class.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", class.Len())
+	}
+	class.Dispose(class.Wrap(g))
+	if class.Len() != 1 {
+		t.Errorf("Len() = %d after Dispose, want 1", class.Len())
+	}
+}
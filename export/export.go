@@ -1,10 +1,7 @@
-//go:build js && wasm
-// +build js,wasm
-
 package export
 
 import (
-	"syscall/js"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 type simpleError string
@@ -0,0 +1,327 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/Nigel2392/jsext/v2/jsc"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+// Void is used as the return type of functions which do not return a value.
+type Void = struct{}
+
+// Typed is a function with a signature, which can be described in TypeScript.
+type Typed interface {
+	MarshalJS() js.Func
+	Signature() Signature
+	Release()
+}
+
+// API is an export of typed functions, which can be described in TypeScript.
+//
+//	var api = export.NewAPI("myApi", getUser, listUsers)
+//	os.WriteFile("myApi.d.ts", []byte(api.TypeScript()), 0644)
+type API struct {
+	Export
+	name    string
+	funcs   []Typed
+	classes []Constructor
+}
+
+// NewAPI registers an export with the functions on the window.
+func NewAPI(name string, funcs ...Typed) *API {
+	var a = &API{Export: NewExport(name), name: name}
+	a.Add(funcs...)
+	return a
+}
+
+func (a *API) Add(funcs ...Typed) {
+	for _, f := range funcs {
+		a.Set(f.Signature().Name, f.MarshalJS().Value)
+		a.funcs = append(a.funcs, f)
+	}
+}
+
+// AddClass sets the constructors of the classes on the export.
+func (a *API) AddClass(classes ...Constructor) {
+	for _, c := range classes {
+		a.Set(c.ClassName(), c.Value())
+		a.classes = append(a.classes, c)
+	}
+}
+
+func (a *API) Funcs() []Typed {
+	return a.funcs
+}
+
+// TypeScript returns the declarations of the export, for a .d.ts file.
+func (a *API) TypeScript() string {
+	return typeScript(a.name, a.funcs, a.classes)
+}
+
+// Remove removes the export from the window and releases the functions.
+func (a *API) Remove() error {
+	if err := a.Export.Remove(); err != nil {
+		return err
+	}
+	for _, f := range a.funcs {
+		f.Release()
+	}
+	for _, c := range a.classes {
+		c.Release()
+	}
+	return nil
+}
+
+// Func is a Go function which is called from javascript with typed arguments.
+//
+// If Args is a struct, its fields are the positional arguments of the function,
+// named by their js, jsc or json tag. Pointer fields are optional.
+// Other types are passed as the only argument, and struct{} takes no arguments.
+//
+// Arguments are decoded and the result is encoded with jsc.
+// A returned error is thrown as an Error, or rejects the promise of an async function.
+// Arguments which can not be decoded are thrown as a TypeError.
+//
+//	type GetUser struct {
+//		ID int `js:"id"`
+//	}
+//
+//	var getUser = export.NewFunc("getUser", func(args GetUser) (User, error) {
+//		return db.User(args.ID)
+//	})
+//	jsext.RegisterFunc("getUser", getUser)
+type Func[Args, Ret any] struct {
+	Name string
+	// Doc is written as a comment above the function in TypeScript declarations.
+	Doc string
+	// Async functions run in a goroutine and return a Promise, they can block, for example on fetch requests.
+	Async bool
+
+	fn     func(Args) (Ret, error)
+	jsFunc js.Func
+	value  js.Value
+}
+
+func NewFunc[Args, Ret any](name string, fn func(args Args) (Ret, error)) *Func[Args, Ret] {
+	return &Func[Args, Ret]{Name: name, fn: fn}
+}
+
+// NewAsyncFunc returns a Func which returns a Promise.
+func NewAsyncFunc[Args, Ret any](name string, fn func(args Args) (Ret, error)) *Func[Args, Ret] {
+	return &Func[Args, Ret]{Name: name, fn: fn, Async: true}
+}
+
+// Value returns the function which is called from javascript.
+func (f *Func[Args, Ret]) Value() js.Value {
+	if f.value.IsUndefined() {
+		f.jsFunc = js.FuncOf(f.call)
+		f.value = wrap(f.jsFunc)
+	}
+	return f.value
+}
+
+// MarshalJS returns the function as a js.Func, so it can be passed to jsext.RegisterFunc.
+//
+// Errors are thrown by a wrapper around the underlying js.Func, use Release to release it.
+func (f *Func[Args, Ret]) MarshalJS() js.Func {
+	return js.Func{Value: f.Value()}
+}
+
+// Release frees the js.Func, the function can no longer be called from javascript.
+func (f *Func[Args, Ret]) Release() {
+	if !f.value.IsUndefined() {
+		f.jsFunc.Release()
+		f.value = js.Undefined()
+	}
+}
+
+// Call calls the Go function with javascript arguments, a panic is returned as an error.
+func (f *Func[Args, Ret]) Call(args ...js.Value) (js.Value, error) {
+	var a Args
+	if err := decodeArgs(args, &a); err != nil {
+		return js.Undefined(), err
+	}
+	return f.invoke(a)
+}
+
+func (f *Func[Args, Ret]) invoke(args Args) (v js.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			v, err = js.Undefined(), fmt.Errorf("%s: panic: %v", f.Name, r)
+		}
+	}()
+	var ret Ret
+	if ret, err = f.fn(args); err != nil {
+		return js.Undefined(), err
+	}
+	if isVoid(reflect.TypeOf(ret)) {
+		return js.Undefined(), nil
+	}
+	return jsc.ValueOf(ret)
+}
+
+func (f *Func[Args, Ret]) call(this js.Value, args []js.Value) any {
+	if !f.Async {
+		var v, err = f.Call(args...)
+		if err != nil {
+			return throw(errorValue(err))
+		}
+		return v
+	}
+
+	// The arguments are decoded before returning, they may not be valid after.
+	var a Args
+	if err := decodeArgs(args, &a); err != nil {
+		return js.Global().Get("Promise").Call("reject", errorValue(err))
+	}
+	var executor = js.FuncOf(func(this js.Value, p []js.Value) any {
+		var resolve, reject = p[0], p[1]
+		go func() {
+			var v, err = f.invoke(a)
+			if err != nil {
+				reject.Invoke(errorValue(err))
+				return
+			}
+			resolve.Invoke(v)
+		}()
+		return nil
+	})
+	defer executor.Release()
+	return js.Global().Get("Promise").New(executor)
+}
+
+func (f *Func[Args, Ret]) Signature() Signature {
+	var args = reflect.TypeOf((*Args)(nil)).Elem()
+	var s = Signature{
+		Name:   f.Name,
+		Doc:    f.Doc,
+		Async:  f.Async,
+		Result: reflect.TypeOf((*Ret)(nil)).Elem(),
+	}
+	if !isParams(args) {
+		s.Params = []Param{{Name: "value", Type: args}}
+		return s
+	}
+	for i := 0; i < args.NumField(); i++ {
+		var field = args.Field(i)
+		var name, omitEmpty, ok = jsc.FieldName(field)
+		if !ok || !field.IsExported() {
+			continue
+		}
+		if name == field.Name {
+			name = lowerFirst(name)
+		}
+		s.Params = append(s.Params, Param{
+			Name:     name,
+			Type:     field.Type,
+			Optional: omitEmpty || field.Type.Kind() == reflect.Ptr,
+		})
+	}
+	return s
+}
+
+var jsValueType = reflect.TypeOf(js.Value{})
+
+// isParams reports whether the fields of the type are the arguments of a function.
+func isParams(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != jsValueType
+}
+
+func isVoid(t reflect.Type) bool {
+	return t == nil || t.Kind() == reflect.Struct && t.NumField() == 0
+}
+
+// argumentError is returned when an argument can not be decoded, it is thrown as a TypeError.
+type argumentError struct {
+	index int
+	name  string
+	err   error
+}
+
+func (e *argumentError) Error() string {
+	if e.name == "" {
+		return fmt.Sprintf("argument %d: %v", e.index, e.err)
+	}
+	return fmt.Sprintf("argument %d (%s): %v", e.index, e.name, e.err)
+}
+
+func (e *argumentError) Unwrap() error {
+	return e.err
+}
+
+// decodeArgs scans the arguments into the fields of a struct, or into the value.
+func decodeArgs[Args any](args []js.Value, dst *Args) error {
+	var v = reflect.ValueOf(dst).Elem()
+	if !isParams(v.Type()) {
+		if len(args) == 0 {
+			return nil
+		}
+		if err := decode(args[0], v); err != nil {
+			return &argumentError{err: err}
+		}
+		return nil
+	}
+	var i int
+	for f := 0; f < v.NumField(); f++ {
+		var field = v.Type().Field(f)
+		if _, _, ok := jsc.FieldName(field); !ok || !field.IsExported() {
+			continue
+		}
+		if i >= len(args) {
+			break
+		}
+		if err := decode(args[i], v.Field(f)); err != nil {
+			return &argumentError{index: i, name: field.Name, err: err}
+		}
+		i++
+	}
+	return nil
+}
+
+// decode scans the value into dst, jsc panics when a value has the wrong type.
+func decode(src js.Value, dst reflect.Value) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	if src.IsUndefined() || src.IsNull() {
+		return nil
+	}
+	if dst.Type() == jsValueType {
+		dst.Set(reflect.ValueOf(src))
+		return nil
+	}
+	if dst.Kind() == reflect.Ptr {
+		dst.Set(reflect.New(dst.Type().Elem()))
+		return decode(src, dst.Elem())
+	}
+	return jsc.Scan(src, dst.Addr().Interface())
+}
+
+// errorValue returns a javascript Error with the message of the error.
+func errorValue(err error) js.Value {
+	if jsErr, ok := err.(js.Error); ok {
+		return jsErr.Value
+	}
+	var argErr *argumentError
+	if errors.As(err, &argErr) {
+		return js.Global().Get("TypeError").New(err.Error())
+	}
+	return js.Global().Get("Error").New(err.Error())
+}
+
+// lowerFirst lower cases the first word of a Go name, "ID" becomes "id" and "URLPath" becomes "urlPath".
+func lowerFirst(s string) string {
+	var b = []byte(s)
+	for i := 0; i < len(b) && b[i] >= 'A' && b[i] <= 'Z'; i++ {
+		if i > 0 && i+1 < len(b) && b[i+1] >= 'a' && b[i+1] <= 'z' {
+			break
+		}
+		b[i] += 'a' - 'A'
+	}
+	return string(b)
+}
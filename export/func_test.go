@@ -0,0 +1,135 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package export
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+type getUser struct {
+	ID   int    `js:"id"`
+	Name string `js:"name"`
+}
+
+func newGetUser(async bool) *Func[getUser, string] {
+	var fn = func(args getUser) (string, error) {
+		if args.ID == 0 {
+			return "", errors.New("no user")
+		}
+		return args.Name, nil
+	}
+	if async {
+		return NewAsyncFunc("getUser", fn)
+	}
+	return NewFunc("getUser", fn)
+}
+
+// settled waits for the promise, an async function settles it in a goroutine.
+func settled(t *testing.T, promise js.Value) (bool, js.Value) {
+	t.Helper()
+	type result struct {
+		fulfilled bool
+		value     js.Value
+	}
+	var c = make(chan result, 1)
+	promise.Call("then", js.FuncOf(func(this js.Value, args []js.Value) any {
+		c <- result{true, args[0]}
+		return nil
+	}), js.FuncOf(func(this js.Value, args []js.Value) any {
+		c <- result{false, args[0]}
+		return nil
+	}))
+	select {
+	case r := <-c:
+		return r.fulfilled, r.value
+	case <-time.After(time.Second):
+		t.Fatal("promise did not settle")
+	}
+	return false, js.Undefined()
+}
+
+func thrown(fn func()) (err js.Value) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = r.(js.Error).Value
+		}
+	}()
+	fn()
+	return js.Undefined()
+}
+
+func TestFuncCall(t *testing.T) {
+	var f = newGetUser(false)
+	if got := f.Value().Invoke(1, "alice").String(); got != "alice" {
+		t.Errorf("getUser(1, alice) = %q, want alice", got)
+	}
+	var err = thrown(func() { f.Value().Invoke(0, "alice") })
+	if err.Get("name").String() != "Error" || err.Get("message").String() != "no user" {
+		t.Errorf("getUser(0) threw %v: %v, want Error: no user", err.Get("name"), err.Get("message"))
+	}
+}
+
+func TestFuncCallBadArgument(t *testing.T) {
+	var f = newGetUser(false)
+	var err = thrown(func() { f.Value().Invoke("abc") })
+	if err.Get("name").String() != "TypeError" {
+		t.Errorf("getUser(abc) threw %v, want a TypeError", err.Get("name"))
+	}
+	if _, err := f.Call(js.ValueOf("abc")); err == nil {
+		t.Errorf("Call(abc) returned no error")
+	}
+}
+
+func TestAsyncFunc(t *testing.T) {
+	var f = newGetUser(true)
+	var ok, result = settled(t, f.Value().Invoke(1, "bob"))
+	if !ok || result.String() != "bob" {
+		t.Errorf("getUser(1, bob) settled with %v, %v, want bob", ok, result)
+	}
+	ok, result = settled(t, f.Value().Invoke(0))
+	if ok || result.Get("message").String() != "no user" {
+		t.Errorf("getUser(0) settled with %v, %v, want a rejection", ok, result)
+	}
+	ok, result = settled(t, f.Value().Invoke("abc"))
+	if ok || result.Get("name").String() != "TypeError" {
+		t.Errorf("getUser(abc) settled with %v, %v, want a TypeError", ok, result)
+	}
+}
+
+func TestLowerFirst(t *testing.T) {
+	var tests = map[string]string{
+		"ID":      "id",
+		"URLPath": "urlPath",
+		"Name":    "name",
+		"userID":  "userID",
+		"A":       "a",
+		"":        "",
+	}
+	for in, want := range tests {
+		if got := lowerFirst(in); got != want {
+			t.Errorf("lowerFirst(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSignatureParamNames(t *testing.T) {
+	type args struct {
+		ID      int
+		URLPath string
+		Limit   *int `js:"max"`
+	}
+	var f = NewFunc("find", func(args) (Void, error) { return Void{}, nil })
+	var names []string
+	for _, p := range f.Signature().Params {
+		names = append(names, p.Name)
+	}
+	if want := []string{"id", "urlPath", "max"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("params = %v, want %v", names, want)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package export
+
+import (
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+// throw throws the error to the javascript caller, jsv turns a panic with a js.Error into a throw.
+func throw(err js.Value) any {
+	panic(js.Error{Value: err})
+}
+
+// wrap returns the function which is called from javascript.
+func wrap(fn js.Func) js.Value {
+	return fn.Value
+}
@@ -0,0 +1,34 @@
+//go:build js && wasm
+// +build js,wasm
+
+package export
+
+import (
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+// A Go callback can not throw, it returns the error in an object which the wrapper throws.
+const throwKey = "__jsextThrow"
+
+var wrapper js.Value
+
+// throw returns the error to the wrapper of the function, which throws it.
+func throw(err js.Value) any {
+	var o = js.Global().Get("Object").New()
+	o.Set(throwKey, err)
+	return o
+}
+
+// wrap returns the function which is called from javascript.
+func wrap(fn js.Func) js.Value {
+	if wrapper.IsUndefined() {
+		wrapper = js.Global().Get("Function").New("f", `return function() {
+	const result = f.apply(this, arguments);
+	if (result instanceof Object && "`+throwKey+`" in result) {
+		throw result.`+throwKey+`;
+	}
+	return result;
+};`)
+	}
+	return wrapper.Invoke(fn)
+}
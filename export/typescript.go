@@ -0,0 +1,310 @@
+package export
+
+import (
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Nigel2392/jsext/v2/jsc"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+// Signature describes the arguments and result of a Typed function.
+type Signature struct {
+	Name   string
+	Doc    string
+	Params []Param
+	Result reflect.Type
+	Async  bool
+}
+
+type Param struct {
+	Name     string
+	Type     reflect.Type
+	Optional bool
+}
+
+// Declarations generates TypeScript declarations of Go types.
+//
+// Structs are declared as interfaces named after the Go type, with the fields jsc encodes.
+type Declarations struct {
+	types map[reflect.Type]string
+	names map[string]bool
+	decls []string
+}
+
+func NewDeclarations() *Declarations {
+	return &Declarations{
+		types: make(map[reflect.Type]string),
+		names: make(map[string]bool),
+	}
+}
+
+// Type returns the TypeScript type of the Go type, declaring the interfaces it uses.
+func (d *Declarations) Type(t reflect.Type) string {
+	if t == nil {
+		return "void"
+	}
+	switch t {
+	case jsValueType, reflect.TypeOf(js.Func{}):
+		return "any"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			if jsc.BASE64 {
+				return "string"
+			}
+			return "Uint8Array"
+		}
+		return arrayOf(d.Type(t.Elem()))
+	case reflect.Map:
+		return "Record<" + d.Type(t.Key()) + ", " + d.Type(t.Elem()) + ">"
+	case reflect.Ptr:
+		return d.Type(t.Elem()) + " | null"
+	case reflect.Func:
+		return "(...args: any[]) => any"
+	case reflect.Struct:
+		if t.NumField() == 0 {
+			return "void"
+		}
+		if name, ok := d.types[t]; ok {
+			return name
+		}
+		if t.Name() == "" {
+			return d.object(t, "")
+		}
+		var name = d.name(t)
+		d.types[t] = name
+		// The body is generated after the name is known, so recursive types refer to it.
+		var body = d.object(t, "")
+		d.decls = append(d.decls, "export interface "+name+" "+body)
+		return name
+	}
+	return "any"
+}
+
+// name returns a unique name for the struct, prefixed with its package if the name is taken.
+func (d *Declarations) name(t reflect.Type) string {
+	var name = strings.ReplaceAll(strings.ReplaceAll(t.Name(), "[", "_"), "]", "")
+	if d.names[name] {
+		var pkg = t.PkgPath()
+		pkg = pkg[strings.LastIndex(pkg, "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	d.names[name] = true
+	return name
+}
+
+func (d *Declarations) object(t reflect.Type, indent string) string {
+	var b strings.Builder
+	b.WriteString("{\n")
+	for i := 0; i < t.NumField(); i++ {
+		var field = t.Field(i)
+		var name, omitEmpty, ok = jsc.FieldName(field)
+		if !ok || !field.IsExported() {
+			continue
+		}
+		var optional = ""
+		if omitEmpty || field.Type.Kind() == reflect.Ptr {
+			optional = "?"
+		}
+		b.WriteString(indent + "\t" + property(name) + optional + ": " + d.Type(field.Type) + ";\n")
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// Func returns the signature as a method of an interface.
+func (d *Declarations) Func(s Signature, indent string) string {
+	var b strings.Builder
+	writeDoc(&b, s.Doc, indent)
+	b.WriteString(indent + property(s.Name) + d.signature(s) + ";\n")
+	return b.String()
+}
+
+func (d *Declarations) signature(s Signature) string {
+	var result = "void"
+	if !isVoid(s.Result) {
+		result = d.Type(s.Result)
+	}
+	if s.Async {
+		result = "Promise<" + result + ">"
+	}
+	return d.params(s.Params) + ": " + result
+}
+
+func (d *Declarations) params(params []Param) string {
+	var list = make([]string, len(params))
+	for i, p := range params {
+		var optional = ""
+		if p.Optional {
+			optional = "?"
+		}
+		list[i] = paramName(p.Name, i) + optional + ": " + d.Type(p.Type)
+	}
+	return "(" + strings.Join(list, ", ") + ")"
+}
+
+// Words which can not be used as the name of a parameter in strict mode.
+var reserved = map[string]bool{
+	"arguments": true, "await": true, "break": true, "case": true, "catch": true,
+	"class": true, "const": true, "continue": true, "debugger": true, "default": true,
+	"delete": true, "do": true, "else": true, "enum": true, "eval": true,
+	"export": true, "extends": true, "false": true, "finally": true, "for": true,
+	"function": true, "if": true, "implements": true, "import": true, "in": true,
+	"instanceof": true, "interface": true, "let": true, "new": true, "null": true,
+	"package": true, "private": true, "protected": true, "public": true, "return": true,
+	"static": true, "super": true, "switch": true, "this": true, "throw": true,
+	"true": true, "try": true, "typeof": true, "var": true, "void": true,
+	"while": true, "with": true, "yield": true,
+}
+
+// paramName returns a valid parameter name, "default" becomes "default_" and "user-id" becomes "arg0".
+func paramName(name string, i int) string {
+	if name == "" || property(name) != name {
+		return "arg" + strconv.Itoa(i)
+	}
+	if reserved[name] {
+		return name + "_"
+	}
+	return name
+}
+
+// Interfaces returns the declarations of the interfaces used so far, sorted by name.
+func (d *Declarations) Interfaces() string {
+	var decls = append([]string(nil), d.decls...)
+	sort.Strings(decls)
+	return strings.Join(decls, "\n\n")
+}
+
+// TypeScript returns the declarations of an object on the window with the functions.
+//
+// If name is empty the functions are declared as globals, as registered by jsext.RegisterFunc.
+func TypeScript(name string, funcs ...Typed) string {
+	return typeScript(name, funcs, nil)
+}
+
+func typeScript(name string, funcs []Typed, classes []Constructor) string {
+	var d = NewDeclarations()
+	var indent = ""
+	if name == "" {
+		indent = "\t"
+	}
+	var modifiers = "export declare "
+	if name == "" {
+		modifiers = ""
+	}
+	var decls = make([]string, len(classes))
+	for i, c := range classes {
+		decls[i] = c.Declare(d, indent, modifiers)
+	}
+
+	var body strings.Builder
+	for _, f := range funcs {
+		var s = f.Signature()
+		if name != "" {
+			body.WriteString(d.Func(s, "\t"))
+			continue
+		}
+		writeDoc(&body, s.Doc, "\t")
+		body.WriteString("\tfunction " + s.Name + d.signature(s) + ";\n")
+	}
+
+	var b strings.Builder
+	b.WriteString("// Code generated by jsext/export. DO NOT EDIT.\n\n")
+	if interfaces := d.Interfaces(); interfaces != "" {
+		b.WriteString(interfaces + "\n\n")
+	} else if name == "" || len(classes) == 0 {
+		// declare global is only allowed in modules.
+		b.WriteString("export {};\n\n")
+	}
+	if name == "" {
+		b.WriteString("declare global {\n")
+		for _, decl := range decls {
+			b.WriteString(decl + "\n\n")
+		}
+		b.WriteString(body.String() + "}\n")
+		return b.String()
+	}
+	for _, decl := range decls {
+		b.WriteString(decl + "\n\n")
+	}
+	var typ = typeName(name)
+	b.WriteString("export interface " + typ + " {\n")
+	for _, c := range classes {
+		b.WriteString("\t" + property(c.ClassName()) + ": typeof " + c.ClassName() + ";\n")
+	}
+	b.WriteString(body.String())
+	b.WriteString("\t/** Removes the export from the window. */\n\tremoveExport(): void;\n}\n\n")
+	b.WriteString("declare global {\n\tvar " + name + ": " + typ + ";\n}\n")
+	return b.String()
+}
+
+// WriteTypeScript writes the declarations of TypeScript to w, for example to a .d.ts file from go generate.
+func WriteTypeScript(w io.Writer, name string, funcs ...Typed) error {
+	var _, err = io.WriteString(w, TypeScript(name, funcs...))
+	return err
+}
+
+func writeDoc(b *strings.Builder, doc, indent string) {
+	if doc == "" {
+		return
+	}
+	var lines = strings.Split(strings.TrimSpace(doc), "\n")
+	if len(lines) == 1 {
+		b.WriteString(indent + "/** " + lines[0] + " */\n")
+		return
+	}
+	b.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		b.WriteString(strings.TrimRight(indent+" * "+line, " ") + "\n")
+	}
+	b.WriteString(indent + " */\n")
+}
+
+func arrayOf(t string) string {
+	if strings.ContainsAny(t, " |(") {
+		return "(" + t + ")[]"
+	}
+	return t + "[]"
+}
+
+// property quotes names which are not identifiers.
+func property(name string) string {
+	for i, r := range name {
+		if r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9' {
+			continue
+		}
+		return `"` + strings.ReplaceAll(name, `"`, `\"`) + `"`
+	}
+	return name
+}
+
+// typeName returns the name of the interface of an export, "myApi" becomes "MyApi".
+func typeName(name string) string {
+	var b strings.Builder
+	var upper = true
+	for _, r := range name {
+		if r == '_' || r == '-' || r == '$' || r == '.' {
+			upper = true
+			continue
+		}
+		if upper && r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		upper = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
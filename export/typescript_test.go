@@ -0,0 +1,93 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package export
+
+import (
+	"testing"
+)
+
+type User struct {
+	ID      int      `js:"id"`
+	Name    string   `js:"name"`
+	Email   *string  `js:"email"`
+	Friends []*User  `js:"friends"`
+	Tags    []string `js:"tags,omitempty"`
+}
+
+type findUser struct {
+	Default int
+	Class   string
+	UserID  int `js:"user-id"`
+	Limit   *int
+}
+
+func TestTypeScript(t *testing.T) {
+	var find = NewAsyncFunc("findUser", func(findUser) ([]User, error) { return nil, nil })
+	find.Doc = "Finds users.\nResults are paged."
+	var count = NewFunc("count", func(int) (int, error) { return 0, nil })
+
+	var want = `// Code generated by jsext/export. DO NOT EDIT.
+
+export interface User {
+	id: number;
+	name: string;
+	email?: string | null;
+	friends: (User | null)[];
+	tags?: string[];
+}
+
+export interface Users {
+	/**
+	 * Finds users.
+	 * Results are paged.
+	 */
+	findUser(default_: number, class_: string, arg2: number, limit?: number | null): Promise<User[]>;
+	count(value: number): number;
+	/** Removes the export from the window. */
+	removeExport(): void;
+}
+
+declare global {
+	var users: Users;
+}
+`
+	if got := TypeScript("users", find, count); got != want {
+		t.Errorf("TypeScript() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTypeScriptGlobal(t *testing.T) {
+	var count = NewFunc("count", func(int) (int, error) { return 0, nil })
+	var want = `// Code generated by jsext/export. DO NOT EDIT.
+
+export {};
+
+declare global {
+	function count(value: number): number;
+}
+`
+	if got := TypeScript("", count); got != want {
+		t.Errorf("TypeScript() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestParamName(t *testing.T) {
+	var tests = []struct {
+		name string
+		want string
+	}{
+		{"id", "id"},
+		{"$el", "$el"},
+		{"default", "default_"},
+		{"class", "class_"},
+		{"user-id", "arg3"},
+		{"2fa", "arg3"},
+		{"", "arg3"},
+	}
+	for _, test := range tests {
+		if got := paramName(test.name, 3); got != test.want {
+			t.Errorf("paramName(%q) = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
@@ -2,9 +2,9 @@ package fetch
 
 import (
 	"io"
-	"syscall/js"
 
 	"github.com/Nigel2392/jsext/v2/errs"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 	"github.com/Nigel2392/jsext/v2/reader"
 )
 
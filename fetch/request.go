@@ -4,9 +4,9 @@ import (
 	"bytes"
 	"context"
 	"io"
-	"syscall/js"
 
 	"github.com/Nigel2392/jsext/v2/encoding"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 type Request struct {
@@ -1,7 +1,7 @@
 package fetch
 
 import (
-	"syscall/js"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 type ReadCloser interface {
@@ -1,8 +1,9 @@
 package files
 
 import (
-	"syscall/js"
 	"time"
+
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 var (
@@ -2,8 +2,8 @@ package files
 
 import (
 	"io"
-	"syscall/js"
 
+	js "github.com/Nigel2392/jsext/v2/jsv"
 	"github.com/Nigel2392/jsext/v2/reader"
 )
 
@@ -1,9 +1,8 @@
 package history
 
 import (
-	"syscall/js"
-
 	"github.com/Nigel2392/jsext/v2/jsc"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 var history = js.Global().Get("history")
@@ -1,9 +1,9 @@
-//go:build js && wasm
-// +build js,wasm
-
 package jsext
 
-import "syscall/js"
+import (
+	"github.com/Nigel2392/jsext/v2/dom"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
 
 // Defines multiple elements.
 // This is a wrapper around a slice of []Element(js.Value).
@@ -24,17 +24,20 @@ func (e Elements) Value() []js.Value {
 }
 
 // AddEventListener adds an event listener to all elements in the slice.
-func (e Elements) AddEventListener(event string, listener func(this Value, event Event)) Elements {
+//
+// The returned group can be used to remove all of the listeners at once.
+func (e Elements) AddEventListener(event string, listener func(this Value, event Event), opts ...ListenerOptions) *Listeners {
+	var group = &Listeners{}
 	for _, el := range e {
-		el.AddEventListener(event, listener)
+		group.Add(el.AddEventListener(event, listener, opts...))
 	}
-	return e
+	return group
 }
 
-// RemoveEventListener removes an event listener from all elements in the slice.
-func (e Elements) RemoveEventListener(event string, listener func(this Value, event Event)) Elements {
+// RemoveEventListener removes all listeners for the event from all elements in the slice.
+func (e Elements) RemoveEventListener(event string) Elements {
 	for _, el := range e {
-		el.RemoveEventListener(event, listener)
+		el.RemoveEventListener(event)
 	}
 	return e
 }
@@ -109,23 +112,25 @@ func (e Element) Equal(other js.Value) bool {
 }
 
 // AddEventListener adds an event listener to the element.
-func (e Element) AddEventListener(event string, listener func(this Value, event Event)) Element {
-	e.JSValue().Call("addEventListener", event, js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		listener(Value(this), Event(args[0]))
-		return nil
-	}))
-	return e
+//
+// The returned Listener can be used to remove it again.
+func (e Element) AddEventListener(event string, listener func(this Value, event Event), opts ...ListenerOptions) *Listener {
+	return Listen(e.JSValue(), event, func(this js.Value, event Event) {
+		listener(Value(this), event)
+	}, opts...)
 }
 
-// RemoveEventListener removes an event listener from the element.
-func (e Element) RemoveEventListener(event string, listener func(this Value, event Event)) Element {
-	e.JSValue().Call("removeEventListener", event, js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		listener(Value(this), Event(args[0]))
-		return nil
-	}))
+// RemoveEventListener removes all listeners for the event which were added with AddEventListener.
+func (e Element) RemoveEventListener(event string) Element {
+	e.Listeners().Remove(event)
 	return e
 }
 
+// Listeners returns the registry of listeners added to the element.
+func (e Element) Listeners() *Listeners {
+	return ListenersOf(e.JSValue())
+}
+
 // AppendChild appends a child to the element.
 func (e Element) AppendChild(child Element) Element {
 	e.JSValue().Call("appendChild", child.JSValue())
@@ -166,6 +171,14 @@ func (e Element) InnerHTML(html string) Element {
 	return e
 }
 
+// SafeInnerHTML sanitizes the HTML and sets it as the inner HTML of the element.
+//
+// If no policy is given, dom.DefaultPolicy is used.
+func (e Element) SafeInnerHTML(html string, policy ...*dom.Policy) Element {
+	dom.SetSafeInnerHTML(e.JSValue(), html, policy...)
+	return e
+}
+
 // InnerText sets the inner text of the element.
 func (e Element) InnerText(text string) Element {
 	e.JSValue().Set("innerText", text)
@@ -1,19 +1,16 @@
-//go:build js && wasm
-// +build js,wasm
-
 package jsext
 
 import (
 	"regexp"
 	"strconv"
 	"strings"
-	"syscall/js"
 	"time"
 	"unsafe"
 
 	"github.com/Nigel2392/jsext/v2/console"
 	"github.com/Nigel2392/jsext/v2/errs"
 	"github.com/Nigel2392/jsext/v2/export"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 type Marshaller interface {
@@ -4,7 +4,7 @@
 package jsc
 
 import (
-	"syscall/js"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 func EncodeBase64[T ~string | ~[]byte](data T) (T, error) {
@@ -3,11 +3,10 @@ package jsc
 import (
 	"fmt"
 	"reflect"
-	"syscall/js"
 	"unsafe"
 
-	"github.com/Nigel2392/jsext/v2"
 	"github.com/Nigel2392/jsext/v2/errs"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 // Convert a js.Value to a map[string]T.
@@ -104,8 +103,8 @@ func Cast[T ObjectConstraints](value js.Value) (T, error) {
 	//
 	// and avoiding the extra interface{} conversion.
 	switch typeOf.(type) {
-	case jsext.Unmarshaller:
-		var err = typeOf.(jsext.Unmarshaller).UnmarshalJS(value)
+	case Unmarshaller:
+		var err = typeOf.(Unmarshaller).UnmarshalJS(value)
 		if err != nil {
 			return preTypeOf, err
 		}
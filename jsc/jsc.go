@@ -3,11 +3,10 @@ package jsc
 import (
 	"reflect"
 	"strings"
-	"syscall/js"
 
-	"github.com/Nigel2392/jsext/v2"
 	"github.com/Nigel2392/jsext/v2/console"
 	"github.com/Nigel2392/jsext/v2/errs"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 // TINYGO is used to check if we are using tinygo as a compiler.
@@ -16,6 +15,22 @@ var TINYGO bool
 // Wether to encode and decode bytes using base64.
 var BASE64 = true
 
+// The marshalling interfaces of jsext, declared here so jsext and export can import jsc.
+type (
+	Marshaller interface {
+		MarshalJS() js.Value
+	}
+	ErrorMarshaller interface {
+		MarshalJS() (js.Value, error)
+	}
+	FuncMarshaller interface {
+		MarshalJS() js.Func
+	}
+	Unmarshaller interface {
+		UnmarshalJS(js.Value) error
+	}
+)
+
 // Package JSC implements a way to convert javascript objects to go objects, and vice versa.
 //
 // This package is used to communicate between the frontend and backend.
@@ -126,15 +141,15 @@ func ValueOf(f interface{}) (js.Value, error) {
 	switch val := f.(type) {
 	case js.Value, js.Func:
 		return js.ValueOf(val), nil
-	case jsext.Marshaller:
+	case Marshaller:
 		return val.MarshalJS(), nil
-	case jsext.ErrorMarshaller:
+	case ErrorMarshaller:
 		var jsValue, err = val.MarshalJS()
 		if err != nil {
 			return js.Null(), err
 		}
 		return jsValue, nil
-	case jsext.FuncMarshaller:
+	case FuncMarshaller:
 		var jsFunc = val.MarshalJS()
 		return jsFunc.Value, nil
 	case func():
@@ -275,6 +290,12 @@ func valueOfJS(valueOf reflect.Value, kind reflect.Kind) (js.Value, error) {
 			}
 			var valField = valueOf.Field(i)
 			if valField.Kind() == reflect.Ptr {
+				if valField.IsNil() {
+					if !omitEmpty {
+						object.Set(tag, js.Null())
+					}
+					continue
+				}
 				valField = valField.Elem()
 			}
 
@@ -326,7 +347,7 @@ func valueOfJS(valueOf reflect.Value, kind reflect.Kind) (js.Value, error) {
 		}
 
 		var vinter = valueOf.Interface()
-		if funcMarshaller, ok := vinter.(jsext.FuncMarshaller); ok {
+		if funcMarshaller, ok := vinter.(FuncMarshaller); ok {
 			var jsFunc = funcMarshaller.MarshalJS()
 			return jsFunc.Value, nil
 		}
@@ -374,6 +395,13 @@ func valueOfJS(valueOf reflect.Value, kind reflect.Kind) (js.Value, error) {
 	}
 }
 
+// FieldName returns the name of the struct field in javascript objects, from the js, jsc or json tag.
+//
+// ok is false if the field is skipped.
+func FieldName(field reflect.StructField) (name string, omitEmpty bool, ok bool) {
+	return getStructTag(field, "js", "jsc", "json")
+}
+
 func getStructTag(field reflect.StructField, tags ...string) (name string, omitEmpty bool, ok bool) {
 	for _, tag := range tags {
 		var value = field.Tag.Get(tag)
@@ -471,7 +499,7 @@ func scanStruct(src js.Value, dstVal reflect.Value, dstTyp reflect.Type) error {
 	return nil
 }
 
-var unmarshallerType = reflect.TypeOf((*jsext.Unmarshaller)(nil)).Elem()
+var unmarshallerType = reflect.TypeOf((*Unmarshaller)(nil)).Elem()
 
 func scanValue(srcVal js.Value, dstVal reflect.Value) error {
 	if dstVal.Kind() == reflect.Ptr {
@@ -479,7 +507,7 @@ func scanValue(srcVal js.Value, dstVal reflect.Value) error {
 	}
 
 	if canAddr := dstVal.CanAddr(); canAddr && dstVal.Addr().Type().Implements(unmarshallerType) {
-		var unmarshaller = dstVal.Addr().Interface().(jsext.Unmarshaller)
+		var unmarshaller = dstVal.Addr().Interface().(Unmarshaller)
 		return unmarshaller.UnmarshalJS(srcVal)
 	}
 
@@ -0,0 +1,667 @@
+package chart
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/Nigel2392/jsext/v2"
+	"github.com/Nigel2392/jsext/v2/dom"
+	"github.com/Nigel2392/jsext/v2/jse"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+//	<div class="jsext-chart">
+//		<svg> or <canvas>
+//		<div class="jsext-chart-legend">
+//			<span class="jsext-chart-legend-item"><span class="jsext-chart-swatch"></span>Name</span>
+//		</div>
+//		<div class="jsext-chart-tooltip"></div>
+//	</div>
+
+type Kind int
+
+const (
+	Line Kind = iota + 1
+	Bar
+	Area
+	Scatter
+	// Pie draws the first series, each point is a slice.
+	Pie
+)
+
+type Point struct {
+	X, Y float64
+	// Label is shown on the x axis of bar charts, and in the legend of pie charts.
+	Label string
+}
+
+type Series struct {
+	Name   string
+	Points []Point
+	// Kind defaults to the kind of the chart, so line and bar series can be mixed.
+	Kind Kind
+	// Color defaults to the next color of Options.Colors.
+	Color string
+}
+
+// Values returns a series of points with the index as X.
+func Values(name string, values ...float64) Series {
+	var points = make([]Point, len(values))
+	for i, v := range values {
+		points[i] = Point{X: float64(i), Y: v}
+	}
+	return Series{Name: name, Points: points}
+}
+
+var DefaultColors = []string{
+	"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f",
+	"#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac",
+}
+
+type Options struct {
+	Kind Kind
+	// Width is only used until the chart is resized, unless Fixed is set.
+	Width  int
+	Height int
+	// Fixed disables resizing the chart to the width of its container.
+	Fixed bool
+	// Padding is the space for the labels of the axes.
+	Padding int
+	// Ticks is the approximate number of ticks on each axis.
+	Ticks     int
+	Colors    []string
+	TextColor string
+	GridColor string
+	Font      string
+
+	NoLegend  bool
+	NoTooltip bool
+
+	// Duration of the transition when the data changes.
+	Duration    time.Duration
+	NoAnimation bool
+
+	FormatX func(x float64) string
+	FormatY func(y float64) string
+
+	// Renderer defaults to an SVGRenderer.
+	Renderer    Renderer
+	ClassPrefix string
+}
+
+func (o *Options) Defaults() {
+	if o.Kind == 0 {
+		o.Kind = Line
+	}
+	if o.Width == 0 {
+		o.Width = 600
+	}
+	if o.Height == 0 {
+		o.Height = 300
+	}
+	if o.Padding == 0 {
+		o.Padding = 40
+	}
+	if o.Ticks == 0 {
+		o.Ticks = 5
+	}
+	if len(o.Colors) == 0 {
+		o.Colors = DefaultColors
+	}
+	if o.TextColor == "" {
+		o.TextColor = "#666"
+	}
+	if o.GridColor == "" {
+		o.GridColor = "#e5e5e5"
+	}
+	if o.Font == "" {
+		o.Font = "12px sans-serif"
+	}
+	if o.Duration == 0 {
+		o.Duration = 300 * time.Millisecond
+	}
+	if o.Renderer == nil {
+		o.Renderer = NewSVGRenderer()
+	}
+	if o.ClassPrefix == "" {
+		o.ClassPrefix = "jsext-"
+	}
+}
+
+// hit is an area of the chart which shows a tooltip when hovered.
+type hit struct {
+	kind          Kind
+	series, index int
+	x, y, w, h    float64 // the point, or the rectangle of a bar
+	start, end    float64 // the angles of a slice
+}
+
+// Chart draws line, bar, area, scatter and pie charts.
+type Chart struct {
+	root    *jse.Element
+	legend  *jse.Element
+	tooltip *jse.Element
+	opts    Options
+
+	series []Series
+	prev   [][]Point // values drawn when the data last changed
+	hidden map[int]bool
+
+	width, height int
+	hits          []hit
+
+	progress float64
+	start    float64
+	frame    js.Func
+	frameID  js.Value
+
+	observer *dom.ResizeObserver
+	// listeners of the plot, and of the legend items which are removed when it is rendered again.
+	listeners       jsext.Listeners
+	legendListeners jsext.Listeners
+}
+
+func New(series []Series, opts Options) *Chart {
+	opts.Defaults()
+	var c = &Chart{
+		opts:     opts,
+		series:   series,
+		hidden:   make(map[int]bool),
+		width:    opts.Width,
+		height:   opts.Height,
+		progress: 1,
+	}
+	if s, ok := opts.Renderer.(interface{ SetFont(string) }); ok {
+		s.SetFont(opts.Font)
+	}
+
+	c.root = jse.Div(c.class("chart"))
+	c.root.Style().Set("position", "relative")
+	c.root.Style().Set("font", opts.Font)
+	c.root.AppendChild(opts.Renderer.Element())
+	c.legend = c.root.Div(c.class("chart-legend"))
+	c.tooltip = c.root.Div(c.class("chart-tooltip"))
+	c.tooltip.Style().Display("none")
+
+	if !opts.NoTooltip {
+		var plot = opts.Renderer.Element()
+		c.listeners.Add(plot.AddEventListener("pointermove", func(this *jse.Element, event jsext.Event) {
+			var rect = plot.Call("getBoundingClientRect")
+			c.hover(event.Get("clientX").Float()-rect.Get("left").Float(), event.Get("clientY").Float()-rect.Get("top").Float())
+		}))
+		c.listeners.Add(plot.AddEventListener("pointerleave", func(this *jse.Element, event jsext.Event) {
+			c.tooltip.Style().Display("none")
+		}))
+	}
+
+	if !opts.Fixed {
+		c.root.Style().Width("100%")
+		c.observer = dom.NewResizeObserver(func(entries []dom.ResizeEntry, o *dom.ResizeObserver) {
+			for _, e := range entries {
+				if w := int(e.ContentRect.Width); w > 0 && w != c.width {
+					c.Resize(w, c.height)
+				}
+			}
+		})
+		c.observer.Observe(c.root.MarshalJS())
+	}
+
+	c.root.StyleBlock(`
+		.` + c.class("chart-legend") + ` {
+			display: flex;
+			flex-wrap: wrap;
+			justify-content: center;
+			gap: 4px 12px;
+			color: ` + opts.TextColor + `;
+		}
+		.` + c.class("chart-legend-item") + ` {
+			cursor: pointer;
+			user-select: none;
+		}
+		.` + c.class("chart-legend-hidden") + ` {
+			opacity: 0.4;
+		}
+		.` + c.class("chart-swatch") + ` {
+			display: inline-block;
+			width: 10px;
+			height: 10px;
+			margin-right: 4px;
+			border-radius: 2px;
+		}
+		.` + c.class("chart-tooltip") + ` {
+			position: absolute;
+			pointer-events: none;
+			white-space: pre;
+			padding: 4px 8px;
+			border-radius: 4px;
+			background: rgba(0, 0, 0, 0.75);
+			color: #fff;
+		}`)
+
+	c.renderLegend()
+	c.draw()
+	return c
+}
+
+func (c *Chart) class(name string) string {
+	return c.opts.ClassPrefix + name
+}
+
+func (c *Chart) Element() *jse.Element {
+	return c.root
+}
+
+func (c *Chart) Data() []Series {
+	return c.series
+}
+
+// SetData replaces the series, points move from their old values to the new ones.
+func (c *Chart) SetData(series []Series) {
+	c.prev = c.values()
+	c.series = series
+	c.renderLegend()
+	if c.opts.NoAnimation {
+		c.progress = 1
+		c.draw()
+		return
+	}
+	c.animate()
+}
+
+// Resize draws the chart at a new size.
+func (c *Chart) Resize(width, height int) {
+	c.width, c.height = width, height
+	c.draw()
+}
+
+// SetHidden hides or shows a series, or a slice of a pie chart.
+func (c *Chart) SetHidden(index int, hidden bool) {
+	if hidden {
+		c.hidden[index] = true
+	} else {
+		delete(c.hidden, index)
+	}
+	c.renderLegend()
+	c.draw()
+}
+
+// Destroy stops the chart from observing its size, removes its listeners and removes it from the document.
+func (c *Chart) Destroy() {
+	c.listeners.Remove()
+	c.legendListeners.Remove()
+	if c.observer != nil {
+		c.observer.Disconnect()
+		c.observer = nil
+	}
+	c.stop()
+	c.root.Remove()
+}
+
+func (c *Chart) color(i int) string {
+	return c.opts.Colors[i%len(c.opts.Colors)]
+}
+
+func (c *Chart) seriesColor(i int) string {
+	if c.series[i].Color != "" {
+		return c.series[i].Color
+	}
+	return c.color(i)
+}
+
+func (c *Chart) kind(i int) Kind {
+	if c.series[i].Kind != 0 && c.opts.Kind != Pie {
+		return c.series[i].Kind
+	}
+	return c.opts.Kind
+}
+
+func (c *Chart) formatX(x float64) string {
+	if c.opts.FormatX != nil {
+		return c.opts.FormatX(x)
+	}
+	return strconv.FormatFloat(x, 'f', -1, 64)
+}
+
+func (c *Chart) formatY(y float64) string {
+	if c.opts.FormatY != nil {
+		return c.opts.FormatY(y)
+	}
+	return strconv.FormatFloat(y, 'f', -1, 64)
+}
+
+func (c *Chart) animate() {
+	c.progress = 0
+	c.start = -1
+	if c.frame.IsUndefined() {
+		c.frame = js.FuncOf(func(this js.Value, args []js.Value) any {
+			var now = args[0].Float()
+			if c.start < 0 {
+				c.start = now
+			}
+			c.progress = math.Min((now-c.start)/float64(c.opts.Duration.Milliseconds()), 1)
+			c.draw()
+			if c.progress < 1 {
+				c.frameID = js.Global().Call("requestAnimationFrame", c.frame)
+			} else {
+				c.frameID = js.Undefined()
+			}
+			return nil
+		})
+	}
+	if c.frameID.IsUndefined() {
+		c.frameID = js.Global().Call("requestAnimationFrame", c.frame)
+	}
+}
+
+func (c *Chart) stop() {
+	if !c.frameID.IsUndefined() {
+		js.Global().Call("cancelAnimationFrame", c.frameID)
+		c.frameID = js.Undefined()
+	}
+	if !c.frame.IsUndefined() {
+		c.frame.Release()
+		c.frame = js.Func{}
+	}
+}
+
+// values returns the points as they are drawn at the current progress of the transition.
+func (c *Chart) values() [][]Point {
+	// Ease out cubic.
+	var t = 1 - math.Pow(1-c.progress, 3)
+	var values = make([][]Point, len(c.series))
+	for i, s := range c.series {
+		values[i] = make([]Point, len(s.Points))
+		for j, p := range s.Points {
+			var from = Point{X: p.X}
+			if i < len(c.prev) && j < len(c.prev[i]) {
+				from = c.prev[i][j]
+			}
+			values[i][j] = Point{
+				X:     from.X + (p.X-from.X)*t,
+				Y:     from.Y + (p.Y-from.Y)*t,
+				Label: p.Label,
+			}
+		}
+	}
+	return values
+}
+
+func (c *Chart) draw() {
+	var r = c.opts.Renderer
+	c.hits = c.hits[:0]
+	r.Begin(c.width, c.height)
+	if c.opts.Kind == Pie {
+		c.drawPie(c.values())
+	} else {
+		c.drawCartesian(c.values())
+	}
+	r.End()
+}
+
+func (c *Chart) drawPie(values [][]Point) {
+	if len(values) == 0 {
+		return
+	}
+	var total float64
+	for j, p := range values[0] {
+		if !c.hidden[j] && p.Y > 0 && finite(p.Y) {
+			total += p.Y
+		}
+	}
+	if total == 0 {
+		return
+	}
+	var cx, cy = float64(c.width) / 2, float64(c.height) / 2
+	var radius = math.Min(cx, cy) - float64(c.opts.Padding)/4
+	var angle float64
+	for j, p := range values[0] {
+		if c.hidden[j] || p.Y <= 0 || !finite(p.Y) {
+			continue
+		}
+		var end = angle + p.Y/total*2*math.Pi
+		c.opts.Renderer.Sector(cx, cy, radius, angle, end, c.color(j))
+		c.hits = append(c.hits, hit{kind: Pie, index: j, x: cx, y: cy, w: radius, start: angle, end: end})
+		angle = end
+	}
+}
+
+func (c *Chart) drawCartesian(values [][]Point) {
+	var r = c.opts.Renderer
+	var pad = float64(c.opts.Padding)
+	var left, right = pad, float64(c.width) - pad/2
+	var top, bottom = pad / 2, float64(c.height) - pad
+
+	var visible []int
+	var categorical, zero bool
+	var n int
+	var yMin, yMax = math.Inf(1), math.Inf(-1)
+	var xMin, xMax = math.Inf(1), math.Inf(-1)
+	for i := range c.series {
+		if c.hidden[i] {
+			continue
+		}
+		visible = append(visible, i)
+		switch c.kind(i) {
+		case Bar:
+			categorical, zero = true, true
+		case Area:
+			zero = true
+		}
+		if len(values[i]) > n {
+			n = len(values[i])
+		}
+		for _, p := range values[i] {
+			// Missing values, such as NaN, are not drawn.
+			if !finite(p.X) || !finite(p.Y) {
+				continue
+			}
+			yMin, yMax = math.Min(yMin, p.Y), math.Max(yMax, p.Y)
+			xMin, xMax = math.Min(xMin, p.X), math.Max(xMax, p.X)
+		}
+	}
+	if yMin > yMax {
+		yMin, yMax, xMin, xMax = 0, 1, 0, 1
+	}
+	if zero {
+		yMin, yMax = math.Min(yMin, 0), math.Max(yMax, 0)
+	}
+
+	var yTicks = Ticks(yMin, yMax, c.opts.Ticks)
+	var ys = scale{yTicks[0], yTicks[len(yTicks)-1], bottom, top}
+	var base = ys.at(math.Max(yTicks[0], math.Min(0, yTicks[len(yTicks)-1])))
+	var formatY = c.formatY
+	if c.opts.FormatY == nil {
+		formatY = FormatTick(yTicks)
+	}
+	for _, t := range yTicks {
+		var y = ys.at(t)
+		r.Line([]Vec{{left, y}, {right, y}}, c.opts.GridColor, 1)
+		r.Text(left-6, y, formatY(t), c.opts.TextColor, AlignEnd, BaselineMiddle)
+	}
+
+	// Bars are placed in bands by their index, other series by their X value.
+	var band = (right - left) / float64(max(n, 1))
+	var xAt func(j int, p Point) float64
+	if categorical {
+		xAt = func(j int, p Point) float64 {
+			return left + band*(float64(j)+0.5)
+		}
+		// Skip labels which would overlap.
+		var every = int(math.Ceil(60 / band))
+		for j := 0; j < n; j += max(every, 1) {
+			r.Text(xAt(j, Point{}), bottom+6, c.categoryLabel(values, visible, j), c.opts.TextColor, AlignMiddle, BaselineTop)
+		}
+	} else {
+		var xTicks = Ticks(xMin, xMax, c.opts.Ticks)
+		var xs = scale{xTicks[0], xTicks[len(xTicks)-1], left, right}
+		xAt = func(j int, p Point) float64 {
+			return xs.at(p.X)
+		}
+		var formatX = c.formatX
+		if c.opts.FormatX == nil {
+			formatX = FormatTick(xTicks)
+		}
+		for _, t := range xTicks {
+			r.Text(xs.at(t), bottom+6, formatX(t), c.opts.TextColor, AlignMiddle, BaselineTop)
+		}
+	}
+	r.Line([]Vec{{left, base}, {right, base}}, c.opts.TextColor, 1)
+
+	var bars, bar = 0, 0
+	for _, i := range visible {
+		if c.kind(i) == Bar {
+			bars++
+		}
+	}
+	for _, i := range visible {
+		var color = c.seriesColor(i)
+		var points []Vec
+		var index []int
+		for j, p := range values[i] {
+			if finite(p.X) && finite(p.Y) {
+				points = append(points, Vec{xAt(j, p), ys.at(p.Y)})
+				index = append(index, j)
+			}
+		}
+		switch c.kind(i) {
+		case Bar:
+			var w = band * 0.8 / float64(bars)
+			for k, p := range points {
+				var j = index[k]
+				var x = left + band*float64(j) + band*0.1 + float64(bar)*w
+				var y, h = math.Min(p.Y, base), math.Abs(p.Y - base)
+				r.Rect(x, y, w, h, color)
+				c.hits = append(c.hits, hit{kind: Bar, series: i, index: j, x: x, y: y, w: w, h: h})
+			}
+			bar++
+			continue
+		case Area:
+			if len(points) > 1 {
+				var polygon = append([]Vec{{points[0].X, base}}, points...)
+				polygon = append(polygon, Vec{points[len(points)-1].X, base})
+				r.Polygon(polygon, color, 0.3)
+			}
+			r.Line(points, color, 2)
+		case Scatter:
+			for _, p := range points {
+				r.Circle(p.X, p.Y, 3.5, color)
+			}
+		default:
+			r.Line(points, color, 2)
+		}
+		for k, p := range points {
+			c.hits = append(c.hits, hit{kind: c.kind(i), series: i, index: index[k], x: p.X, y: p.Y})
+		}
+	}
+}
+
+func (c *Chart) categoryLabel(values [][]Point, visible []int, j int) string {
+	for _, i := range visible {
+		if j < len(values[i]) {
+			if l := c.series[i].Points[j].Label; l != "" {
+				return l
+			}
+			return c.formatX(c.series[i].Points[j].X)
+		}
+	}
+	return ""
+}
+
+// hover shows the tooltip of the hit closest to x, y.
+func (c *Chart) hover(x, y float64) {
+	var found *hit
+	var best = 24.0
+	for i := range c.hits {
+		var h = &c.hits[i]
+		switch h.kind {
+		case Bar:
+			if x >= h.x && x <= h.x+h.w && y >= h.y-4 && y <= h.y+h.h {
+				found, best = h, 0
+			}
+		case Pie:
+			var dx, dy = x - h.x, y - h.y
+			var angle = math.Atan2(dx, -dy)
+			if angle < 0 {
+				angle += 2 * math.Pi
+			}
+			if math.Hypot(dx, dy) <= h.w && angle >= h.start && angle < h.end {
+				found, best = h, 0
+			}
+		default:
+			if d := math.Hypot(x-h.x, y-h.y); d < best {
+				found, best = h, d
+			}
+		}
+	}
+	if found == nil {
+		c.tooltip.Style().Display("none")
+		return
+	}
+
+	var text string
+	switch found.kind {
+	case Pie:
+		var p = c.series[0].Points[found.index]
+		var share = (found.end - found.start) / (2 * math.Pi) * 100
+		text = c.pointLabel(0, found.index) + ": " + c.formatY(p.Y) + " (" + strconv.FormatFloat(share, 'f', 1, 64) + "%)"
+	default:
+		var p = c.series[found.series].Points[found.index]
+		text = c.pointLabel(found.series, found.index) + ": " + c.formatY(p.Y)
+		if name := c.series[found.series].Name; name != "" {
+			text = name + "\n" + text
+		}
+	}
+	c.tooltip.InnerText(text)
+	c.tooltip.Style().Display("block")
+	c.tooltip.Style().Left(strconv.Itoa(int(x)+12) + "px")
+	c.tooltip.Style().Top(strconv.Itoa(int(y)+12) + "px")
+}
+
+func (c *Chart) pointLabel(series, index int) string {
+	var p = c.series[series].Points[index]
+	if p.Label != "" {
+		return p.Label
+	}
+	return c.formatX(p.X)
+}
+
+func (c *Chart) renderLegend() {
+	c.legendListeners.Remove()
+	c.legend.ClearInnerHTML()
+	if c.opts.NoLegend {
+		return
+	}
+	var add = func(index int, name, color string) {
+		var item = c.legend.Span()
+		item.ClassList(c.class("chart-legend-item"))
+		if c.hidden[index] {
+			item.ClassList().Call("add", c.class("chart-legend-hidden"))
+		}
+		var swatch = item.Span()
+		swatch.ClassList(c.class("chart-swatch"))
+		swatch.Style().Set("background", color)
+		item.Call("append", name)
+		c.legendListeners.Add(item.OnClick(func(this *jse.Element, event jsext.Event) {
+			c.SetHidden(index, !c.hidden[index])
+		}))
+	}
+	if c.opts.Kind == Pie {
+		if len(c.series) > 0 {
+			for j := range c.series[0].Points {
+				add(j, c.pointLabel(0, j), c.color(j))
+			}
+		}
+		return
+	}
+	for i, s := range c.series {
+		add(i, s.Name, c.seriesColor(i))
+	}
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,36 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package chart
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+func TestNonFiniteValues(t *testing.T) {
+	for _, kind := range []Kind{Line, Bar, Area, Scatter, Pie} {
+		js.Reset()
+		var c = New([]Series{
+			Values("a", 1, math.NaN(), 3, math.Inf(1)),
+			Values("b", math.Inf(-1)),
+		}, Options{Kind: kind, Fixed: true, NoAnimation: true})
+		var html = c.Element().JSValue().Get("outerHTML").String()
+		if strings.Contains(html, "NaN") || strings.Contains(html, "Inf") {
+			t.Errorf("kind %d: non-finite values were drawn: %s", kind, html)
+		}
+		c.Destroy()
+	}
+}
+
+func TestOnlyNonFiniteValues(t *testing.T) {
+	js.Reset()
+	var c = New([]Series{Values("a", math.NaN(), math.NaN())}, Options{Fixed: true, NoAnimation: true})
+	defer c.Destroy()
+	if html := c.Element().JSValue().Get("outerHTML").String(); strings.Contains(html, "NaN") {
+		t.Fatalf("non-finite values were drawn: %s", html)
+	}
+}
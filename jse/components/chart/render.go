@@ -0,0 +1,185 @@
+package chart
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Nigel2392/jsext/v2/jse"
+)
+
+// Vec is a point in pixels.
+type Vec struct {
+	X, Y float64
+}
+
+type Align string
+
+const (
+	AlignStart  Align = "start"
+	AlignMiddle Align = "middle"
+	AlignEnd    Align = "end"
+)
+
+type Baseline string
+
+const (
+	BaselineTop    Baseline = "top"
+	BaselineMiddle Baseline = "middle"
+	BaselineBottom Baseline = "bottom"
+)
+
+// Renderer draws the shapes of a chart.
+//
+// A frame starts with Begin, and ends with End; the renderer is redrawn from scratch every frame.
+type Renderer interface {
+	Element() *jse.Element
+	Begin(width, height int)
+	Line(points []Vec, color string, width float64)
+	Polygon(points []Vec, color string, opacity float64)
+	Rect(x, y, width, height float64, color string)
+	Circle(x, y, radius float64, color string)
+	// Sector draws a slice of a pie, angles are in radians, clockwise from 12 o'clock.
+	Sector(x, y, radius, start, end float64, color string)
+	Text(x, y float64, text string, color string, align Align, baseline Baseline)
+	End()
+}
+
+// SVGRenderer draws charts as an svg element.
+//
+// It works outside of the browser, so charts can be rendered on the server.
+type SVGRenderer struct {
+	svg *jse.SVG
+}
+
+func NewSVGRenderer() *SVGRenderer {
+	var r = &SVGRenderer{
+		svg: jse.NewSVG("http://www.w3.org/2000/svg"),
+	}
+	r.svg.Element().Style().Set("display", "block")
+	return r
+}
+
+func (r *SVGRenderer) Element() *jse.Element {
+	return r.svg.Element()
+}
+
+// SetFont sets the font of texts, as a CSS font shorthand.
+func (r *SVGRenderer) SetFont(font string) {
+	r.svg.Element().Style().Set("font", font)
+}
+
+func (r *SVGRenderer) Begin(width, height int) {
+	r.svg.Element().ClearInnerHTML()
+	r.svg.Width(width)
+	r.svg.Height(height)
+	r.svg.ViewBox(0, 0, width, height)
+}
+
+func (r *SVGRenderer) End() {}
+
+func (r *SVGRenderer) Line(points []Vec, color string, width float64) {
+	if len(points) < 2 {
+		return
+	}
+	r.element("polyline",
+		"points", svgPoints(points),
+		"fill", "none",
+		"stroke", color,
+		"stroke-width", num(width),
+		"stroke-linejoin", "round",
+	)
+}
+
+func (r *SVGRenderer) Polygon(points []Vec, color string, opacity float64) {
+	if len(points) < 3 {
+		return
+	}
+	r.element("polygon",
+		"points", svgPoints(points),
+		"fill", color,
+		"fill-opacity", num(opacity),
+	)
+}
+
+func (r *SVGRenderer) Rect(x, y, width, height float64, color string) {
+	r.element("rect",
+		"x", num(x),
+		"y", num(y),
+		"width", num(math.Max(width, 0)),
+		"height", num(math.Max(height, 0)),
+		"fill", color,
+	)
+}
+
+func (r *SVGRenderer) Circle(x, y, radius float64, color string) {
+	r.element("circle",
+		"cx", num(x),
+		"cy", num(y),
+		"r", num(radius),
+		"fill", color,
+	)
+}
+
+func (r *SVGRenderer) Sector(x, y, radius, start, end float64, color string) {
+	if end-start >= 2*math.Pi-1e-9 {
+		r.Circle(x, y, radius, color)
+		return
+	}
+	var x0, y0 = x + radius*math.Sin(start), y - radius*math.Cos(start)
+	var x1, y1 = x + radius*math.Sin(end), y - radius*math.Cos(end)
+	var large = "0"
+	if end-start > math.Pi {
+		large = "1"
+	}
+	var d = "M" + num(x) + " " + num(y) +
+		" L" + num(x0) + " " + num(y0) +
+		" A" + num(radius) + " " + num(radius) + " 0 " + large + " 1 " + num(x1) + " " + num(y1) +
+		" Z"
+	r.element("path",
+		"d", d,
+		"fill", color,
+	)
+}
+
+func (r *SVGRenderer) Text(x, y float64, text string, color string, align Align, baseline Baseline) {
+	var t = r.element("text",
+		"x", num(x),
+		"y", num(y),
+		"fill", color,
+		"text-anchor", string(align),
+	)
+	switch baseline {
+	case BaselineTop:
+		t.SetAttr("dominant-baseline", "hanging")
+	case BaselineMiddle:
+		t.SetAttr("dominant-baseline", "middle")
+	}
+	t.Set("textContent", text)
+}
+
+// element appends an element, attributes are set in order so the output is stable.
+func (r *SVGRenderer) element(name string, attrs ...string) *jse.Element {
+	var e = r.svg.NewElement(name)
+	for i := 0; i+1 < len(attrs); i += 2 {
+		e.SetAttr(attrs[i], attrs[i+1])
+	}
+	return e
+}
+
+func svgPoints(points []Vec) string {
+	var b strings.Builder
+	for i, p := range points {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(num(p.X))
+		b.WriteByte(',')
+		b.WriteString(num(p.Y))
+	}
+	return b.String()
+}
+
+func num(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
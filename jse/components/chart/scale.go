@@ -0,0 +1,98 @@
+package chart
+
+import (
+	"math"
+	"strconv"
+)
+
+// Ticks returns about count evenly spaced, round values which cover min and max.
+//
+// The first and last tick are the bounds of the axis, they may be outside of min and max.
+func Ticks(min, max float64, count int) []float64 {
+	if count < 2 {
+		count = 2
+	}
+	// A non-finite bound is replaced by the other bound, or 0 if neither is finite.
+	switch {
+	case !finite(min) && !finite(max):
+		min, max = 0, 0
+	case !finite(min):
+		min = max
+	case !finite(max):
+		max = min
+	}
+	if min == max {
+		if min == 0 {
+			max = 1
+		} else {
+			min, max = min-math.Abs(min)/2, max+math.Abs(max)/2
+		}
+	}
+	if min > max {
+		min, max = max, min
+	}
+	var step = niceNumber((max - min) / float64(count-1))
+	if !finite(step) || step == 0 {
+		// The range is too large or too small to divide.
+		return []float64{min, max}
+	}
+	var lo = math.Floor(min/step) * step
+	var hi = math.Ceil(max/step) * step
+	// Round to the decimals of the step, to avoid values like 0.30000000000000004.
+	var p = math.Pow(10, math.Max(0, -math.Floor(math.Log10(step))))
+	var n = int(math.Round((hi - lo) / step))
+	var ticks = make([]float64, n+1)
+	for i := range ticks {
+		ticks[i] = math.Round((lo+float64(i)*step)*p) / p
+	}
+	return ticks
+}
+
+func finite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// niceNumber rounds x to 1, 2, 5 or 10 times a power of ten.
+func niceNumber(x float64) float64 {
+	var exp = math.Floor(math.Log10(x))
+	var f = x / math.Pow(10, exp)
+	var nice float64
+	switch {
+	case f < 1.5:
+		nice = 1
+	case f < 3:
+		nice = 2
+	case f < 7:
+		nice = 5
+	default:
+		nice = 10
+	}
+	return nice * math.Pow(10, exp)
+}
+
+// FormatTick formats a tick with just enough decimals for the distance between ticks.
+func FormatTick(ticks []float64) func(float64) string {
+	var decimals = 0
+	if len(ticks) > 1 {
+		var step = math.Abs(ticks[1] - ticks[0])
+		if step > 0 && step < 1 {
+			decimals = int(math.Ceil(-math.Log10(step)))
+		}
+	}
+	return func(v float64) string {
+		return strconv.FormatFloat(v, 'f', decimals, 64)
+	}
+}
+
+// scale maps values of the domain d0..d1 onto the range r0..r1.
+type scale struct {
+	d0, d1 float64
+	r0, r1 float64
+}
+
+func (s scale) at(v float64) float64 {
+	if s.d1 == s.d0 {
+		return s.r0
+	}
+	return s.r0 + (v-s.d0)/(s.d1-s.d0)*(s.r1-s.r0)
+}
@@ -0,0 +1,60 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package chart
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTicks(t *testing.T) {
+	var tests = []struct {
+		name     string
+		min, max float64
+		count    int
+		want     []float64
+	}{
+		{"zero to ten", 0, 10, 5, []float64{0, 2, 4, 6, 8, 10}},
+		{"uneven", 1, 9, 5, []float64{0, 2, 4, 6, 8, 10}},
+		{"negative", -7, 3, 5, []float64{-8, -6, -4, -2, 0, 2, 4}},
+		{"decimals", 0.1, 0.3, 3, []float64{0.1, 0.2, 0.3}},
+		{"reversed", 10, 0, 5, []float64{0, 2, 4, 6, 8, 10}},
+		{"equal zero", 0, 0, 2, []float64{0, 1}},
+		{"equal", 4, 4, 2, []float64{0, 5, 10}},
+		{"count below two", 0, 10, 0, []float64{0, 10}},
+		{"NaN min", math.NaN(), 4, 2, []float64{0, 5, 10}},
+		{"infinite max", 4, math.Inf(1), 2, []float64{0, 5, 10}},
+		{"NaN", math.NaN(), math.NaN(), 2, []float64{0, 1}},
+		{"infinite", math.Inf(-1), math.Inf(1), 2, []float64{0, 1}},
+		{"too large", -math.MaxFloat64, math.MaxFloat64, 5, []float64{-math.MaxFloat64, math.MaxFloat64}},
+	}
+	for _, test := range tests {
+		var got = Ticks(test.min, test.max, test.count)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.want, got)
+		}
+	}
+}
+
+func TestFormatTick(t *testing.T) {
+	var tests = []struct {
+		ticks []float64
+		v     float64
+		want  string
+	}{
+		{[]float64{0, 2, 4}, 2, "2"},
+		{[]float64{0, 0.5, 1}, 0.5, "0.5"},
+		{[]float64{0, 0.2, 0.4}, 0.25, "0.2"},
+		{[]float64{0.01, 0.02}, 0.015, "0.01"},
+		{[]float64{0, 1000}, 1234.5, "1234"},
+		{[]float64{1}, 1.5, "2"},
+		{nil, 3, "3"},
+	}
+	for _, test := range tests {
+		if got := FormatTick(test.ticks)(test.v); got != test.want {
+			t.Errorf("FormatTick(%v)(%v): expected %q, got %q", test.ticks, test.v, test.want, got)
+		}
+	}
+}
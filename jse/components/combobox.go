@@ -0,0 +1,548 @@
+package shortcuts
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nigel2392/jsext/v2"
+	"github.com/Nigel2392/jsext/v2/css"
+	"github.com/Nigel2392/jsext/v2/encoding"
+	"github.com/Nigel2392/jsext/v2/errs"
+	"github.com/Nigel2392/jsext/v2/fetch"
+	"github.com/Nigel2392/jsext/v2/jse"
+	"github.com/Nigel2392/jsext/v2/jsrand"
+)
+
+//	<div class="jsext-combobox">
+//		<div class="jsext-combobox-header">
+//			<span class="jsext-combobox-chip">Option 1 <button>×</button></span>
+//			<input role="combobox" aria-controls="jsext-combobox-xxx" aria-expanded="true">
+//		</div>
+//		<ul class="jsext-combobox-listbox" role="listbox" id="jsext-combobox-xxx">
+//			<li role="option" class="jsext-combobox-option-active">Opt<mark>ion</mark> 1</li>
+//			<li role="option">Opt<mark>ion</mark> 2</li>
+//		</ul>
+//	</div>
+
+// RenderableSelectOption can be implemented by a SearchableSelectOption
+// to customize how it is displayed in the results of a Combobox.
+type RenderableSelectOption interface {
+	SearchableSelectOption
+	Render(query string) *jse.Element
+}
+
+type ComboboxOptions struct {
+	// Load fetches the options for the query.
+	//
+	// The context is cancelled when a newer query is made.
+	Load func(ctx context.Context, query string) ([]SearchableSelectOption, error)
+	// URL is used when Load is nil, the query is sent as the "q" parameter.
+	//
+	// The response should be a JSON array of {"display": "...", "value": "..."} objects,
+	// unless Decode is set.
+	URL    string
+	Decode func(resp *fetch.Response) ([]SearchableSelectOption, error)
+	// Options shown when the input has less than MinChars characters.
+	InitialOptions []SearchableSelectOption
+	Selected       []SearchableSelectOption
+	Multiple       bool
+	OnSelect       func(option SearchableSelectOption)
+	OnRemove       func(option SearchableSelectOption)
+	OnError        func(err error)
+	// Results for a query are cached, unless DisableCache is set.
+	DisableCache bool
+	Delay        time.Duration
+	MinChars     int
+	Placeholder  string
+	NoResults    string
+	ClassPrefix  string
+	InputOptions *jse.InputOptions
+}
+
+func (opts *ComboboxOptions) Defaults() {
+	if opts.Delay == 0 {
+		opts.Delay = 200 * time.Millisecond
+	}
+	if opts.MinChars == 0 {
+		opts.MinChars = 1
+	}
+	if opts.NoResults == "" {
+		opts.NoResults = "No results"
+	}
+	if opts.ClassPrefix == "" {
+		opts.ClassPrefix = "jsext-"
+	}
+	if opts.InputOptions == nil {
+		opts.InputOptions = &jse.InputOptions{}
+	}
+	if opts.Placeholder != "" {
+		opts.InputOptions.Placeholder = opts.Placeholder
+	}
+	opts.InputOptions.AutoComplete = "off"
+	if opts.Load == nil && opts.URL != "" {
+		opts.Load = opts.fetch
+	}
+}
+
+// fetch loads the options from opts.URL.
+func (opts *ComboboxOptions) fetch(ctx context.Context, query string) ([]SearchableSelectOption, error) {
+	var u, err = url.Parse(opts.URL)
+	if err != nil {
+		return nil, err
+	}
+	var values = u.Query()
+	values.Set("q", query)
+	u.RawQuery = values.Encode()
+
+	var req = fetch.NewRequest("GET", u.String())
+	req.SetContext(ctx)
+	resp, err := fetch.Fetch(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, errs.Error("jsext/combobox: server responded with " + resp.Status)
+	}
+	if opts.Decode != nil {
+		return opts.Decode(resp)
+	}
+
+	defer resp.Body.Close()
+	var body []byte
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var results []struct {
+		Display string `json:"display"`
+		Value   string `json:"value"`
+	}
+	if err = encoding.DecodeJSON(body, &results); err != nil {
+		return nil, err
+	}
+	var options = make([]SearchableSelectOption, len(results))
+	for i, result := range results {
+		options[i] = NewSearchableSelectOption(result.Display, result.Value)
+	}
+	return options, nil
+}
+
+type Combobox struct {
+	root    *jse.Element
+	header  *jse.Element
+	input   *jse.Element
+	listbox *jse.Element
+	opts    ComboboxOptions
+	id      string
+
+	query    string
+	results  []SearchableSelectOption
+	items    []*jse.Element
+	active   int
+	open     bool
+	selected []SearchableSelectOption
+	chips    []*jse.Element
+
+	cache  map[string][]SearchableSelectOption
+	cancel context.CancelFunc
+}
+
+// NewCombobox creates a new combobox, which loads its options asynchronously.
+func NewCombobox(opts ComboboxOptions) *Combobox {
+	opts.Defaults()
+
+	var c = &Combobox{
+		opts:   opts,
+		id:     opts.ClassPrefix + "combobox-" + jsrand.String(8),
+		active: -1,
+		cache:  make(map[string][]SearchableSelectOption),
+	}
+
+	c.root = jse.Div(c.class("combobox"))
+	c.header = c.root.Div(c.class("combobox-header"))
+	c.input = c.header.Input("text", c.class("combobox-input"), opts.InputOptions)
+	c.input.SetAttr("role", "combobox")
+	c.input.SetAttr("aria-autocomplete", "list")
+	c.input.SetAttr("aria-expanded", "false")
+	c.input.SetAttr("aria-controls", c.id)
+
+	c.listbox = c.root.NewElement("ul")
+	c.listbox.ClassList(c.class("combobox-listbox"))
+	c.listbox.SetAttr("role", "listbox")
+	c.listbox.SetAttr("id", c.id)
+	c.listbox.Style().Display("none")
+	if opts.Multiple {
+		c.listbox.SetAttr("aria-multiselectable", "true")
+	}
+
+	for _, option := range opts.Selected {
+		c.addSelected(option)
+	}
+	if !opts.Multiple && len(c.selected) > 0 {
+		c.input.Set("value", c.selected[0].Display())
+	}
+
+	c.input.OnKeyDown(c.onKeyDown)
+	c.input.OnFocus(func(_ *jse.Element, _ jsext.Event) {
+		if len([]rune(c.input.Get("value").String())) < opts.MinChars {
+			c.showResults("", opts.InitialOptions)
+		}
+	})
+	c.input.OnBlur(func(_ *jse.Element, _ jsext.Event) {
+		c.Close()
+	})
+	AfterCharsAndDuration(
+		opts.MinChars,
+		opts.Delay,
+		c.input,
+		func(this *jse.Element, _ jsext.Event) {
+			c.Search(this.Get("value").String())
+		},
+		func(_ *jse.Element, _ jsext.Event) {
+			c.abort()
+			c.showResults("", opts.InitialOptions)
+		},
+	)
+
+	c.root.StyleBlock(`
+		.` + c.class("combobox") + ` {
+			position: relative;
+		}
+		.` + c.class("combobox-header") + ` {
+			display: flex;
+			flex-wrap: wrap;
+			align-items: center;
+			gap: 4px;
+		}
+		.` + c.class("combobox-input") + ` {
+			flex: 1;
+			min-width: 80px;
+		}
+		.` + c.class("combobox-chip") + ` {
+			display: inline-flex;
+			align-items: center;
+			gap: 2px;
+			padding: 0 4px;
+			border-radius: 4px;
+			background: ` + css.Var(css.ColorHover) + `;
+		}
+		.` + c.class("combobox-chip") + ` button {
+			border: 0;
+			background: none;
+			cursor: pointer;
+		}
+		.` + c.class("combobox-listbox") + ` {
+			position: absolute;
+			left: 0;
+			right: 0;
+			z-index: 10;
+			margin: 0;
+			padding: 0;
+			list-style: none;
+			max-height: 300px;
+			overflow: auto;
+			background: ` + css.Var(css.ColorBackground) + `;
+			color: ` + css.Var(css.ColorText) + `;
+			border: 1px solid ` + css.Var(css.ColorBorder) + `;
+		}
+		.` + c.class("combobox-option") + ` {
+			padding: 4px 8px;
+			cursor: pointer;
+		}
+		.` + c.class("combobox-option-active") + ` {
+			background: ` + css.Var(css.ColorHover) + `;
+		}`)
+
+	return c
+}
+
+func (c *Combobox) Element() *jse.Element {
+	return c.root
+}
+
+func (c *Combobox) Input() *jse.Element {
+	return c.input
+}
+
+// Selected returns the selected options.
+func (c *Combobox) Selected() []SearchableSelectOption {
+	return c.selected
+}
+
+// Value returns the value of the first selected option, or an empty string.
+func (c *Combobox) Value() string {
+	if len(c.selected) == 0 {
+		return ""
+	}
+	return c.selected[0].Value()
+}
+
+// Search loads the options for the query and displays them.
+//
+// Any pending request for a previous query is cancelled.
+func (c *Combobox) Search(query string) {
+	c.abort()
+	if !c.opts.DisableCache {
+		if results, ok := c.cache[query]; ok {
+			c.showResults(query, results)
+			return
+		}
+	}
+	if c.opts.Load == nil {
+		return
+	}
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.root.SetAttr("aria-busy", "true")
+	go func() {
+		defer cancel()
+		var results, err = c.opts.Load(ctx, query)
+		if ctx.Err() != nil {
+			// A newer query has been made.
+			return
+		}
+		c.root.DelAttr("aria-busy")
+		if err != nil {
+			if c.opts.OnError != nil {
+				c.opts.OnError(err)
+			}
+			return
+		}
+		if !c.opts.DisableCache {
+			c.cache[query] = results
+		}
+		c.showResults(query, results)
+	}()
+}
+
+// ClearCache removes all cached results.
+func (c *Combobox) ClearCache() {
+	c.cache = make(map[string][]SearchableSelectOption)
+}
+
+// Select selects the option.
+//
+// If the combobox allows multiple options and the option is already selected, it is removed.
+func (c *Combobox) Select(option SearchableSelectOption) {
+	if !c.opts.Multiple {
+		c.selected = c.selected[:0]
+		c.input.Set("value", option.Display())
+		c.addSelected(option)
+		c.Close()
+	} else {
+		if c.indexOf(option) >= 0 {
+			c.Remove(option)
+			return
+		}
+		c.addSelected(option)
+		c.input.Set("value", "")
+		c.renderResults()
+	}
+	if c.opts.OnSelect != nil {
+		go c.opts.OnSelect(option)
+	}
+}
+
+// Remove deselects the option.
+func (c *Combobox) Remove(option SearchableSelectOption) {
+	var i = c.indexOf(option)
+	if i < 0 {
+		return
+	}
+	c.selected = append(c.selected[:i], c.selected[i+1:]...)
+	if c.opts.Multiple {
+		c.chips[i].Remove()
+		c.chips = append(c.chips[:i], c.chips[i+1:]...)
+	} else {
+		c.input.Set("value", "")
+	}
+	if c.open {
+		c.renderResults()
+	}
+	if c.opts.OnRemove != nil {
+		go c.opts.OnRemove(option)
+	}
+}
+
+// Open shows the results.
+func (c *Combobox) Open() {
+	c.open = true
+	c.listbox.Style().Display("block")
+	c.input.SetAttr("aria-expanded", "true")
+}
+
+// Close hides the results.
+func (c *Combobox) Close() {
+	c.open = false
+	c.setActive(-1)
+	c.listbox.Style().Display("none")
+	c.input.SetAttr("aria-expanded", "false")
+}
+
+func (c *Combobox) class(name string) string {
+	return c.opts.ClassPrefix + name
+}
+
+func (c *Combobox) abort() {
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+	c.root.DelAttr("aria-busy")
+}
+
+func (c *Combobox) indexOf(option SearchableSelectOption) int {
+	for i, s := range c.selected {
+		if s.Value() == option.Value() {
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *Combobox) addSelected(option SearchableSelectOption) {
+	c.selected = append(c.selected, option)
+	if !c.opts.Multiple {
+		return
+	}
+	var chip = jse.Span()
+	chip.ClassList(c.class("combobox-chip"))
+	chip.Span(option.Display())
+	var remove = chip.Button("×", func(_ *jse.Element, event jsext.Event) {
+		event.PreventDefault()
+		c.Remove(option)
+	})
+	remove.SetAttr("type", "button")
+	remove.SetAttr("aria-label", "Remove "+option.Display())
+	c.header.InsertBefore(chip, c.input)
+	c.chips = append(c.chips, chip)
+}
+
+func (c *Combobox) showResults(query string, results []SearchableSelectOption) {
+	c.query = query
+	c.results = results
+	c.renderResults()
+	if len(results) == 0 && query == "" {
+		c.Close()
+		return
+	}
+	c.Open()
+}
+
+func (c *Combobox) renderResults() {
+	c.listbox.ClearInnerHTML()
+	c.items = c.items[:0]
+	c.active = -1
+	c.input.DelAttr("aria-activedescendant")
+
+	if len(c.results) == 0 {
+		var empty = c.listbox.NewElement("li", c.opts.NoResults)
+		empty.ClassList(c.class("combobox-empty"))
+		empty.SetAttr("aria-disabled", "true")
+		return
+	}
+
+	for i, option := range c.results {
+		var option = option
+		var li = c.listbox.NewElement("li")
+		li.ClassList(c.class("combobox-option"))
+		li.SetAttr("role", "option")
+		li.SetAttr("id", c.id+"-"+strconv.Itoa(i))
+		li.SetAttr("aria-selected", strconv.FormatBool(c.indexOf(option) >= 0))
+		li.Dataset().Set("value", option.Value())
+		if r, ok := option.(RenderableSelectOption); ok {
+			li.AppendChild(r.Render(c.query))
+		} else {
+			highlight(li, option.Display(), c.query)
+		}
+		// Keep the focus on the input.
+		li.OnMouseDown(func(_ *jse.Element, event jsext.Event) {
+			event.PreventDefault()
+		})
+		li.OnClick(func(_ *jse.Element, _ jsext.Event) {
+			c.Select(option)
+		})
+		c.items = append(c.items, li)
+	}
+}
+
+func (c *Combobox) setActive(index int) {
+	if c.active >= 0 && c.active < len(c.items) {
+		c.items[c.active].ClassList().Call("remove", c.class("combobox-option-active"))
+	}
+	c.active = index
+	if index < 0 || index >= len(c.items) {
+		c.input.DelAttr("aria-activedescendant")
+		return
+	}
+	var item = c.items[index]
+	item.ClassList(c.class("combobox-option-active"))
+	item.Call("scrollIntoView", map[string]any{"block": "nearest"})
+	c.input.SetAttr("aria-activedescendant", c.id+"-"+strconv.Itoa(index))
+}
+
+func (c *Combobox) onKeyDown(_ *jse.Element, event jsext.Event) {
+	switch event.Get("key").String() {
+	case "ArrowDown":
+		event.PreventDefault()
+		if !c.open {
+			c.Open()
+		}
+		if len(c.items) > 0 {
+			c.setActive((c.active + 1) % len(c.items))
+		}
+	case "ArrowUp":
+		event.PreventDefault()
+		if len(c.items) > 0 {
+			var index = c.active - 1
+			if index < 0 {
+				index = len(c.items) - 1
+			}
+			c.setActive(index)
+		}
+	case "Enter":
+		if c.open && c.active >= 0 && c.active < len(c.results) {
+			event.PreventDefault()
+			c.Select(c.results[c.active])
+		}
+	case "Escape":
+		if c.open {
+			event.PreventDefault()
+			c.Close()
+		}
+	case "Backspace":
+		if c.opts.Multiple && len(c.selected) > 0 && c.input.Get("value").String() == "" {
+			c.Remove(c.selected[len(c.selected)-1])
+		}
+	}
+}
+
+// highlight appends the text to the element, wrapping case-insensitive matches of query in <mark> elements.
+func highlight(e *jse.Element, text, query string) {
+	var lowerText, lowerQuery = strings.ToLower(text), strings.ToLower(query)
+	// Offsets in the lower case text are only valid in text if lower casing kept the lengths.
+	if query == "" || len(lowerText) != len(text) || len(lowerQuery) != len(query) {
+		e.Span(text)
+		return
+	}
+	for {
+		var i = strings.Index(lowerText, lowerQuery)
+		if i < 0 {
+			break
+		}
+		if i > 0 {
+			e.Span(text[:i])
+		}
+		e.Mark(text[i : i+len(lowerQuery)])
+		text, lowerText = text[i+len(lowerQuery):], lowerText[i+len(lowerQuery):]
+	}
+	if text != "" {
+		e.Span(text)
+	}
+}
@@ -0,0 +1,34 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package shortcuts
+
+import (
+	"testing"
+
+	"github.com/Nigel2392/jsext/v2/jse"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+func TestHighlight(t *testing.T) {
+	var tests = []struct {
+		text, query string
+		want        string
+	}{
+		{"Hello world", "o", "<span>Hell</span><mark>o</mark><span> w</span><mark>o</mark><span>rld</span>"},
+		{"Hello", "HEL", "<mark>Hel</mark><span>lo</span>"},
+		{"Hello", "", "<span>Hello</span>"},
+		{"Hello", "x", "<span>Hello</span>"},
+		// The Kelvin sign is 3 bytes, but lower cases to a 1 byte k.
+		{"kilo", "K", "<span>kilo</span>"},
+		{"Kelvin", "e", "<span>Kelvin</span>"},
+	}
+	for _, test := range tests {
+		js.Reset()
+		var e = jse.NewElement("div")
+		highlight(e, test.text, test.query)
+		if got := e.JSValue().Get("innerHTML").String(); got != test.want {
+			t.Errorf("highlight(%q, %q) = %s, want %s", test.text, test.query, got, test.want)
+		}
+	}
+}
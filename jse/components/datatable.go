@@ -0,0 +1,935 @@
+package shortcuts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nigel2392/jsext/v2"
+	"github.com/Nigel2392/jsext/v2/css"
+	"github.com/Nigel2392/jsext/v2/encoding"
+	"github.com/Nigel2392/jsext/v2/errs"
+	"github.com/Nigel2392/jsext/v2/fetch"
+	"github.com/Nigel2392/jsext/v2/jse"
+)
+
+//	<div class="jsext-datatable">
+//		<div class="jsext-datatable-toolbar">
+//			<input type="search" class="jsext-datatable-filter">
+//		</div>
+//		<div class="jsext-datatable-scroll">
+//			<table class="jsext-datatable-table">
+//				<thead> <tr> <th>...</th> </tr> </thead>
+//				<tbody> <tr> <td>...</td> </tr> </tbody>
+//			</table>
+//		</div>
+//		<div class="jsext-datatable-pagination">
+//			<button>«</button> <button>‹</button>
+//			<span>Page 1 of 10</span>
+//			<button>›</button> <button>»</button>
+//		</div>
+//	</div>
+
+type SortDirection int
+
+const (
+	SortNone SortDirection = iota
+	SortAscending
+	SortDescending
+)
+
+func (d SortDirection) String() string {
+	switch d {
+	case SortAscending:
+		return "asc"
+	case SortDescending:
+		return "desc"
+	}
+	return ""
+}
+
+// Column describes a single column of a DataTable.
+type Column[T any] struct {
+	// Key uniquely identifies the column.
+	//
+	// It is also sent to the server as the sort key in server-side mode.
+	Key string
+	// Name is displayed in the table header.
+	Name string
+	// Value returns the value of the cell, used for displaying, sorting and filtering.
+	Value func(row T) any
+	// Render optionally renders the cell, instead of displaying the value as text.
+	Render func(row T) *jse.Element
+	// Less optionally overrides the default comparison when sorting.
+	Less       func(a, b T) bool
+	Width      string
+	Sortable   bool
+	Filterable bool
+}
+
+// DataTableQuery is the current state of the table.
+//
+// It is passed to the server in server-side mode.
+type DataTableQuery struct {
+	Page          int
+	PageSize      int
+	SortKey       string
+	SortDirection SortDirection
+	Filter        string
+}
+
+// Values returns the query as url values.
+//
+// page, page_size, sort, order and q are set.
+func (q DataTableQuery) Values() url.Values {
+	var v = url.Values{}
+	v.Set("page", strconv.Itoa(q.Page))
+	v.Set("page_size", strconv.Itoa(q.PageSize))
+	if q.SortKey != "" && q.SortDirection != SortNone {
+		v.Set("sort", q.SortKey)
+		v.Set("order", q.SortDirection.String())
+	}
+	if q.Filter != "" {
+		v.Set("q", q.Filter)
+	}
+	return v
+}
+
+// DataTableServer enables server-side mode for a DataTable.
+//
+// Sorting, filtering and pagination are then left to the server.
+type DataTableServer[T any] struct {
+	URL     string
+	Headers map[string]string
+	// Total is the number of rows on the server, when the table is created with the first page.
+	//
+	// If it is 0, the first page is fetched to get the total.
+	Total int
+	// Request optionally builds the request for the query.
+	//
+	// By default a GET request is made to URL with the query values appended.
+	Request func(q DataTableQuery) *fetch.Request
+	// Decode optionally decodes the response.
+	//
+	// By default the response is expected to be JSON in the format of:
+	//
+	//	{"rows": [...], "total": 100}
+	Decode func(resp *fetch.Response) (rows []T, total int, err error)
+	// OnError is called when fetching a page failed.
+	OnError func(err error)
+}
+
+type DataTableOptions[T any] struct {
+	// Columns of the table.
+	//
+	// If nil, the columns are generated from the struct tags of T.
+	//
+	//	type User struct {
+	//		ID    int    `table:"ID,sortable"`
+	//		Name  string `table:"Name,sortable,filterable"`
+	//		Email string `table:"E-Mail,filterable"`
+	//		Hash  string `table:"-"`
+	//	}
+	Columns []Column[T]
+	// Number of rows per page, 0 disables pagination.
+	PageSize int
+	// Filter optionally overrides the default filter.
+	Filter func(row T, query string) bool
+	// RowKey returns a unique key for the row, used to keep track of selected rows.
+	//
+	// Defaults to the index of the row in the data.
+	RowKey      func(row T) string
+	Selectable  bool
+	MultiSelect bool
+	OnSelect    func(selected []T)
+	OnRowClick  func(row T)
+	// Virtual only renders the rows which are visible, plus Overscan rows.
+	//
+	// Every row must have the same height, RowHeight.
+	Virtual   bool
+	RowHeight int
+	Overscan  int
+	// Height of the scrolling container, required for virtual tables.
+	Height            string
+	FilterPlaceholder string
+	ClassPrefix       string
+	// Server enables server-side mode.
+	Server *DataTableServer[T]
+}
+
+func (o *DataTableOptions[T]) Defaults() {
+	if o.ClassPrefix == "" {
+		o.ClassPrefix = "jsext-"
+	}
+	if o.RowHeight == 0 {
+		o.RowHeight = 32
+	}
+	if o.Overscan == 0 {
+		o.Overscan = 10
+	}
+	if o.Virtual && o.Height == "" {
+		o.Height = "400px"
+	}
+	if o.FilterPlaceholder == "" {
+		o.FilterPlaceholder = "Filter..."
+	}
+	if o.Server != nil && o.PageSize == 0 {
+		o.PageSize = 25
+	}
+	if o.Columns == nil {
+		o.Columns = ColumnsFromStruct[T]()
+	}
+}
+
+type dataRow[T any] struct {
+	key   string
+	value T
+}
+
+type DataTable[T any] struct {
+	root       *jse.Element
+	scroll     *jse.Element
+	head       *jse.Element
+	body       *jse.Element
+	pagination *jse.Element
+	selectAll  *jse.Element
+
+	opts DataTableOptions[T]
+
+	data     []T
+	rows     []dataRow[T] // filtered and sorted rows, or the rows of the current page in server mode
+	total    int
+	query    DataTableQuery
+	selected map[string]T
+	order    []string
+
+	cancel        context.CancelFunc
+	renderedStart int
+	renderedEnd   int
+}
+
+// NewDataTable creates a new table from the data.
+//
+// In server-side mode data is used as the initial page, and Server.Total as the number of rows.
+func NewDataTable[T any](data []T, opts DataTableOptions[T]) *DataTable[T] {
+	opts.Defaults()
+
+	var t = &DataTable[T]{
+		opts:     opts,
+		data:     data,
+		selected: make(map[string]T),
+		query: DataTableQuery{
+			Page:     1,
+			PageSize: opts.PageSize,
+		},
+	}
+
+	t.root = jse.Div(t.class("datatable"))
+	var toolbar = t.root.Div(t.class("datatable-toolbar"))
+	if t.filterable() {
+		var filter = toolbar.Input("search", "filter", &jse.InputOptions{
+			Placeholder: opts.FilterPlaceholder,
+			Classlist:   []string{t.class("datatable-filter")},
+		})
+		AfterCharsAndDuration(0, 200*time.Millisecond, filter, func(this *jse.Element, _ jsext.Event) {
+			t.SetFilter(this.Get("value").String())
+		}, nil)
+	}
+
+	t.scroll = t.root.Div(t.class("datatable-scroll"))
+	if opts.Virtual {
+		t.scroll.Style().Height(opts.Height)
+		t.scroll.OnScroll(func(_ *jse.Element, _ jsext.Event) {
+			t.renderVirtual(false)
+		})
+	}
+
+	var table = t.scroll.Table(t.class("datatable-table"))
+	t.head = table.Thead()
+	t.body = table.Tbody()
+	t.pagination = t.root.Div(t.class("datatable-pagination"))
+	t.renderHead()
+
+	t.root.StyleBlock(`
+		.` + t.class("datatable-scroll") + ` {
+			overflow: auto;
+		}
+		.` + t.class("datatable-table") + ` {
+			width: 100%;
+			border-collapse: collapse;
+		}
+		.` + t.class("datatable-table") + ` th[data-sortable] {
+			cursor: pointer;
+			user-select: none;
+		}
+		.` + t.class("datatable-table") + ` thead th {
+			position: sticky;
+			top: 0;
+			background: ` + css.Var(css.ColorBackground) + `;
+		}
+		.` + t.class("datatable-row-selected") + ` {
+			background: ` + css.Var(css.ColorHover) + `;
+		}
+		.` + t.class("datatable-pagination") + ` {
+			display: flex;
+			align-items: center;
+			gap: 5px;
+		}`)
+
+	if opts.Server != nil {
+		t.total = opts.Server.Total
+		t.rows = t.keyed(data, 0)
+		t.render()
+		if len(data) == 0 || t.total == 0 {
+			t.Refresh()
+		}
+		return t
+	}
+
+	t.Refresh()
+	return t
+}
+
+// ColumnsFromStruct generates columns from the struct tags of T.
+//
+// The tag is in the format of `table:"Name,sortable,filterable"`.
+// Fields with the tag `table:"-"` and unexported fields are skipped.
+//
+// The key of the column is the json tag of the field, or the field name.
+func ColumnsFromStruct[T any]() []Column[T] {
+	var typ = reflect.TypeOf((*T)(nil)).Elem()
+	var isPtr = typ.Kind() == reflect.Ptr
+	if isPtr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return []Column[T]{{
+			Key:        "value",
+			Name:       "Value",
+			Value:      func(row T) any { return row },
+			Sortable:   true,
+			Filterable: true,
+		}}
+	}
+
+	var columns = make([]Column[T], 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		var field = typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		var tag = field.Tag.Get("table")
+		if tag == "-" {
+			continue
+		}
+		var parts = strings.Split(tag, ",")
+		var column = Column[T]{
+			Key:  field.Name,
+			Name: strings.TrimSpace(parts[0]),
+		}
+		if column.Name == "" {
+			column.Name = field.Name
+		}
+		if jsonTag := strings.Split(field.Tag.Get("json"), ",")[0]; jsonTag != "" && jsonTag != "-" {
+			column.Key = jsonTag
+		}
+		for _, opt := range parts[1:] {
+			switch strings.TrimSpace(opt) {
+			case "sortable":
+				column.Sortable = true
+			case "filterable":
+				column.Filterable = true
+			}
+		}
+		var index = field.Index
+		column.Value = func(row T) any {
+			var v = reflect.ValueOf(row)
+			if isPtr {
+				if v.IsNil() {
+					return nil
+				}
+				v = v.Elem()
+			}
+			return v.FieldByIndex(index).Interface()
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+func (t *DataTable[T]) class(name string) string {
+	return t.opts.ClassPrefix + name
+}
+
+func (t *DataTable[T]) filterable() bool {
+	if t.opts.Filter != nil || t.opts.Server != nil {
+		return true
+	}
+	for _, column := range t.opts.Columns {
+		if column.Filterable {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *DataTable[T]) Element() *jse.Element {
+	return t.root
+}
+
+// Data returns the data of the table.
+//
+// In server-side mode, this is the data of the current page.
+func (t *DataTable[T]) Data() []T {
+	return t.data
+}
+
+// SetData replaces the data of the table.
+//
+// The selection is cleared if no RowKey function was provided.
+func (t *DataTable[T]) SetData(data []T) {
+	t.data = data
+	if t.opts.RowKey == nil {
+		t.clearSelection()
+	}
+	t.Refresh()
+}
+
+// Query returns the current state of the table.
+func (t *DataTable[T]) Query() DataTableQuery {
+	return t.query
+}
+
+// Total returns the number of rows after filtering.
+func (t *DataTable[T]) Total() int {
+	return t.total
+}
+
+// SortBy sorts the table by the column with the given key.
+func (t *DataTable[T]) SortBy(key string, direction SortDirection) {
+	t.query.SortKey = key
+	t.query.SortDirection = direction
+	if direction == SortNone {
+		t.query.SortKey = ""
+	}
+	t.query.Page = 1
+	t.renderHead()
+	t.Refresh()
+}
+
+// SetFilter filters the rows of the table.
+func (t *DataTable[T]) SetFilter(query string) {
+	query = strings.TrimSpace(query)
+	if query == t.query.Filter {
+		return
+	}
+	t.query.Filter = query
+	t.query.Page = 1
+	t.Refresh()
+}
+
+// SetPage changes the current page, starting at 1.
+func (t *DataTable[T]) SetPage(page int) {
+	if page < 1 {
+		page = 1
+	}
+	if count := t.PageCount(); page > count {
+		page = count
+	}
+	if page == t.query.Page {
+		return
+	}
+	t.query.Page = page
+	if t.opts.Server != nil {
+		t.Refresh()
+		return
+	}
+	t.render()
+}
+
+// Page returns the current page, starting at 1.
+func (t *DataTable[T]) Page() int {
+	return t.query.Page
+}
+
+// PageCount returns the number of pages.
+func (t *DataTable[T]) PageCount() int {
+	if t.query.PageSize <= 0 || t.total == 0 {
+		return 1
+	}
+	return int(math.Ceil(float64(t.total) / float64(t.query.PageSize)))
+}
+
+// Selected returns the selected rows, in order of selection.
+func (t *DataTable[T]) Selected() []T {
+	var selected = make([]T, 0, len(t.order))
+	for _, key := range t.order {
+		selected = append(selected, t.selected[key])
+	}
+	return selected
+}
+
+// ClearSelection deselects all rows.
+func (t *DataTable[T]) ClearSelection() {
+	t.clearSelection()
+	t.renderRows()
+	t.onSelect()
+}
+
+func (t *DataTable[T]) clearSelection() {
+	t.selected = make(map[string]T)
+	t.order = t.order[:0]
+}
+
+// Refresh filters, sorts and renders the table.
+//
+// In server-side mode, the current page is fetched from the server.
+func (t *DataTable[T]) Refresh() {
+	if t.opts.Server != nil {
+		t.fetch()
+		return
+	}
+
+	var rows = t.keyed(t.data, 0)
+	if t.query.Filter != "" {
+		var filtered = rows[:0]
+		for _, row := range rows {
+			if t.matches(row.value, t.query.Filter) {
+				filtered = append(filtered, row)
+			}
+		}
+		rows = filtered
+	}
+
+	if column, ok := t.column(t.query.SortKey); ok && t.query.SortDirection != SortNone {
+		var less = column.Less
+		if less == nil {
+			less = func(a, b T) bool {
+				return compareValues(column.Value(a), column.Value(b)) < 0
+			}
+		}
+		var descending = t.query.SortDirection == SortDescending
+		sort.SliceStable(rows, func(i, j int) bool {
+			if descending {
+				return less(rows[j].value, rows[i].value)
+			}
+			return less(rows[i].value, rows[j].value)
+		})
+	}
+
+	t.rows = rows
+	t.total = len(rows)
+	if t.query.Page > t.PageCount() {
+		t.query.Page = t.PageCount()
+	}
+	t.render()
+}
+
+func (t *DataTable[T]) keyed(data []T, offset int) []dataRow[T] {
+	var rows = make([]dataRow[T], len(data))
+	for i, row := range data {
+		var key string
+		if t.opts.RowKey != nil {
+			key = t.opts.RowKey(row)
+		} else {
+			key = strconv.Itoa(offset + i)
+		}
+		rows[i] = dataRow[T]{key: key, value: row}
+	}
+	return rows
+}
+
+func (t *DataTable[T]) matches(row T, query string) bool {
+	if t.opts.Filter != nil {
+		return t.opts.Filter(row, query)
+	}
+	query = strings.ToLower(query)
+	for _, column := range t.opts.Columns {
+		if !column.Filterable || column.Value == nil {
+			continue
+		}
+		if strings.Contains(strings.ToLower(fmt.Sprint(column.Value(row))), query) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *DataTable[T]) column(key string) (Column[T], bool) {
+	for _, column := range t.opts.Columns {
+		if column.Key == key {
+			return column, true
+		}
+	}
+	return Column[T]{}, false
+}
+
+func (t *DataTable[T]) fetch() {
+	if t.cancel != nil {
+		t.cancel()
+	}
+	var ctx, cancel = context.WithCancel(context.Background())
+	t.cancel = cancel
+	var query = t.query
+	var server = t.opts.Server
+
+	go func() {
+		var rows, total, err = server.load(ctx, query)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			if server.OnError != nil {
+				server.OnError(err)
+			}
+			return
+		}
+		t.data = rows
+		t.rows = t.keyed(rows, (query.Page-1)*query.PageSize)
+		t.total = total
+		t.render()
+	}()
+}
+
+func (s *DataTableServer[T]) load(ctx context.Context, query DataTableQuery) ([]T, int, error) {
+	var req *fetch.Request
+	if s.Request != nil {
+		req = s.Request(query)
+	} else {
+		var u, err = url.Parse(s.URL)
+		if err != nil {
+			return nil, 0, err
+		}
+		var values = u.Query()
+		for k, v := range query.Values() {
+			values[k] = v
+		}
+		u.RawQuery = values.Encode()
+		req = fetch.NewRequest("GET", u.String())
+	}
+	for k, v := range s.Headers {
+		req.SetHeader(k, v)
+	}
+	req.SetContext(ctx)
+
+	var resp, err = fetch.Fetch(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, 0, errs.Error("jsext/datatable: server responded with " + resp.Status)
+	}
+	if s.Decode != nil {
+		return s.Decode(resp)
+	}
+
+	defer resp.Body.Close()
+	var body []byte
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	var page struct {
+		Rows  []T `json:"rows"`
+		Total int `json:"total"`
+	}
+	if err = encoding.DecodeJSON(body, &page); err != nil {
+		return nil, 0, err
+	}
+	return page.Rows, page.Total, nil
+}
+
+// pageRows returns the rows which should be displayed on the current page.
+func (t *DataTable[T]) pageRows() []dataRow[T] {
+	if t.opts.Server != nil || t.query.PageSize <= 0 {
+		return t.rows
+	}
+	var start = (t.query.Page - 1) * t.query.PageSize
+	if start > len(t.rows) {
+		start = len(t.rows)
+	}
+	var end = start + t.query.PageSize
+	if end > len(t.rows) {
+		end = len(t.rows)
+	}
+	return t.rows[start:end]
+}
+
+func (t *DataTable[T]) render() {
+	t.renderRows()
+	t.renderPagination()
+}
+
+func (t *DataTable[T]) renderHead() {
+	t.head.ClearInnerHTML()
+	var tr = t.head.Tr()
+	if t.opts.Selectable {
+		var th = tr.Th()
+		if t.opts.MultiSelect {
+			t.selectAll = th.Input("checkbox", "select-all", nil)
+			t.selectAll.OnChange(func(this *jse.Element, _ jsext.Event) {
+				var checked = this.Get("checked").Bool()
+				for _, row := range t.pageRows() {
+					t.setSelected(row, checked)
+				}
+				t.renderRows()
+				t.onSelect()
+			})
+		}
+	}
+	for _, column := range t.opts.Columns {
+		var th = tr.Th(column.Name)
+		th.Dataset().Set("key", column.Key)
+		if column.Width != "" {
+			th.Style().Width(column.Width)
+		}
+		if !column.Sortable {
+			continue
+		}
+		th.SetAttr("data-sortable", "true")
+		if column.Key == t.query.SortKey {
+			switch t.query.SortDirection {
+			case SortAscending:
+				th.SetAttr("aria-sort", "ascending")
+				th.InnerText(column.Name + " ▲")
+			case SortDescending:
+				th.SetAttr("aria-sort", "descending")
+				th.InnerText(column.Name + " ▼")
+			}
+		}
+		var key = column.Key
+		th.OnClick(func(_ *jse.Element, _ jsext.Event) {
+			var direction = SortAscending
+			if t.query.SortKey == key {
+				direction = (t.query.SortDirection + 1) % 3
+			}
+			t.SortBy(key, direction)
+		})
+	}
+}
+
+func (t *DataTable[T]) renderRows() {
+	if t.opts.Virtual {
+		t.renderVirtual(true)
+		return
+	}
+	var rows = t.pageRows()
+	t.body.ClearInnerHTML()
+	for _, row := range rows {
+		t.body.AppendChild(t.renderRow(row))
+	}
+	t.updateSelectAll(rows)
+}
+
+// renderVirtual only renders the visible rows of the table.
+//
+// Spacer rows are used to keep the scrollbar the correct size.
+func (t *DataTable[T]) renderVirtual(force bool) {
+	var rows = t.pageRows()
+	var rowHeight = t.opts.RowHeight
+	var visible = t.scroll.ClientHeight()/rowHeight + 1
+	var start = t.scroll.ScrollTop()/rowHeight - t.opts.Overscan
+	if start < 0 {
+		start = 0
+	}
+	var end = start + visible + t.opts.Overscan*2
+	if end > len(rows) {
+		end = len(rows)
+	}
+	if start > end {
+		start = end
+	}
+	if !force && start == t.renderedStart && end == t.renderedEnd {
+		return
+	}
+	t.renderedStart, t.renderedEnd = start, end
+
+	t.body.ClearInnerHTML()
+	t.body.AppendChild(t.spacer(start * rowHeight))
+	for _, row := range rows[start:end] {
+		var tr = t.renderRow(row)
+		tr.Style().Height(strconv.Itoa(rowHeight) + "px")
+		t.body.AppendChild(tr)
+	}
+	t.body.AppendChild(t.spacer((len(rows) - end) * rowHeight))
+	t.updateSelectAll(rows)
+}
+
+func (t *DataTable[T]) spacer(height int) *jse.Element {
+	var tr = jse.Tr()
+	tr.SetAttr("aria-hidden", "true")
+	tr.Style().Height(strconv.Itoa(height) + "px")
+	var td = tr.Td()
+	td.SetAttr("colspan", strconv.Itoa(len(t.opts.Columns)+1))
+	td.Style().Padding("0")
+	td.Style().Border("0")
+	return tr
+}
+
+func (t *DataTable[T]) renderRow(row dataRow[T]) *jse.Element {
+	var tr = jse.Tr()
+	tr.Dataset().Set("key", row.key)
+	var _, selected = t.selected[row.key]
+	if selected {
+		tr.ClassList(t.class("datatable-row-selected"))
+		tr.SetAttr("aria-selected", "true")
+	}
+	if t.opts.Selectable {
+		var typ = "radio"
+		if t.opts.MultiSelect {
+			typ = "checkbox"
+		}
+		var input = tr.Td().Input(typ, t.class("datatable-select"), &jse.InputOptions{
+			Checked: selected,
+		})
+		input.OnClick(func(this *jse.Element, event jsext.Event) {
+			event.StopPropagation()
+			t.toggle(row, this.Get("checked").Bool())
+		})
+	}
+	for _, column := range t.opts.Columns {
+		var td = tr.Td()
+		switch {
+		case column.Render != nil:
+			td.AppendChild(column.Render(row.value))
+		case column.Value != nil:
+			td.InnerText(fmt.Sprint(column.Value(row.value)))
+		}
+	}
+	if t.opts.OnRowClick != nil {
+		tr.OnClick(func(_ *jse.Element, _ jsext.Event) {
+			t.opts.OnRowClick(row.value)
+		})
+	}
+	return tr
+}
+
+func (t *DataTable[T]) toggle(row dataRow[T], checked bool) {
+	if !t.opts.MultiSelect {
+		t.clearSelection()
+	}
+	t.setSelected(row, checked)
+	t.renderRows()
+	t.onSelect()
+}
+
+func (t *DataTable[T]) setSelected(row dataRow[T], selected bool) {
+	var _, ok = t.selected[row.key]
+	switch {
+	case selected && !ok:
+		t.selected[row.key] = row.value
+		t.order = append(t.order, row.key)
+	case !selected && ok:
+		delete(t.selected, row.key)
+		for i, key := range t.order {
+			if key == row.key {
+				t.order = append(t.order[:i], t.order[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+func (t *DataTable[T]) updateSelectAll(rows []dataRow[T]) {
+	if t.selectAll == nil {
+		return
+	}
+	var count int
+	for _, row := range rows {
+		if _, ok := t.selected[row.key]; ok {
+			count++
+		}
+	}
+	t.selectAll.Set("checked", count > 0 && count == len(rows))
+	t.selectAll.Set("indeterminate", count > 0 && count < len(rows))
+}
+
+func (t *DataTable[T]) onSelect() {
+	if t.opts.OnSelect != nil {
+		t.opts.OnSelect(t.Selected())
+	}
+}
+
+func (t *DataTable[T]) renderPagination() {
+	t.pagination.ClearInnerHTML()
+	if t.query.PageSize <= 0 {
+		return
+	}
+	var page, count = t.query.Page, t.PageCount()
+	var button = func(text string, to int, disabled bool) {
+		var b = t.pagination.Button(text, func(_ *jse.Element, _ jsext.Event) {
+			t.SetPage(to)
+		})
+		b.AttrType("button")
+		b.AttrDisabled(disabled)
+	}
+	button("«", 1, page <= 1)
+	button("‹", page-1, page <= 1)
+	t.pagination.Span(fmt.Sprintf("Page %d of %d (%d rows)", page, count, t.total))
+	button("›", page+1, page >= count)
+	button("»", count, page >= count)
+}
+
+// compareValues compares two cell values, returning -1, 0 or 1.
+func compareValues(a, b any) int {
+	switch a := a.(type) {
+	case string:
+		if b, ok := b.(string); ok {
+			return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+		}
+	case time.Time:
+		if b, ok := b.(time.Time); ok {
+			switch {
+			case a.Before(b):
+				return -1
+			case a.After(b):
+				return 1
+			}
+			return 0
+		}
+	case bool:
+		if b, ok := b.(bool); ok {
+			switch {
+			case a == b:
+				return 0
+			case !a:
+				return -1
+			}
+			return 1
+		}
+	}
+
+	var av, bv = reflect.ValueOf(a), reflect.ValueOf(b)
+	if af, ok := numeric(av); ok {
+		if bf, ok := numeric(bv); ok {
+			switch {
+			case af < bf:
+				return -1
+			case af > bf:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func numeric(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,154 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package shortcuts
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Nigel2392/jsext/v2"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+type testUser struct {
+	ID   int    `table:"ID,sortable"`
+	Name string `table:"Name,sortable,filterable"`
+}
+
+var testUsers = []testUser{
+	{1, "Charlie"},
+	{2, "alice"},
+	{3, "Bob"},
+	{4, "Dave"},
+	{5, "Eve"},
+}
+
+func mountTable(t *testing.T, opts DataTableOptions[testUser]) (*DataTable[testUser], js.Value) {
+	t.Helper()
+	js.Reset()
+	var table = NewDataTable(testUsers, opts)
+	jsext.Body.AppendChild(table.Element().Element())
+	return table, table.Element().JSValue()
+}
+
+func cells(root js.Value, column int) []string {
+	var rows = root.Call("querySelectorAll", "tbody tr")
+	var values = make([]string, rows.Length())
+	for i := range values {
+		values[i] = rows.Index(i).Call("querySelectorAll", "td").Index(column).Get("textContent").String()
+	}
+	return values
+}
+
+func TestDataTableRender(t *testing.T) {
+	var _, root = mountTable(t, DataTableOptions[testUser]{})
+	var headers = root.Call("querySelectorAll", "thead th[data-sortable]")
+	if headers.Length() != 2 {
+		t.Fatalf("got %d sortable headers, want 2", headers.Length())
+	}
+	if got := cells(root, 1); !reflect.DeepEqual(got, []string{"Charlie", "alice", "Bob", "Dave", "Eve"}) {
+		t.Errorf("names = %v", got)
+	}
+}
+
+func TestDataTableSortOnClick(t *testing.T) {
+	var table, root = mountTable(t, DataTableOptions[testUser]{})
+	var name = root.Call("querySelector", "th[data-key=Name]")
+
+	js.Click(name)
+	if got := cells(root, 1); !reflect.DeepEqual(got, []string{"alice", "Bob", "Charlie", "Dave", "Eve"}) {
+		t.Errorf("ascending names = %v", got)
+	}
+
+	// The header is rendered again when sorting.
+	name = root.Call("querySelector", "th[data-key=Name]")
+	if got := name.Call("getAttribute", "aria-sort").String(); got != "ascending" {
+		t.Errorf("aria-sort = %q, want ascending", got)
+	}
+	js.Click(name)
+	if got := cells(root, 1); !reflect.DeepEqual(got, []string{"Eve", "Dave", "Charlie", "Bob", "alice"}) {
+		t.Errorf("descending names = %v", got)
+	}
+	if q := table.Query(); q.SortKey != "Name" || q.SortDirection != SortDescending {
+		t.Errorf("query = %+v, want Name descending", q)
+	}
+}
+
+func TestDataTablePagination(t *testing.T) {
+	var table, root = mountTable(t, DataTableOptions[testUser]{PageSize: 2})
+	if got := cells(root, 0); !reflect.DeepEqual(got, []string{"1", "2"}) {
+		t.Errorf("page 1 = %v", got)
+	}
+	var buttons = root.Call("querySelectorAll", ".jsext-datatable-pagination button")
+	if !buttons.Index(0).Get("disabled").Bool() {
+		t.Errorf("first page button is enabled on the first page")
+	}
+
+	js.Click(buttons.Index(3))
+	if table.Page() != 3 {
+		t.Errorf("Page() = %d, want 3", table.Page())
+	}
+	if got := cells(root, 0); !reflect.DeepEqual(got, []string{"5"}) {
+		t.Errorf("page 3 = %v", got)
+	}
+	var label = root.Call("querySelector", ".jsext-datatable-pagination span").Get("textContent").String()
+	if !strings.HasPrefix(label, "Page 3 of 3") {
+		t.Errorf("pagination label = %q", label)
+	}
+}
+
+func TestDataTableFilter(t *testing.T) {
+	var table, root = mountTable(t, DataTableOptions[testUser]{PageSize: 2})
+	table.SetPage(2)
+	table.SetFilter(" e ")
+	if table.Page() != 1 || table.Total() != 4 {
+		t.Errorf("Page() = %d, Total() = %d, want 1 and 4", table.Page(), table.Total())
+	}
+	table.SetPage(2)
+	if got := cells(root, 1); !reflect.DeepEqual(got, []string{"Dave", "Eve"}) {
+		t.Errorf("filtered names = %v", got)
+	}
+}
+
+func TestDataTableSelection(t *testing.T) {
+	var selected []testUser
+	var _, root = mountTable(t, DataTableOptions[testUser]{
+		Selectable:  true,
+		MultiSelect: true,
+		OnSelect:    func(rows []testUser) { selected = rows },
+	})
+
+	js.Click(root.Call("querySelectorAll", "tbody input[name=jsext-datatable-select]").Index(1))
+	if !reflect.DeepEqual(selected, []testUser{testUsers[1]}) {
+		t.Errorf("selected = %v, want [%v]", selected, testUsers[1])
+	}
+	if root.Call("querySelectorAll", "tbody tr[aria-selected]").Length() != 1 {
+		t.Errorf("selected row is not marked")
+	}
+
+	var all = root.Call("querySelector", "thead input[type=checkbox]")
+	if !all.Get("indeterminate").Bool() {
+		t.Errorf("select all is not indeterminate with one row selected")
+	}
+	js.Click(all)
+	if len(selected) != len(testUsers) {
+		t.Errorf("select all selected %d rows, want %d", len(selected), len(testUsers))
+	}
+}
+
+func TestDataTableServerTotal(t *testing.T) {
+	js.Reset()
+	var table = NewDataTable(testUsers[:2], DataTableOptions[testUser]{
+		PageSize: 2,
+		Server:   &DataTableServer[testUser]{URL: "/users", Total: 7},
+	})
+	if table.PageCount() != 4 {
+		t.Errorf("PageCount() = %d, want 4", table.PageCount())
+	}
+	var label = table.Element().JSValue().Call("querySelector", ".jsext-datatable-pagination span").Get("textContent").String()
+	if !strings.HasPrefix(label, "Page 1 of 4") {
+		t.Errorf("pagination label = %q", label)
+	}
+}
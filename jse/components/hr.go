@@ -2,6 +2,9 @@ package shortcuts
 
 import (
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 
 	"github.com/Nigel2392/jsext/v2/css"
 	"github.com/Nigel2392/jsext/v2/jse"
@@ -11,7 +14,8 @@ import (
 type HR struct {
 	// Width and height of the HR
 	Width, Height string
-	// Main colors to use.
+	// Main colors to use, hex colors or CSS variables.
+	// Defaults to the text and background colors of the theme.
 	BackCol, FadeCol string
 	// Margin top and bottom.
 	MarginTopBottom string
@@ -39,7 +43,7 @@ func (h *HR) setDefaults() {
 		h.Height = "1px"
 	}
 	if h.BackCol == "" {
-		h.BackCol = "#000000"
+		h.BackCol = css.Var(css.ColorText)
 	}
 	if h.BackColFormat == "" {
 		h.BackColFormat = "rgba(%d, %d, %d, 1)"
@@ -48,7 +52,7 @@ func (h *HR) setDefaults() {
 		h.FadeColFormat = "rgba(%d, %d, %d, 0.1)"
 	}
 	if h.FadeCol == "" {
-		h.FadeCol = "#ffffff"
+		h.FadeCol = css.Var(css.ColorBackground)
 	}
 	if h.MarginTopBottom == "" {
 		h.MarginTopBottom = "0"
@@ -69,8 +73,8 @@ func (h *HR) setDefaults() {
 
 func FancyHR(opts *HR) *jse.Element {
 	opts.setDefaults()
-	var mainColor = css.FormatRGBA(opts.BackCol, opts.BackColFormat)
-	var fadeColor = css.FormatRGBA(opts.FadeCol, opts.FadeColFormat)
+	var mainColor = hrColor(opts.BackCol, opts.BackColFormat)
+	var fadeColor = hrColor(opts.FadeCol, opts.FadeColFormat)
 	var hash = jsrand.XorBytes(opts.Width + opts.Height + opts.BackCol + opts.FadeCol + opts.MarginTopBottom + opts.FadeDir)
 	var hr = jse.NewElement("hr")
 	hr.ClassList("fancy-hr" + hash)
@@ -104,3 +108,28 @@ func FancyHR(opts *HR) *jse.Element {
 
 	return hr
 }
+
+// hrColor formats hex colors, CSS variables cannot be formatted,
+// so the alpha of the format is applied to them with color-mix().
+func hrColor(color, format string) string {
+	if strings.HasPrefix(color, "var(") {
+		if alpha, ok := formatAlpha(format); ok && alpha < 1 {
+			return fmt.Sprintf("color-mix(in srgb, %s %s%%, transparent)", color, strconv.FormatFloat(math.Round(alpha*1e4)/1e2, 'f', -1, 64))
+		}
+		return color
+	}
+	return css.FormatRGBA(color, format)
+}
+
+// formatAlpha returns the alpha of a format like "rgba(%d, %d, %d, 0.1)".
+func formatAlpha(format string) (float64, bool) {
+	var args = strings.Split(strings.TrimSuffix(strings.TrimSpace(format), ")"), ",")
+	if len(args) != 4 {
+		return 0, false
+	}
+	var alpha, err = strconv.ParseFloat(strings.TrimSpace(args[3]), 64)
+	if err != nil || alpha < 0 {
+		return 0, false
+	}
+	return alpha, true
+}
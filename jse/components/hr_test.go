@@ -0,0 +1,26 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package shortcuts
+
+import (
+	"testing"
+)
+
+func TestHRColor(t *testing.T) {
+	var tests = []struct {
+		color, format string
+		want          string
+	}{
+		{"#ff0000", "rgba(%d, %d, %d, 0.1)", "rgba(255, 0, 0, 0.1)"},
+		{"var(--color-background)", "rgba(%d, %d, %d, 0.1)", "color-mix(in srgb, var(--color-background) 10%, transparent)"},
+		{"var(--color-text)", "rgba(%d, %d, %d, 0.55)", "color-mix(in srgb, var(--color-text) 55%, transparent)"},
+		{"var(--color-text)", "rgba(%d, %d, %d, 1)", "var(--color-text)"},
+		{"var(--color-text)", "rgb(%d, %d, %d)", "var(--color-text)"},
+	}
+	for _, test := range tests {
+		if got := hrColor(test.color, test.format); got != test.want {
+			t.Errorf("hrColor(%q, %q) = %q, want %q", test.color, test.format, got, test.want)
+		}
+	}
+}
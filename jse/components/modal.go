@@ -3,10 +3,11 @@ package shortcuts
 import (
 	"strconv"
 	"strings"
-	"syscall/js"
 
 	"github.com/Nigel2392/jsext/v2"
+	"github.com/Nigel2392/jsext/v2/css"
 	"github.com/Nigel2392/jsext/v2/jse"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 var (
@@ -14,12 +15,12 @@ var (
 
 	DEFAULT_MODAL_CLASS_PREFIX = "jsext-"
 
-	DEFAULT_MODAL_FILL_COLOR      string  = "rgba(0,0,0,0.5)"
-	DEFAULT_MODAL_COLOR           string  = "#232323"
-	DEFAULT_MODAL_BG_COLOR        string  = "#fff"
+	DEFAULT_MODAL_FILL_COLOR      string  = css.Var(css.ColorBackdrop)
+	DEFAULT_MODAL_COLOR           string  = css.Var(css.ColorText)
+	DEFAULT_MODAL_BG_COLOR        string  = css.Var(css.ColorBackground)
 	DEFAULT_MODAL_BORDER_RADIUS   string  = "5px"
-	DEFAULT_MODAL_BLOCK_BORDER    string  = "1px solid #ccc;"
-	DEFAULT_MODAL_BORDER          string  = "1px solid #ccc"
+	DEFAULT_MODAL_BLOCK_BORDER    string  = "1px solid " + css.Var(css.ColorBorder) + ";"
+	DEFAULT_MODAL_BORDER          string  = "1px solid " + css.Var(css.ColorBorder)
 	DEFAULT_MODAL_WIDTH           string  = "50%"
 	DEFAULT_MODAL_HEIGHT          string  = "auto"
 	DEFAULT_MODAL_OVERFLOW        string  = "auto"
@@ -236,7 +237,7 @@ func CreateModal(opts ModalOptions) *Modal {
 		modal.AppendChild(opts.Footer)
 	}
 
-	css := strings.Join([]string{`.`, opts.ClassPrefix, `modal-container {
+	style := strings.Join([]string{`.`, opts.ClassPrefix, `modal-container {
 			position: fixed;
 			top: 0;
 			left: 0;
@@ -254,7 +255,7 @@ func CreateModal(opts ModalOptions) *Modal {
 			right: 10px;
 			width: 30px;
 			height: 30px;
-			background: #ff0000;
+			background: `, css.Var(css.ColorDanger), `;
 			border-radius: 50%;
 			display: flex;
 			justify-content: center;
@@ -264,14 +265,14 @@ func CreateModal(opts ModalOptions) *Modal {
 			transition: background 0.2s ease-in-out;
 		}
 		.`, opts.ClassPrefix, `close-btn:hover {
-			background: #910c0c;
+			background: `, css.Var(css.ColorDangerHover), `;
 		}
 		.`, opts.ClassPrefix, `close-btn::before {
 			position: absolute;
 			content: "";
 			width: 20px;
 			height: 2px;
-			background: #fff;
+			background: `, css.Var(css.ColorOnDanger), `;
 			transform: rotate(45deg);
 		}
 		.`, opts.ClassPrefix, `close-btn::after {
@@ -279,7 +280,7 @@ func CreateModal(opts ModalOptions) *Modal {
 			content: "";
 			width: 20px;
 			height: 2px;
-			background: #fff;
+			background: `, css.Var(css.ColorOnDanger), `;
 			transform: rotate(-45deg);
 		}
 		
@@ -311,6 +312,6 @@ func CreateModal(opts ModalOptions) *Modal {
 			border: none;
 		}`}, "")
 
-	modal.StyleBlock(css)
+	modal.StyleBlock(style)
 	return (*Modal)(modal_container)
 }
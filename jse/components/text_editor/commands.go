@@ -0,0 +1,269 @@
+package texteditor
+
+import (
+	"time"
+)
+
+// Transaction is a set of changes to a copy of the document.
+//
+// The changes are applied to the editor when the command returns true.
+type Transaction struct {
+	Doc       *Document
+	Selection Range
+	changed   bool
+}
+
+func newTransaction(doc *Document, sel Range) *Transaction {
+	return &Transaction{
+		Doc:       doc.Clone(),
+		Selection: Range{Start: doc.Clamp(sel.Start), End: doc.Clamp(sel.End)},
+	}
+}
+
+// Changed reports whether the document was changed.
+func (tr *Transaction) Changed() bool {
+	return tr.changed
+}
+
+// Delete removes the selected content.
+func (tr *Transaction) Delete() *Transaction {
+	if tr.Selection.Collapsed() {
+		return tr
+	}
+	tr.Selection = Collapsed(tr.Doc.deleteRange(tr.Selection))
+	tr.changed = true
+	return tr
+}
+
+// InsertText replaces the selection with the text, using the marks of the text before the selection.
+func (tr *Transaction) InsertText(text string) *Transaction {
+	var marks, href = tr.Doc.MarksAt(tr.Selection)
+	tr.Delete()
+	tr.Selection = Collapsed(tr.Doc.insertInlines(tr.Selection.Start, Inline{Text: text, Marks: marks, Href: href}))
+	tr.changed = true
+	return tr
+}
+
+// InsertDocument replaces the selection with the blocks of the document.
+func (tr *Transaction) InsertDocument(doc *Document) *Transaction {
+	tr.Delete()
+	tr.Selection = Collapsed(tr.Doc.insertDocument(tr.Selection.Start, doc))
+	tr.Doc.normalize()
+	tr.changed = true
+	return tr
+}
+
+// SplitBlock replaces the selection with a new block.
+func (tr *Transaction) SplitBlock() *Transaction {
+	tr.Delete()
+	tr.Selection = Collapsed(tr.Doc.splitBlock(tr.Selection.Start))
+	tr.changed = true
+	return tr
+}
+
+// HasMark reports whether the whole selection has the mark.
+func (tr *Transaction) HasMark(m Mark) bool {
+	var marks, _ = tr.Doc.MarksAt(tr.Selection)
+	return marks&m == m
+}
+
+// SetMark adds or removes the mark from the selected text.
+func (tr *Transaction) SetMark(m Mark, on bool) *Transaction {
+	if tr.Selection.Collapsed() {
+		return tr
+	}
+	tr.Doc.mapInlines(tr.Selection, func(i *Inline) {
+		if on {
+			i.Marks |= m
+		} else {
+			i.Marks &^= m
+		}
+	})
+	tr.changed = true
+	return tr
+}
+
+// SetLink links the selected text to href, an empty href removes the link.
+func (tr *Transaction) SetLink(href string) *Transaction {
+	if tr.Selection.Collapsed() {
+		return tr
+	}
+	href = safeHref(href)
+	tr.Doc.mapInlines(tr.Selection, func(i *Inline) {
+		i.Href = href
+	})
+	tr.changed = true
+	return tr
+}
+
+// SetBlockType changes the type of all selected blocks.
+func (tr *Transaction) SetBlockType(typ string) *Transaction {
+	tr.Doc.eachBlock(tr.Selection, func(b *Block) {
+		if b.Type != BlockRule {
+			b.Type = typ
+		}
+	})
+	tr.changed = true
+	return tr
+}
+
+// SetAlign changes the alignment of all selected blocks.
+func (tr *Transaction) SetAlign(align string) *Transaction {
+	align = validAlign(align)
+	tr.Doc.eachBlock(tr.Selection, func(b *Block) {
+		b.Align = align
+	})
+	tr.changed = true
+	return tr
+}
+
+// BlockType returns the type of the block at the start of the selection.
+func (tr *Transaction) BlockType() string {
+	return tr.Doc.Blocks[tr.Selection.Ordered().Start.Block].Type
+}
+
+// Command changes the document through a transaction.
+//
+// It returns false if the command could not be applied.
+type Command func(tr *Transaction) bool
+
+// ToggleMark adds the mark to the selection, or removes it if the whole selection already has it.
+func ToggleMark(m Mark) Command {
+	return func(tr *Transaction) bool {
+		if tr.Selection.Collapsed() {
+			return false
+		}
+		tr.SetMark(m, !tr.HasMark(m))
+		return true
+	}
+}
+
+// ToggleBlockType sets the type of the selected blocks, or changes them back to paragraphs.
+func ToggleBlockType(typ string) Command {
+	return func(tr *Transaction) bool {
+		if tr.BlockType() == typ {
+			tr.SetBlockType(BlockParagraph)
+		} else {
+			tr.SetBlockType(typ)
+		}
+		return true
+	}
+}
+
+func SetAlign(align string) Command {
+	return func(tr *Transaction) bool {
+		tr.SetAlign(align)
+		return true
+	}
+}
+
+// SetLink links the selected text, an empty href removes the link.
+func SetLink(href string) Command {
+	return func(tr *Transaction) bool {
+		if tr.Selection.Collapsed() {
+			return false
+		}
+		tr.SetLink(href)
+		return true
+	}
+}
+
+func InsertText(text string) Command {
+	return func(tr *Transaction) bool {
+		tr.InsertText(text)
+		return true
+	}
+}
+
+func InsertDocument(doc *Document) Command {
+	return func(tr *Transaction) bool {
+		tr.InsertDocument(doc)
+		return true
+	}
+}
+
+// InsertRule inserts a horizontal rule after the selected block.
+func InsertRule() Command {
+	return func(tr *Transaction) bool {
+		tr.InsertDocument(NewDocument(NewBlock(BlockRule), NewBlock(BlockParagraph)))
+		return true
+	}
+}
+
+type historyEntry struct {
+	doc *Document
+	sel Range
+}
+
+// History keeps track of previous versions of the document.
+type History struct {
+	// Limit is the maximum number of undo steps.
+	Limit int
+	// Changes made within GroupDelay of each other while typing are undone at once.
+	GroupDelay time.Duration
+
+	undo     []historyEntry
+	redo     []historyEntry
+	lastPush time.Time
+}
+
+func NewHistory(limit int) *History {
+	return &History{
+		Limit:      limit,
+		GroupDelay: 500 * time.Millisecond,
+	}
+}
+
+// push records the document before a change.
+//
+// If group is true and the previous change was recent, they are undone together.
+func (h *History) push(doc *Document, sel Range, group bool) {
+	var now = time.Now()
+	var grouped = group && len(h.undo) > 0 && now.Sub(h.lastPush) < h.GroupDelay
+	h.lastPush = now
+	h.redo = h.redo[:0]
+	if grouped {
+		return
+	}
+	h.undo = append(h.undo, historyEntry{doc: doc.Clone(), sel: sel})
+	if h.Limit > 0 && len(h.undo) > h.Limit {
+		h.undo = h.undo[len(h.undo)-h.Limit:]
+	}
+}
+
+func (h *History) CanUndo() bool {
+	return len(h.undo) > 0
+}
+
+func (h *History) CanRedo() bool {
+	return len(h.redo) > 0
+}
+
+func (h *History) Clear() {
+	h.undo = h.undo[:0]
+	h.redo = h.redo[:0]
+}
+
+// undoTo returns the previous version of the document, and saves the current one for redo.
+func (h *History) undoTo(doc *Document, sel Range) (historyEntry, bool) {
+	if len(h.undo) == 0 {
+		return historyEntry{}, false
+	}
+	var e = h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	h.redo = append(h.redo, historyEntry{doc: doc.Clone(), sel: sel})
+	h.lastPush = time.Time{}
+	return e, true
+}
+
+// redoTo returns the next version of the document, and saves the current one for undo.
+func (h *History) redoTo(doc *Document, sel Range) (historyEntry, bool) {
+	if len(h.redo) == 0 {
+		return historyEntry{}, false
+	}
+	var e = h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+	h.undo = append(h.undo, historyEntry{doc: doc.Clone(), sel: sel})
+	h.lastPush = time.Time{}
+	return e, true
+}
@@ -0,0 +1,448 @@
+package texteditor
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Mark is a bitset of inline formatting.
+type Mark uint8
+
+const (
+	MarkBold Mark = 1 << iota
+	MarkItalic
+	MarkUnderline
+	MarkStrike
+	MarkCode
+)
+
+// Block types which are always available.
+//
+// More can be registered with Schema.Register or Config.AddLintElement.
+const (
+	BlockParagraph   = "p"
+	BlockHeading1    = "h1"
+	BlockHeading2    = "h2"
+	BlockHeading3    = "h3"
+	BlockHeading4    = "h4"
+	BlockHeading5    = "h5"
+	BlockHeading6    = "h6"
+	BlockQuote       = "blockquote"
+	BlockCode        = "pre"
+	BlockBulletList  = "ul"
+	BlockOrderedList = "ol"
+	BlockRule        = "hr"
+)
+
+// Inline is a run of text with the same formatting.
+//
+// Hard line breaks are stored as "\n".
+type Inline struct {
+	Text  string
+	Marks Mark
+	Href  string
+}
+
+func (i Inline) Has(m Mark) bool {
+	return i.Marks&m == m
+}
+
+func (i Inline) Len() int {
+	return utf8.RuneCountInString(i.Text)
+}
+
+func (i Inline) sameFormat(o Inline) bool {
+	return i.Marks == o.Marks && i.Href == o.Href
+}
+
+// Block is a single block of the document, such as a paragraph or a list item.
+//
+// Consecutive list items of the same type form a single list.
+type Block struct {
+	Type    string
+	Align   string
+	Inlines []Inline
+}
+
+func NewBlock(typ string, inlines ...Inline) *Block {
+	return &Block{
+		Type:    typ,
+		Inlines: inlines,
+	}
+}
+
+// Text returns the plain text of the block.
+func (b *Block) Text() string {
+	var sb strings.Builder
+	for _, i := range b.Inlines {
+		sb.WriteString(i.Text)
+	}
+	return sb.String()
+}
+
+// Len returns the length of the block in runes.
+func (b *Block) Len() int {
+	var n int
+	for _, i := range b.Inlines {
+		n += i.Len()
+	}
+	return n
+}
+
+func (b *Block) Clone() *Block {
+	var c = *b
+	c.Inlines = append([]Inline(nil), b.Inlines...)
+	return &c
+}
+
+// split makes sure an inline starts at offset, and returns the index of that inline.
+func (b *Block) split(offset int) int {
+	var pos int
+	for idx, i := range b.Inlines {
+		var l = i.Len()
+		if offset == pos {
+			return idx
+		}
+		if offset < pos+l {
+			var at = runeIndex(i.Text, offset-pos)
+			var left, right = i, i
+			left.Text, right.Text = i.Text[:at], i.Text[at:]
+			b.Inlines = append(b.Inlines[:idx], append([]Inline{left, right}, b.Inlines[idx+1:]...)...)
+			return idx + 1
+		}
+		pos += l
+	}
+	return len(b.Inlines)
+}
+
+// cut removes and returns the inlines from offset to the end of the block.
+func (b *Block) cut(offset int) []Inline {
+	var idx = b.split(offset)
+	var rest = append([]Inline(nil), b.Inlines[idx:]...)
+	b.Inlines = b.Inlines[:idx]
+	return rest
+}
+
+func (b *Block) normalize() {
+	var inlines = b.Inlines[:0]
+	for _, i := range b.Inlines {
+		if i.Text == "" {
+			continue
+		}
+		if n := len(inlines); n > 0 && inlines[n-1].sameFormat(i) {
+			inlines[n-1].Text += i.Text
+			continue
+		}
+		inlines = append(inlines, i)
+	}
+	b.Inlines = inlines
+	if b.Type == "" {
+		b.Type = BlockParagraph
+	}
+}
+
+// Position is a position in the document.
+//
+// Offset is counted in runes from the start of the block.
+type Position struct {
+	Block  int
+	Offset int
+}
+
+// Compare returns -1, 0 or 1 if p is before, equal to or after o.
+func (p Position) Compare(o Position) int {
+	switch {
+	case p.Block < o.Block, p.Block == o.Block && p.Offset < o.Offset:
+		return -1
+	case p == o:
+		return 0
+	}
+	return 1
+}
+
+// Range is a range of the document.
+//
+// Start may be after End if the selection was made backwards.
+type Range struct {
+	Start Position
+	End   Position
+}
+
+func Collapsed(p Position) Range {
+	return Range{Start: p, End: p}
+}
+
+func (r Range) Collapsed() bool {
+	return r.Start == r.End
+}
+
+// Ordered returns the range with Start before End.
+func (r Range) Ordered() Range {
+	if r.Start.Compare(r.End) > 0 {
+		return Range{Start: r.End, End: r.Start}
+	}
+	return r
+}
+
+type Document struct {
+	Blocks []*Block
+}
+
+func NewDocument(blocks ...*Block) *Document {
+	var d = &Document{Blocks: blocks}
+	d.normalize()
+	return d
+}
+
+func (d *Document) Clone() *Document {
+	var c = &Document{Blocks: make([]*Block, len(d.Blocks))}
+	for i, b := range d.Blocks {
+		c.Blocks[i] = b.Clone()
+	}
+	return c
+}
+
+// Text returns the plain text of the document, blocks are separated by newlines.
+func (d *Document) Text() string {
+	var texts = make([]string, len(d.Blocks))
+	for i, b := range d.Blocks {
+		texts[i] = b.Text()
+	}
+	return strings.Join(texts, "\n")
+}
+
+func (d *Document) Start() Position {
+	return Position{}
+}
+
+func (d *Document) End() Position {
+	var last = len(d.Blocks) - 1
+	return Position{Block: last, Offset: d.Blocks[last].Len()}
+}
+
+// Clamp returns the closest valid position to p.
+func (d *Document) Clamp(p Position) Position {
+	if p.Block < 0 {
+		return d.Start()
+	}
+	if p.Block >= len(d.Blocks) {
+		return d.End()
+	}
+	if p.Offset < 0 {
+		p.Offset = 0
+	}
+	if l := d.Blocks[p.Block].Len(); p.Offset > l {
+		p.Offset = l
+	}
+	return p
+}
+
+// Slice returns a copy of the part of the document within the range.
+func (d *Document) Slice(r Range) *Document {
+	r = r.Ordered()
+	var c = &Document{}
+	for i := r.Start.Block; i <= r.End.Block && i < len(d.Blocks); i++ {
+		var b = d.Blocks[i].Clone()
+		if i == r.End.Block {
+			b.cut(r.End.Offset)
+		}
+		if i == r.Start.Block {
+			b.Inlines = b.cut(r.Start.Offset)
+		}
+		c.Blocks = append(c.Blocks, b)
+	}
+	c.normalize()
+	return c
+}
+
+// MarksAt returns the marks which apply to the whole range.
+//
+// For a collapsed range the marks of the text before the position are returned.
+func (d *Document) MarksAt(r Range) (marks Mark, href string) {
+	r = r.Ordered()
+	if r.Collapsed() {
+		var b = d.Blocks[r.Start.Block]
+		var pos int
+		for _, i := range b.Inlines {
+			pos += i.Len()
+			if pos >= r.Start.Offset {
+				return i.Marks, i.Href
+			}
+		}
+		return 0, ""
+	}
+	var first = true
+	d.each(r, func(i Inline) {
+		if first {
+			marks, href = i.Marks, i.Href
+			first = false
+			return
+		}
+		marks &= i.Marks
+		if href != i.Href {
+			href = ""
+		}
+	})
+	return marks, href
+}
+
+// each calls fn for each part of an inline within the range.
+func (d *Document) each(r Range, fn func(i Inline)) {
+	var s = d.Slice(r)
+	for _, b := range s.Blocks {
+		for _, i := range b.Inlines {
+			fn(i)
+		}
+	}
+}
+
+// normalize merges adjacent inlines and makes sure the document has at least one block.
+func (d *Document) normalize() {
+	for _, b := range d.Blocks {
+		b.normalize()
+	}
+	if len(d.Blocks) == 0 {
+		d.Blocks = []*Block{NewBlock(BlockParagraph)}
+	}
+}
+
+// trimSpace removes leading and trailing whitespace from each block, except code blocks.
+func (d *Document) trimSpace() {
+	var blocks = d.Blocks[:0]
+	for _, b := range d.Blocks {
+		if b.Type != BlockCode && len(b.Inlines) > 0 {
+			b.Inlines[0].Text = strings.TrimLeft(b.Inlines[0].Text, " ")
+			var last = len(b.Inlines) - 1
+			b.Inlines[last].Text = strings.TrimRight(b.Inlines[last].Text, " ")
+			b.normalize()
+		}
+		if len(b.Inlines) == 0 && b.Type == BlockParagraph {
+			continue
+		}
+		blocks = append(blocks, b)
+	}
+	d.Blocks = blocks
+	d.normalize()
+}
+
+// deleteRange removes the content within the range, joining the first and last block.
+func (d *Document) deleteRange(r Range) Position {
+	r = r.Ordered()
+	if r.Collapsed() {
+		return r.Start
+	}
+	var first = d.Blocks[r.Start.Block]
+	var last = d.Blocks[r.End.Block]
+	var rest = last.cut(r.End.Offset)
+	first.cut(r.Start.Offset)
+	first.Inlines = append(first.Inlines, rest...)
+	first.normalize()
+	d.Blocks = append(d.Blocks[:r.Start.Block+1], d.Blocks[r.End.Block+1:]...)
+	return r.Start
+}
+
+// insertInlines inserts the inlines at p, and returns the position after them.
+func (d *Document) insertInlines(p Position, inlines ...Inline) Position {
+	var b = d.Blocks[p.Block]
+	var idx = b.split(p.Offset)
+	var n int
+	for _, i := range inlines {
+		n += i.Len()
+	}
+	b.Inlines = append(b.Inlines[:idx], append(append([]Inline(nil), inlines...), b.Inlines[idx:]...)...)
+	b.normalize()
+	return Position{Block: p.Block, Offset: p.Offset + n}
+}
+
+// splitBlock splits the block at p, the new block has the same type and alignment.
+func (d *Document) splitBlock(p Position) Position {
+	var b = d.Blocks[p.Block]
+	var nb = &Block{Type: b.Type, Align: b.Align, Inlines: b.cut(p.Offset)}
+	if isHeading(b.Type) && len(nb.Inlines) == 0 {
+		nb.Type = BlockParagraph
+	}
+	d.Blocks = append(d.Blocks[:p.Block+1], append([]*Block{nb}, d.Blocks[p.Block+1:]...)...)
+	return Position{Block: p.Block + 1}
+}
+
+// insertDocument inserts the blocks of other at p, and returns the position after them.
+//
+// The first and last block are merged with the text before and after p.
+func (d *Document) insertDocument(p Position, other *Document) Position {
+	var blocks = other.Clone().Blocks
+	if len(blocks) == 0 {
+		return p
+	}
+	if len(blocks) == 1 && blocks[0].Type != BlockRule {
+		return d.insertInlines(p, blocks[0].Inlines...)
+	}
+	var end = d.splitBlock(p)
+	var before = d.Blocks[p.Block]
+	var after = d.Blocks[end.Block]
+	var first, last = blocks[0], blocks[len(blocks)-1]
+	if first.Type != BlockRule {
+		if len(before.Inlines) == 0 {
+			before.Type, before.Align = first.Type, first.Align
+		}
+		before.Inlines = append(before.Inlines, first.Inlines...)
+		before.normalize()
+		blocks = blocks[1:]
+	}
+	if len(blocks) > 0 && last.Type != BlockRule {
+		if len(after.Inlines) == 0 {
+			after.Type, after.Align = last.Type, last.Align
+		}
+		end.Offset = last.Len()
+		after.Inlines = append(last.Inlines, after.Inlines...)
+		after.normalize()
+		blocks = blocks[:len(blocks)-1]
+	}
+	d.Blocks = append(d.Blocks[:end.Block], append(blocks, d.Blocks[end.Block:]...)...)
+	end.Block += len(blocks)
+	return end
+}
+
+// mapInlines calls fn for each inline within the range, splitting inlines at the edges of the range.
+func (d *Document) mapInlines(r Range, fn func(i *Inline)) {
+	r = r.Ordered()
+	for bi := r.Start.Block; bi <= r.End.Block; bi++ {
+		var b = d.Blocks[bi]
+		var start, end = 0, len(b.Inlines)
+		if bi == r.End.Block {
+			end = b.split(r.End.Offset)
+		}
+		if bi == r.Start.Block {
+			start = b.split(r.Start.Offset)
+			if bi == r.End.Block {
+				end = b.split(r.End.Offset)
+			}
+		}
+		for i := start; i < end; i++ {
+			fn(&b.Inlines[i])
+		}
+		b.normalize()
+	}
+}
+
+func (d *Document) eachBlock(r Range, fn func(b *Block)) {
+	r = r.Ordered()
+	for bi := r.Start.Block; bi <= r.End.Block; bi++ {
+		fn(d.Blocks[bi])
+	}
+}
+
+func isHeading(typ string) bool {
+	return len(typ) == 2 && typ[0] == 'h' && typ[1] >= '1' && typ[1] <= '6'
+}
+
+// runeIndex returns the byte index of the n-th rune in s.
+func runeIndex(s string, n int) int {
+	var i int
+	for idx := range s {
+		if i == n {
+			return idx
+		}
+		i++
+	}
+	return len(s)
+}
@@ -0,0 +1,85 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package texteditor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	var doc = NewDocument(NewBlock("",
+		Inline{Text: "a"},
+		Inline{Text: ""},
+		Inline{Text: "b"},
+		Inline{Text: "c", Marks: MarkBold},
+	))
+	var want = []Inline{{Text: "ab"}, {Text: "c", Marks: MarkBold}}
+	if !reflect.DeepEqual(doc.Blocks[0].Inlines, want) {
+		t.Fatalf("expected %v, got %v", want, doc.Blocks[0].Inlines)
+	}
+	if doc.Blocks[0].Type != BlockParagraph {
+		t.Fatalf("expected a paragraph, got %q", doc.Blocks[0].Type)
+	}
+}
+
+func TestSlice(t *testing.T) {
+	var doc = NewDocument(
+		NewBlock(BlockParagraph, Inline{Text: "héllo"}, Inline{Text: " world", Marks: MarkBold}),
+		NewBlock(BlockHeading1, Inline{Text: "title"}),
+	)
+	var s = doc.Slice(Range{Start: Position{0, 3}, End: Position{1, 2}})
+	if s.Text() != "lo world\nti" {
+		t.Fatalf("expected %q, got %q", "lo world\nti", s.Text())
+	}
+	if s.Blocks[1].Type != BlockHeading1 {
+		t.Fatalf("expected a heading, got %q", s.Blocks[1].Type)
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	var doc = NewDocument(
+		NewBlock(BlockParagraph, Inline{Text: "hello world"}),
+		NewBlock(BlockParagraph, Inline{Text: "second"}),
+	)
+
+	var tr = newTransaction(doc, Range{Start: Position{0, 6}, End: Position{0, 11}})
+	tr.SetMark(MarkBold, true)
+	if !tr.HasMark(MarkBold) {
+		t.Fatal("expected the selection to be bold")
+	}
+	if doc.Blocks[0].Inlines[0].Marks != 0 {
+		t.Fatal("the transaction changed the original document")
+	}
+
+	tr.Selection = Range{Start: Position{0, 5}, End: Position{1, 0}}
+	tr.Delete()
+	if got := tr.Doc.Text(); got != "hellosecond" {
+		t.Fatalf("expected %q, got %q", "hellosecond", got)
+	}
+	if tr.Selection != Collapsed(Position{0, 5}) {
+		t.Fatalf("expected a collapsed selection at 0:5, got %v", tr.Selection)
+	}
+
+	tr.SplitBlock().InsertText("new ")
+	if got := tr.Doc.Text(); got != "hello\nnew second" {
+		t.Fatalf("expected %q, got %q", "hello\nnew second", got)
+	}
+	if !tr.Changed() {
+		t.Fatal("expected the transaction to be changed")
+	}
+}
+
+func TestSetLink(t *testing.T) {
+	var doc = NewDocument(NewBlock(BlockParagraph, Inline{Text: "click here"}))
+	var tr = newTransaction(doc, Range{Start: Position{0, 6}, End: Position{0, 10}})
+	tr.SetLink("javascript:alert(1)")
+	if _, href := tr.Doc.MarksAt(tr.Selection); href != "" {
+		t.Fatalf("expected an unsafe link to be removed, got %q", href)
+	}
+	tr.SetLink("https://example.com")
+	if _, href := tr.Doc.MarksAt(tr.Selection); href != "https://example.com" {
+		t.Fatalf("expected the link to be set, got %q", href)
+	}
+}
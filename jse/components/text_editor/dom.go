@@ -0,0 +1,314 @@
+package texteditor
+
+import (
+	"strings"
+
+	"github.com/Nigel2392/jsext/v2"
+	"github.com/Nigel2392/jsext/v2/jse"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+// jsNode is a node of the live DOM.
+type jsNode struct {
+	v js.Value
+}
+
+func (n jsNode) isText() bool {
+	return n.v.Get("nodeType").Int() == 3
+}
+
+func (n jsNode) text() string {
+	if !n.isText() {
+		return n.v.Get("textContent").String()
+	}
+	return n.v.Get("data").String()
+}
+
+func (n jsNode) tag() string {
+	if n.v.Get("nodeType").Int() != 1 {
+		return ""
+	}
+	return strings.ToLower(n.v.Get("tagName").String())
+}
+
+func (n jsNode) attr(name string) string {
+	var v = n.v.Call("getAttribute", name)
+	if v.IsNull() || v.IsUndefined() {
+		return ""
+	}
+	return v.String()
+}
+
+func (n jsNode) children() []node {
+	var childNodes = n.v.Get("childNodes")
+	var length = childNodes.Length()
+	var nodes = make([]node, 0, length)
+	for i := 0; i < length; i++ {
+		var c = childNodes.Index(i)
+		switch c.Get("nodeType").Int() {
+		case 1, 3:
+			nodes = append(nodes, jsNode{c})
+		}
+	}
+	return nodes
+}
+
+func contains(parent, child js.Value) bool {
+	return parent.Call("contains", child).Bool()
+}
+
+func indexOf(n js.Value) int {
+	var siblings = n.Get("parentNode").Get("childNodes")
+	for i := 0; i < siblings.Length(); i++ {
+		if siblings.Index(i).Equal(n) {
+			return i
+		}
+	}
+	return 0
+}
+
+// sync reads the document from the DOM of the editor.
+func (e *Editor) sync() {
+	var r = newReader(e.Conf.Schema)
+	r.readNodes(jsNode{e.Editor.JSValue()}.children(), "")
+	e.doc = r.document()
+	e.refs = r.refs
+	e.sources = r.sources
+}
+
+// render renders the document into the editor.
+func (e *Editor) render() {
+	e.Editor.InnerHTML(e.Conf.Schema.renderHTML(e.doc, true))
+	e.sync()
+}
+
+// Selection returns the current selection within the document.
+//
+// If the editor does not have focus, the last known selection is returned.
+func (e *Editor) Selection() Range {
+	var sel = jsext.Window.Call("getSelection").Value()
+	if sel.IsNull() || sel.Get("rangeCount").Int() == 0 {
+		return e.lastSelection()
+	}
+	var anchor, focus = sel.Get("anchorNode"), sel.Get("focusNode")
+	var root = e.Editor.JSValue()
+	if anchor.IsNull() || focus.IsNull() || !contains(root, anchor) || !contains(root, focus) {
+		return e.lastSelection()
+	}
+	e.selection = Range{
+		Start: e.domToPosition(anchor, sel.Get("anchorOffset").Int()),
+		End:   e.domToPosition(focus, sel.Get("focusOffset").Int()),
+	}
+	return e.selection
+}
+
+func (e *Editor) lastSelection() Range {
+	return Range{Start: e.doc.Clamp(e.selection.Start), End: e.doc.Clamp(e.selection.End)}
+}
+
+// SetSelection selects the range in the editor.
+func (e *Editor) SetSelection(r Range) {
+	r = Range{Start: e.doc.Clamp(r.Start), End: e.doc.Clamp(r.End)}
+	e.selection = r
+	var anchor, anchorOffset = e.positionToDOM(r.Start)
+	var focus, focusOffset = e.positionToDOM(r.End)
+	jsext.Window.Call("getSelection").Call("setBaseAndExtent", anchor, anchorOffset, focus, focusOffset)
+}
+
+func (e *Editor) domToPosition(n js.Value, offset int) Position {
+	for _, ref := range e.refs {
+		if ref.node.(jsNode).v.Equal(n) {
+			return e.doc.Clamp(Position{Block: ref.block, Offset: ref.modelOffset(offset)})
+		}
+	}
+
+	if n.Get("nodeType").Int() == 1 {
+		var childNodes = n.Get("childNodes")
+		if offset < childNodes.Length() {
+			var child = childNodes.Index(offset)
+			for _, ref := range e.refs {
+				if contains(child, ref.node.(jsNode).v) {
+					return e.doc.Clamp(Position{Block: ref.block, Offset: ref.offset})
+				}
+			}
+			for i, src := range e.sources {
+				if contains(child, src[0].(jsNode).v) {
+					return e.doc.Clamp(Position{Block: i})
+				}
+			}
+		} else {
+			for i := len(e.refs) - 1; i >= 0; i-- {
+				var ref = e.refs[i]
+				if contains(n, ref.node.(jsNode).v) {
+					return e.doc.Clamp(Position{Block: ref.block, Offset: ref.offset + ref.length})
+				}
+			}
+		}
+	}
+
+	for i, src := range e.sources {
+		if contains(src[0].(jsNode).v, n) {
+			return e.doc.Clamp(Position{Block: i, Offset: e.doc.Blocks[i].Len()})
+		}
+	}
+	return e.doc.End()
+}
+
+func (e *Editor) positionToDOM(p Position) (js.Value, int) {
+	var found *textRef
+	for i := range e.refs {
+		var ref = &e.refs[i]
+		if ref.block != p.Block || p.Offset < ref.offset || p.Offset > ref.offset+ref.length {
+			continue
+		}
+		if found == nil || found.br && !ref.br {
+			found = ref
+		}
+	}
+	switch {
+	case found != nil && found.br:
+		var br = found.node.(jsNode).v
+		var idx = indexOf(br)
+		if p.Offset > found.offset {
+			idx++
+		}
+		return br.Get("parentNode"), idx
+	case found != nil:
+		return found.node.(jsNode).v, found.domOffset(p.Offset)
+	case p.Block < len(e.sources):
+		var src = e.sources[p.Block][0].(jsNode).v
+		if src.Get("nodeType").Int() == 1 && src.Get("tagName").String() != "HR" {
+			return src, 0
+		}
+		return src.Get("parentNode"), indexOf(src)
+	}
+	return e.Editor.JSValue(), 0
+}
+
+// Dispatch runs the command on the current selection, and applies the changes.
+func (e *Editor) Dispatch(cmd Command) bool {
+	var sel = e.Selection()
+	var tr = newTransaction(e.doc, sel)
+	if !cmd(tr) {
+		return false
+	}
+	if tr.changed {
+		e.history.push(e.doc, sel, false)
+		e.doc = tr.Doc
+		e.render()
+	}
+	e.SetSelection(tr.Selection)
+	e.changed()
+	return true
+}
+
+// Undo reverts the last change.
+func (e *Editor) Undo() bool {
+	var entry, ok = e.history.undoTo(e.doc, e.Selection())
+	if !ok {
+		return false
+	}
+	e.doc = entry.doc
+	e.render()
+	e.SetSelection(entry.sel)
+	e.changed()
+	return true
+}
+
+// Redo re-applies the last undone change.
+func (e *Editor) Redo() bool {
+	var entry, ok = e.history.redoTo(e.doc, e.Selection())
+	if !ok {
+		return false
+	}
+	e.doc = entry.doc
+	e.render()
+	e.SetSelection(entry.sel)
+	e.changed()
+	return true
+}
+
+func (e *Editor) changed() {
+	if e.Conf.OnChange != nil {
+		e.Conf.OnChange(e)
+	}
+}
+
+// listen keeps the document in sync with changes the user makes in the browser.
+func (e *Editor) listen() {
+	var before historyEntry
+	e.Editor.AddEventListener("beforeinput", func(_ *jse.Element, event jsext.Event) {
+		switch event.Get("inputType").String() {
+		case "historyUndo":
+			event.PreventDefault()
+			e.Undo()
+			return
+		case "historyRedo":
+			event.PreventDefault()
+			e.Redo()
+			return
+		}
+		before = historyEntry{doc: e.doc, sel: e.Selection()}
+	})
+	e.Editor.AddEventListener("input", func(_ *jse.Element, _ jsext.Event) {
+		if before.doc == nil {
+			before = historyEntry{doc: e.doc, sel: e.selection}
+		}
+		e.sync()
+		e.history.push(before.doc, before.sel, true)
+		before = historyEntry{}
+		e.Selection()
+		e.changed()
+	})
+	e.Editor.OnKeyDown(func(_ *jse.Element, event jsext.Event) {
+		if !event.Get("ctrlKey").Bool() && !event.Get("metaKey").Bool() {
+			return
+		}
+		switch strings.ToLower(event.Get("key").String()) {
+		case "z":
+			event.PreventDefault()
+			if event.Get("shiftKey").Bool() {
+				e.Redo()
+			} else {
+				e.Undo()
+			}
+		case "y":
+			event.PreventDefault()
+			e.Redo()
+		}
+	})
+	e.Editor.AddEventListener("paste", func(_ *jse.Element, event jsext.Event) {
+		var data = event.Get("clipboardData")
+		if data.IsUndefined() || data.IsNull() {
+			return
+		}
+		event.PreventDefault()
+		var doc *Document
+		if src := data.Call("getData", "text/html").String(); src != "" {
+			var err error
+			if doc, err = e.Conf.Schema.ParseHTML(src); err != nil {
+				return
+			}
+		} else {
+			doc = plainText(data.Call("getData", "text/plain").String())
+		}
+		e.Dispatch(InsertDocument(doc))
+	})
+	e.Editor.AddEventListener("keyup", func(_ *jse.Element, _ jsext.Event) {
+		e.Selection()
+	})
+	e.Editor.AddEventListener("mouseup", func(_ *jse.Element, _ jsext.Event) {
+		e.Selection()
+	})
+}
+
+// plainText converts text into a document with a paragraph for each line.
+func plainText(text string) *Document {
+	var lines = strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	var doc = &Document{}
+	for _, line := range lines {
+		doc.Blocks = append(doc.Blocks, NewBlock(BlockParagraph, Inline{Text: line}))
+	}
+	doc.normalize()
+	return doc
+}
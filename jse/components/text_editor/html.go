@@ -0,0 +1,552 @@
+package texteditor
+
+import (
+	"html"
+	"strings"
+	"unicode/utf16"
+
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// node abstracts over parsed HTML and the live DOM, so that both are read the same way.
+type node interface {
+	isText() bool
+	text() string
+	tag() string
+	attr(name string) string
+	children() []node
+}
+
+type htmlNode struct {
+	n *xhtml.Node
+}
+
+func (h htmlNode) isText() bool {
+	return h.n.Type == xhtml.TextNode
+}
+
+func (h htmlNode) text() string {
+	return h.n.Data
+}
+
+func (h htmlNode) tag() string {
+	if h.n.Type != xhtml.ElementNode {
+		return ""
+	}
+	return strings.ToLower(h.n.Data)
+}
+
+func (h htmlNode) attr(name string) string {
+	for _, a := range h.n.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func (h htmlNode) children() []node {
+	var nodes []node
+	for c := h.n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == xhtml.TextNode || c.Type == xhtml.ElementNode {
+			nodes = append(nodes, htmlNode{c})
+		}
+	}
+	return nodes
+}
+
+// Elements which are removed together with their content.
+var dropTags = map[string]bool{
+	"script": true, "style": true, "template": true, "iframe": true,
+	"object": true, "embed": true, "noscript": true, "head": true,
+	"title": true, "meta": true, "link": true, "svg": true,
+	"math": true, "canvas": true, "audio": true, "video": true,
+	"input": true, "button": true, "select": true, "textarea": true,
+	"img": true,
+}
+
+// Elements which contain blocks, but are not blocks themselves.
+var containerTags = map[string]bool{
+	"div": true, "section": true, "article": true, "header": true,
+	"footer": true, "main": true, "aside": true, "nav": true,
+	"figure": true, "figcaption": true, "table": true, "thead": true,
+	"tbody": true, "tfoot": true, "tr": true, "td": true, "th": true,
+	"dl": true, "dt": true, "dd": true, "address": true, "body": true,
+	"html": true, "form": true, "fieldset": true, "details": true,
+	"summary": true,
+}
+
+var blockTags = map[string]bool{
+	BlockParagraph: true, BlockHeading1: true, BlockHeading2: true,
+	BlockHeading3: true, BlockHeading4: true, BlockHeading5: true,
+	BlockHeading6: true, BlockQuote: true, BlockCode: true,
+}
+
+var inlineMarks = map[string]Mark{
+	"b": MarkBold, "strong": MarkBold,
+	"i": MarkItalic, "em": MarkItalic,
+	"u": MarkUnderline, "ins": MarkUnderline,
+	"s": MarkStrike, "strike": MarkStrike, "del": MarkStrike,
+	"code": MarkCode, "kbd": MarkCode, "samp": MarkCode, "tt": MarkCode,
+}
+
+// textRef records where the text of a DOM node ended up in the document.
+type textRef struct {
+	node      node
+	block     int
+	offset    int
+	length    int
+	br        bool
+	pre       bool
+	prevSpace bool
+}
+
+// reader converts a tree of nodes into a sanitized document.
+//
+// Anything which is not known to the schema is either unwrapped or dropped.
+type reader struct {
+	schema *Schema
+	doc    *Document
+	cur    *Block
+	marks  Mark
+	href   string
+	pre    bool
+
+	// sources are the nodes each block was read from.
+	sources [][]node
+	refs    []textRef
+}
+
+func newReader(schema *Schema) *reader {
+	if schema == nil {
+		schema = NewSchema()
+	}
+	return &reader{
+		schema: schema,
+		doc:    &Document{},
+	}
+}
+
+func (r *reader) readNodes(nodes []node, typ string) {
+	for _, n := range nodes {
+		r.readNode(n, typ)
+	}
+}
+
+func (r *reader) readNode(n node, typ string) {
+	if n.isText() {
+		r.readText(n, typ)
+		return
+	}
+
+	var tag = n.tag()
+	switch {
+	case dropTags[tag]:
+		return
+	case tag == "br":
+		r.start(n, typ)
+		r.refs = append(r.refs, textRef{
+			node:   n,
+			block:  len(r.doc.Blocks),
+			offset: r.cur.Len(),
+			length: 1,
+			br:     true,
+		})
+		r.append("\n")
+	case tag == BlockRule:
+		r.end()
+		r.sources = append(r.sources, []node{n})
+		r.doc.Blocks = append(r.doc.Blocks, NewBlock(BlockRule))
+	case tag == BlockBulletList || tag == BlockOrderedList:
+		r.end()
+		for _, c := range n.children() {
+			if !c.isText() && c.tag() == "li" {
+				r.readBlock(c, tag)
+			} else {
+				r.readNode(c, tag)
+			}
+			r.end()
+		}
+	case tag == "li":
+		if typ != BlockBulletList && typ != BlockOrderedList {
+			typ = BlockBulletList
+		}
+		r.readBlock(n, typ)
+	case r.schema.match(tag, n.attr("class")) != nil:
+		r.readBlock(n, r.schema.match(tag, n.attr("class")).Name)
+	case blockTags[tag]:
+		if tag == BlockParagraph && typ != BlockParagraph && typ != "" {
+			tag = typ
+		}
+		r.readBlock(n, tag)
+	case containerTags[tag]:
+		r.end()
+		r.readNodes(n.children(), typ)
+		r.end()
+	default:
+		var marks, href = r.marks, r.href
+		if m, ok := inlineMarks[tag]; ok && !(m == MarkCode && r.pre) {
+			r.marks |= m
+		}
+		if tag == "a" {
+			r.href = safeHref(n.attr("href"))
+		}
+		r.readNodes(n.children(), typ)
+		r.marks, r.href = marks, href
+	}
+}
+
+// readBlock reads an element as a block of type typ.
+//
+// If the element contains other blocks, each of them becomes a block of type typ instead.
+func (r *reader) readBlock(n node, typ string) {
+	r.end()
+	var pre = r.pre
+	if typ == BlockCode {
+		r.pre = true
+	}
+	var children = n.children()
+	if r.hasBlocks(children) {
+		r.readNodes(children, typ)
+	} else {
+		r.cur = NewBlock(typ)
+		r.cur.Align = alignment(n)
+		r.sources = append(r.sources, []node{n})
+		r.readNodes(children, typ)
+	}
+	r.end()
+	r.pre = pre
+}
+
+func (r *reader) hasBlocks(nodes []node) bool {
+	for _, n := range nodes {
+		if n.isText() {
+			continue
+		}
+		var tag = n.tag()
+		switch {
+		case blockTags[tag], containerTags[tag], tag == "li", tag == BlockRule,
+			tag == BlockBulletList, tag == BlockOrderedList:
+			return true
+		case r.schema.match(tag, n.attr("class")) != nil:
+			return true
+		}
+	}
+	return false
+}
+
+func (r *reader) readText(n node, typ string) {
+	var text = n.text()
+	if !r.pre && r.cur == nil && strings.TrimSpace(text) == "" {
+		return
+	}
+	r.start(n, typ)
+	var prevSpace = r.prevSpace()
+	if !r.pre {
+		text = collapseSpace(text, prevSpace)
+	}
+	r.refs = append(r.refs, textRef{
+		node:      n,
+		block:     len(r.doc.Blocks),
+		offset:    r.cur.Len(),
+		length:    len([]rune(text)),
+		pre:       r.pre,
+		prevSpace: prevSpace,
+	})
+	r.append(text)
+}
+
+// start starts a new block if no block is being read.
+func (r *reader) start(n node, typ string) {
+	if r.cur != nil {
+		return
+	}
+	if typ == "" {
+		typ = BlockParagraph
+	}
+	r.cur = NewBlock(typ)
+	r.sources = append(r.sources, []node{n})
+}
+
+func (r *reader) append(text string) {
+	r.cur.Inlines = append(r.cur.Inlines, Inline{
+		Text:  text,
+		Marks: r.marks,
+		Href:  r.href,
+	})
+}
+
+func (r *reader) prevSpace() bool {
+	var text = r.cur.Text()
+	return text == "" || strings.HasSuffix(text, " ") || strings.HasSuffix(text, "\n")
+}
+
+// end adds the current block to the document.
+func (r *reader) end() {
+	if r.cur == nil {
+		return
+	}
+	r.cur.normalize()
+	// A trailing <br> only keeps the line open in the browser.
+	if last := len(r.cur.Inlines) - 1; last >= 0 && strings.HasSuffix(r.cur.Inlines[last].Text, "\n") {
+		r.cur.Inlines[last].Text = strings.TrimSuffix(r.cur.Inlines[last].Text, "\n")
+		r.cur.normalize()
+	}
+	r.doc.Blocks = append(r.doc.Blocks, r.cur)
+	r.cur = nil
+}
+
+func (r *reader) document() *Document {
+	r.end()
+	r.doc.normalize()
+	return r.doc
+}
+
+// offset converts an offset within the DOM node of the ref to an offset in the block.
+func (ref textRef) modelOffset(domOffset int) int {
+	if ref.br {
+		if domOffset > 0 {
+			return ref.offset + 1
+		}
+		return ref.offset
+	}
+	var units = utf16.Encode([]rune(ref.node.text()))
+	if domOffset > len(units) {
+		domOffset = len(units)
+	}
+	var prefix = string(utf16.Decode(units[:domOffset]))
+	if !ref.pre {
+		prefix = collapseSpace(prefix, ref.prevSpace)
+	}
+	return ref.offset + len([]rune(prefix))
+}
+
+// domOffset converts an offset in the block to an offset within the DOM node of the ref.
+func (ref textRef) domOffset(offset int) int {
+	var runes = []rune(ref.node.text())
+	var want = offset - ref.offset
+	for i := 0; i <= len(runes); i++ {
+		var prefix = string(runes[:i])
+		if !ref.pre {
+			prefix = collapseSpace(prefix, ref.prevSpace)
+		}
+		if len([]rune(prefix)) >= want {
+			return len(utf16.Encode(runes[:i]))
+		}
+	}
+	return len(utf16.Encode(runes))
+}
+
+// collapseSpace collapses whitespace the way a browser renders it.
+func collapseSpace(s string, prevSpace bool) string {
+	var sb strings.Builder
+	for _, c := range s {
+		switch c {
+		case ' ', '\n', '\t', '\r', '\f':
+			if prevSpace {
+				continue
+			}
+			sb.WriteByte(' ')
+			prevSpace = true
+		case ' ':
+			sb.WriteByte(' ')
+			prevSpace = false
+		default:
+			sb.WriteRune(c)
+			prevSpace = false
+		}
+	}
+	return sb.String()
+}
+
+func alignment(n node) string {
+	if a := n.attr("align"); a != "" {
+		return validAlign(a)
+	}
+	for _, decl := range strings.Split(n.attr("style"), ";") {
+		var k, v, ok = strings.Cut(decl, ":")
+		if ok && strings.TrimSpace(strings.ToLower(k)) == "text-align" {
+			return validAlign(v)
+		}
+	}
+	return ""
+}
+
+func validAlign(a string) string {
+	switch a = strings.TrimSpace(strings.ToLower(a)); a {
+	case "left", "center", "right", "justify":
+		return a
+	}
+	return ""
+}
+
+// safeHref returns the href if it is safe to link to, otherwise an empty string.
+func safeHref(href string) string {
+	href = strings.TrimSpace(href)
+	var lower = strings.ToLower(href)
+	var colon = strings.IndexByte(lower, ':')
+	if colon < 0 || strings.IndexAny(lower[:colon], "/?#") >= 0 {
+		return href
+	}
+	switch lower[:colon] {
+	case "http", "https", "mailto", "tel":
+		return href
+	}
+	return ""
+}
+
+// ParseHTML parses and sanitizes the HTML into a document.
+func (s *Schema) ParseHTML(src string) (*Document, error) {
+	var body = &xhtml.Node{
+		Type:     xhtml.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	}
+	var nodes, err = xhtml.ParseFragment(strings.NewReader(src), body)
+	if err != nil {
+		return nil, err
+	}
+	var r = newReader(s)
+	for _, n := range nodes {
+		if n.Type == xhtml.TextNode || n.Type == xhtml.ElementNode {
+			r.readNode(htmlNode{n}, "")
+		}
+	}
+	var doc = r.document()
+	doc.trimSpace()
+	return doc, nil
+}
+
+// SanitizeHTML parses the HTML and renders it again, removing anything unknown to the schema.
+func (s *Schema) SanitizeHTML(src string) (string, error) {
+	var doc, err = s.ParseHTML(src)
+	if err != nil {
+		return "", err
+	}
+	return s.RenderHTML(doc), nil
+}
+
+// RenderHTML renders the document as HTML.
+func (s *Schema) RenderHTML(doc *Document) string {
+	return s.renderHTML(doc, false)
+}
+
+// renderHTML renders the document, editable adds placeholders to keep empty lines open.
+func (s *Schema) renderHTML(doc *Document, editable bool) string {
+	var sb strings.Builder
+	var list string
+	for _, b := range doc.Blocks {
+		var isList = b.Type == BlockBulletList || b.Type == BlockOrderedList
+		if list != "" && (!isList || b.Type != list) {
+			sb.WriteString("</" + list + ">")
+			list = ""
+		}
+		if isList && list == "" {
+			list = b.Type
+			sb.WriteString("<" + list + ">")
+		}
+
+		var tag, class = b.Type, ""
+		switch {
+		case isList:
+			tag = "li"
+		case b.Type == BlockRule:
+			sb.WriteString("<hr>")
+			continue
+		case blockTags[b.Type]:
+		case s.BlockType(b.Type) != nil:
+			var bt = s.BlockType(b.Type)
+			tag, class = bt.Tag, bt.Class
+		default:
+			tag = BlockParagraph
+		}
+
+		sb.WriteString("<" + tag)
+		if class != "" {
+			sb.WriteString(` class="` + html.EscapeString(class) + `"`)
+		}
+		if b.Align != "" {
+			sb.WriteString(` style="text-align: ` + b.Align + `"`)
+		}
+		sb.WriteString(">")
+		renderInlines(&sb, b.Inlines, b.Type == BlockCode)
+		if editable && (len(b.Inlines) == 0 || strings.HasSuffix(b.Text(), "\n")) {
+			sb.WriteString("<br>")
+		}
+		sb.WriteString("</" + tag + ">")
+	}
+	if list != "" {
+		sb.WriteString("</" + list + ">")
+	}
+	return sb.String()
+}
+
+func renderInlines(sb *strings.Builder, inlines []Inline, pre bool) {
+	var prevSpace = true
+	for idx, i := range inlines {
+		if i.Href != "" {
+			sb.WriteString(`<a href="` + html.EscapeString(i.Href) + `">`)
+		}
+		var tags = markTags(i.Marks)
+		for _, t := range tags {
+			sb.WriteString("<" + t + ">")
+		}
+		var last = idx == len(inlines)-1
+		sb.WriteString(escapeText(i.Text, pre, &prevSpace, last))
+		for j := len(tags) - 1; j >= 0; j-- {
+			sb.WriteString("</" + tags[j] + ">")
+		}
+		if i.Href != "" {
+			sb.WriteString("</a>")
+		}
+	}
+}
+
+func markTags(m Mark) []string {
+	var tags []string
+	if m&MarkBold != 0 {
+		tags = append(tags, "strong")
+	}
+	if m&MarkItalic != 0 {
+		tags = append(tags, "em")
+	}
+	if m&MarkUnderline != 0 {
+		tags = append(tags, "u")
+	}
+	if m&MarkStrike != 0 {
+		tags = append(tags, "s")
+	}
+	if m&MarkCode != 0 {
+		tags = append(tags, "code")
+	}
+	return tags
+}
+
+// escapeText escapes the text, and keeps spaces which the browser would collapse with &nbsp;.
+func escapeText(text string, pre bool, prevSpace *bool, last bool) string {
+	if pre {
+		return html.EscapeString(text)
+	}
+	var sb strings.Builder
+	var runes = []rune(text)
+	for i, c := range runes {
+		switch c {
+		case '\n':
+			sb.WriteString("<br>")
+			*prevSpace = true
+		case ' ':
+			if *prevSpace || (last && i == len(runes)-1) {
+				sb.WriteString("&nbsp;")
+				*prevSpace = false
+			} else {
+				sb.WriteByte(' ')
+				*prevSpace = true
+			}
+		default:
+			sb.WriteString(html.EscapeString(string(c)))
+			*prevSpace = false
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,74 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package texteditor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseHTML(t *testing.T) {
+	var doc, err = NewSchema().ParseHTML(`<div><p style="text-align: center">Hello <b>bold</b> <a href="https://example.com"><i>link</i></a></p><script>alert(1)</script><ul><li>one</li><li>two</li></ul></div>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want = &Document{Blocks: []*Block{
+		{Type: BlockParagraph, Align: "center", Inlines: []Inline{
+			{Text: "Hello "},
+			{Text: "bold", Marks: MarkBold},
+			{Text: " "},
+			{Text: "link", Marks: MarkItalic, Href: "https://example.com"},
+		}},
+		{Type: BlockBulletList, Inlines: []Inline{{Text: "one"}}},
+		{Type: BlockBulletList, Inlines: []Inline{{Text: "two"}}},
+	}}
+	if !reflect.DeepEqual(doc, want) {
+		t.Fatalf("expected %+v, got %+v", blocks(want), blocks(doc))
+	}
+}
+
+func TestSanitizeHTML(t *testing.T) {
+	var tests = []struct {
+		src  string
+		want string
+	}{
+		{`<p onclick="alert(1)">text</p>`, `<p>text</p>`},
+		{`<a href="javascript:alert(1)">text</a>`, `<p>text</p>`},
+		{`<p>a<img src=x onerror=alert(1)>b</p>`, `<p>ab</p>`},
+		{`<p>&lt;script&gt;</p>`, `<p>&lt;script&gt;</p>`},
+	}
+	var s = NewSchema()
+	for _, test := range tests {
+		var got, err = s.SanitizeHTML(test.src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("%s: expected %s, got %s", test.src, test.want, got)
+		}
+	}
+}
+
+func TestHTMLRoundTrip(t *testing.T) {
+	var doc = NewDocument(
+		NewBlock(BlockHeading1, Inline{Text: "Title"}),
+		NewBlock(BlockParagraph,
+			Inline{Text: "two  spaces "},
+			Inline{Text: "<tag> & more", Marks: MarkBold | MarkItalic},
+			Inline{Text: "\nbreak", Href: "https://x/a(b)"},
+		),
+		NewBlock(BlockCode, Inline{Text: "if a < b {\n  return\n}"}),
+		NewBlock(BlockRule),
+		NewBlock(BlockOrderedList, Inline{Text: "item"}),
+	)
+	doc.Blocks[1].Align = "right"
+	var s = NewSchema()
+	var got, err = s.ParseHTML(s.RenderHTML(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, doc) {
+		t.Fatalf("expected %+v, got %+v", blocks(doc), blocks(got))
+	}
+}
@@ -0,0 +1,436 @@
+package texteditor
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// RenderMarkdown renders the document as Markdown.
+//
+// Alignment is not supported by Markdown and is left out,
+// underlined text is written as inline HTML.
+func (s *Schema) RenderMarkdown(doc *Document) string {
+	var sb strings.Builder
+	var number int
+	for idx, b := range doc.Blocks {
+		if idx > 0 {
+			var prev = doc.Blocks[idx-1]
+			if prev.Type == b.Type && (b.Type == BlockBulletList || b.Type == BlockOrderedList) {
+				sb.WriteString("\n")
+			} else {
+				sb.WriteString("\n\n")
+				number = 0
+			}
+		}
+
+		switch {
+		case isHeading(b.Type):
+			var level = int(b.Type[1] - '0')
+			sb.WriteString(strings.Repeat("#", level) + " ")
+			sb.WriteString(strings.ReplaceAll(markdownInlines(b.Inlines), "\\\n", " "))
+		case b.Type == BlockQuote:
+			sb.WriteString(prefixLines(markdownInlines(b.Inlines), "> ", "> "))
+		case b.Type == BlockCode:
+			var fence = "```"
+			for strings.Contains(b.Text(), fence) {
+				fence += "`"
+			}
+			sb.WriteString(fence + "\n" + b.Text() + "\n" + fence)
+		case b.Type == BlockBulletList:
+			sb.WriteString(prefixLines(markdownInlines(b.Inlines), "- ", "  "))
+		case b.Type == BlockOrderedList:
+			number++
+			var prefix = strconv.Itoa(number) + ". "
+			sb.WriteString(prefixLines(markdownInlines(b.Inlines), prefix, strings.Repeat(" ", len(prefix))))
+		case b.Type == BlockRule:
+			sb.WriteString("---")
+		case s.BlockType(b.Type) != nil && s.BlockType(b.Type).Markdown != "":
+			var prefix = s.BlockType(b.Type).Markdown
+			sb.WriteString(prefixLines(markdownInlines(b.Inlines), prefix, prefix))
+		default:
+			sb.WriteString(markdownInlines(b.Inlines))
+		}
+	}
+	return sb.String()
+}
+
+func prefixLines(text, first, rest string) string {
+	var lines = strings.Split(text, "\n")
+	for i, line := range lines {
+		if i == 0 {
+			lines[i] = first + line
+		} else {
+			lines[i] = rest + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func markdownInlines(inlines []Inline) string {
+	var sb strings.Builder
+	for idx, i := range inlines {
+		var text = i.Text
+		var core = strings.Trim(text, " ")
+		if core == "" {
+			sb.WriteString(escapeMarkdown(text, idx == 0))
+			continue
+		}
+		var lead = text[:strings.Index(text, core)]
+		var trail = text[len(lead)+len(core):]
+
+		if i.Has(MarkCode) {
+			var ticks = "`"
+			for strings.Contains(core, ticks) {
+				ticks += "`"
+			}
+			core = ticks + core + ticks
+		} else {
+			core = escapeMarkdown(core, idx == 0 && lead == "")
+		}
+		if i.Has(MarkStrike) {
+			core = "~~" + core + "~~"
+		}
+		if i.Has(MarkUnderline) {
+			core = "<u>" + core + "</u>"
+		}
+		if i.Has(MarkItalic) {
+			core = "_" + core + "_"
+		}
+		if i.Has(MarkBold) {
+			core = "**" + core + "**"
+		}
+		if i.Href != "" {
+			core = "[" + core + "](" + hrefEscaper.Replace(i.Href) + ")"
+		}
+		sb.WriteString(escapeMarkdown(lead, false))
+		sb.WriteString(core)
+		sb.WriteString(escapeMarkdown(trail, false))
+	}
+	return sb.String()
+}
+
+// hrefEscaper percent-encodes the characters which would end a link target early, or make it unbalanced.
+var hrefEscaper = strings.NewReplacer("(", "%28", ")", "%29", "\\", "%5C", " ", "%20")
+
+// escapeMarkdown escapes characters which would otherwise be read as Markdown.
+//
+// Hard line breaks are written as a backslash at the end of the line.
+func escapeMarkdown(text string, start bool) string {
+	var sb strings.Builder
+	var lineStart = start
+	for _, c := range text {
+		switch c {
+		case '\\', '`', '*', '_', '[', ']', '<', '>', '~':
+			sb.WriteByte('\\')
+			sb.WriteRune(c)
+		case '#', '-', '+':
+			if lineStart {
+				sb.WriteByte('\\')
+			}
+			sb.WriteRune(c)
+		case '\n':
+			sb.WriteString("\\\n")
+			lineStart = true
+			continue
+		default:
+			sb.WriteRune(c)
+		}
+		lineStart = false
+	}
+	return sb.String()
+}
+
+// ParseMarkdown parses Markdown into a document.
+//
+// Only the subset of Markdown which the document can represent is supported,
+// nested blocks are flattened.
+func (s *Schema) ParseMarkdown(src string) *Document {
+	var lines = strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	var doc = &Document{}
+	var para []string
+
+	var flush = func() {
+		if len(para) > 0 {
+			doc.Blocks = append(doc.Blocks, NewBlock(BlockParagraph, parseInline(joinLines(para), 0, "")...))
+			para = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		var line = lines[i]
+		var trimmed = strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			flush()
+			// The closing fence is at least as long as the opening fence.
+			var fence = trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, trimmed[:1]))]
+			var code []string
+			for i++; i < len(lines) && !isClosingFence(lines[i], fence); i++ {
+				code = append(code, lines[i])
+			}
+			doc.Blocks = append(doc.Blocks, NewBlock(BlockCode, Inline{Text: strings.Join(code, "\n")}))
+		case isMarkdownRule(trimmed):
+			flush()
+			doc.Blocks = append(doc.Blocks, NewBlock(BlockRule))
+		case markdownHeading(trimmed) > 0:
+			flush()
+			var level = markdownHeading(trimmed)
+			var text = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(trimmed[level:]), "#"))
+			doc.Blocks = append(doc.Blocks, NewBlock("h"+strconv.Itoa(level), parseInline(text, 0, "")...))
+		case s.matchMarkdown(line) != nil:
+			flush()
+			var bt = s.matchMarkdown(line)
+			var content []string
+			for ; i < len(lines) && strings.HasPrefix(lines[i], bt.Markdown); i++ {
+				content = append(content, strings.TrimPrefix(lines[i], bt.Markdown))
+			}
+			i--
+			doc.Blocks = append(doc.Blocks, NewBlock(bt.Name, parseInline(joinLines(content), 0, "")...))
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			var content []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				var l = strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				content = append(content, strings.TrimPrefix(l, " "))
+			}
+			i--
+			doc.Blocks = append(doc.Blocks, NewBlock(BlockQuote, parseInline(joinLines(content), 0, "")...))
+		case listMarker(line) > 0:
+			flush()
+			var typ = BlockBulletList
+			if c := trimmed[0]; c >= '0' && c <= '9' {
+				typ = BlockOrderedList
+			}
+			var content = []string{line[listMarker(line):]}
+			for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" && listMarker(lines[i+1]) == 0 &&
+				(strings.HasPrefix(lines[i+1], " ") || strings.HasPrefix(lines[i+1], "\t")) {
+				i++
+				content = append(content, strings.TrimSpace(lines[i]))
+			}
+			doc.Blocks = append(doc.Blocks, NewBlock(typ, parseInline(joinLines(content), 0, "")...))
+		default:
+			para = append(para, line)
+		}
+	}
+	flush()
+	doc.normalize()
+	return doc
+}
+
+// joinLines joins the lines of a block, lines ending with a backslash or two spaces are hard breaks.
+func joinLines(lines []string) string {
+	var sb strings.Builder
+	for i, line := range lines {
+		if i == len(lines)-1 {
+			sb.WriteString(strings.TrimSpace(line))
+			break
+		}
+		switch {
+		case strings.HasSuffix(line, "\\") && !strings.HasSuffix(line, "\\\\"):
+			sb.WriteString(strings.TrimLeft(line[:len(line)-1], " "))
+			sb.WriteString("\n")
+		case strings.HasSuffix(line, "  "):
+			sb.WriteString(strings.TrimSpace(line))
+			sb.WriteString("\n")
+		default:
+			sb.WriteString(strings.TrimSpace(line))
+			sb.WriteString(" ")
+		}
+	}
+	return sb.String()
+}
+
+// isClosingFence reports whether the line closes a code block opened with fence.
+func isClosingFence(line, fence string) bool {
+	var trimmed = strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == ""
+}
+
+func isMarkdownRule(line string) bool {
+	var s = strings.ReplaceAll(line, " ", "")
+	if len(s) < 3 {
+		return false
+	}
+	return strings.Trim(s, "-") == "" || strings.Trim(s, "*") == "" || strings.Trim(s, "_") == ""
+}
+
+// markdownHeading returns the level of the heading, or 0.
+func markdownHeading(line string) int {
+	var level int
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return 0
+	}
+	if level < len(line) && line[level] != ' ' && line[level] != '\t' {
+		return 0
+	}
+	return level
+}
+
+// listMarker returns the length of the list marker at the start of the line, or 0.
+func listMarker(line string) int {
+	var indent = len(line) - len(strings.TrimLeft(line, " "))
+	var s = line[indent:]
+	if len(s) >= 2 && (s[0] == '-' || s[0] == '*' || s[0] == '+') && s[1] == ' ' {
+		return indent + 2
+	}
+	var i int
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i > 0 && i+1 < len(s) && (s[i] == '.' || s[i] == ')') && s[i+1] == ' ' {
+		return indent + i + 2
+	}
+	return 0
+}
+
+// parseInline parses inline Markdown.
+func parseInline(s string, marks Mark, href string) []Inline {
+	var inlines []Inline
+	var sb strings.Builder
+	var emit = func(text string, m Mark, h string) {
+		inlines = append(inlines, Inline{Text: text, Marks: m, Href: h})
+	}
+	var flush = func() {
+		if sb.Len() > 0 {
+			emit(sb.String(), marks, href)
+			sb.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); {
+		var rest = s[i:]
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && isMarkdownPunct(rest[1]):
+			sb.WriteByte(rest[1])
+			i += 2
+			continue
+		case rest[0] == '`':
+			var ticks = len(rest) - len(strings.TrimLeft(rest, "`"))
+			var end = strings.Index(rest[ticks:], rest[:ticks])
+			if end > 0 {
+				flush()
+				emit(strings.TrimSpace(rest[ticks:ticks+end]), marks|MarkCode, href)
+				i += ticks*2 + end
+				continue
+			}
+		case strings.HasPrefix(rest, "<u>"):
+			if end := findClose(rest[3:], "</u>"); end > 0 {
+				flush()
+				inlines = append(inlines, parseInline(rest[3:3+end], marks|MarkUnderline, href)...)
+				i += 3 + end + 4
+				continue
+			}
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if canOpen(s, i, 2) {
+				if end := findClose(rest[2:], rest[:2]); end > 0 {
+					flush()
+					inlines = append(inlines, parseInline(rest[2:2+end], marks|MarkBold, href)...)
+					i += 2 + end + 2
+					continue
+				}
+			}
+		case strings.HasPrefix(rest, "~~"):
+			if end := findClose(rest[2:], "~~"); end > 0 {
+				flush()
+				inlines = append(inlines, parseInline(rest[2:2+end], marks|MarkStrike, href)...)
+				i += 2 + end + 2
+				continue
+			}
+		case rest[0] == '*' || rest[0] == '_':
+			if canOpen(s, i, 1) {
+				if end := findClose(rest[1:], rest[:1]); end > 0 {
+					flush()
+					inlines = append(inlines, parseInline(rest[1:1+end], marks|MarkItalic, href)...)
+					i += 1 + end + 1
+					continue
+				}
+			}
+		case rest[0] == '[':
+			if end := findClose(rest[1:], "]("); end >= 0 {
+				var target = rest[1+end+2:]
+				if close := closeParen(target); close >= 0 {
+					flush()
+					var link = safeHref(strings.TrimSpace(target[:close]))
+					inlines = append(inlines, parseInline(rest[1:1+end], marks, link)...)
+					i += 1 + end + 2 + close + 1
+					continue
+				}
+			}
+		}
+		var _, size = utf8.DecodeRuneInString(rest)
+		sb.WriteString(rest[:size])
+		i += size
+	}
+	flush()
+	return inlines
+}
+
+// closeParen returns the index of the parenthesis which closes a link target,
+// parentheses inside the target must be balanced.
+func closeParen(s string) int {
+	var depth int
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// findClose returns the index of the closing delimiter, skipping escaped characters.
+func findClose(s, delim string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], delim) {
+			if len(delim) <= 2 && delim[0] != ']' && (i == 0 || s[i-1] == ' ') {
+				// Emphasis can not be empty or end after a space.
+				if i == 0 {
+					return -1
+				}
+				continue
+			}
+			if len(delim) == 1 && i+1 < len(s) && s[i+1] == delim[0] {
+				// Skip "**" when looking for "*".
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// canOpen reports whether the delimiter at i can open emphasis.
+func canOpen(s string, i, n int) bool {
+	if i+n >= len(s) || s[i+n] == ' ' {
+		return false
+	}
+	if s[i] == '_' && i > 0 {
+		var r, _ = utf8.DecodeLastRuneInString(s[:i])
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}
+	return true
+}
+
+func isMarkdownPunct(c byte) bool {
+	return strings.IndexByte("\\`*_{}[]()#+-.!<>~|", c) >= 0
+}
@@ -0,0 +1,102 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package texteditor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMarkdown(t *testing.T) {
+	var doc = NewSchema().ParseMarkdown("# Title\n\nSome **bold** and _italic_ text.\n\n- one\n- two\n\n---")
+	var types []string
+	for _, b := range doc.Blocks {
+		types = append(types, b.Type)
+	}
+	var want = []string{BlockHeading1, BlockParagraph, BlockBulletList, BlockBulletList, BlockRule}
+	if !reflect.DeepEqual(types, want) {
+		t.Fatalf("expected blocks %v, got %v", want, types)
+	}
+	var inlines = []Inline{
+		{Text: "Some "},
+		{Text: "bold", Marks: MarkBold},
+		{Text: " and "},
+		{Text: "italic", Marks: MarkItalic},
+		{Text: " text."},
+	}
+	if !reflect.DeepEqual(doc.Blocks[1].Inlines, inlines) {
+		t.Fatalf("expected %v, got %v", inlines, doc.Blocks[1].Inlines)
+	}
+}
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	var docs = []*Document{
+		NewDocument(
+			NewBlock(BlockHeading2, Inline{Text: "A *title*"}),
+			NewBlock(BlockParagraph,
+				Inline{Text: "plain "},
+				Inline{Text: "bold", Marks: MarkBold},
+				Inline{Text: " "},
+				Inline{Text: "code`tick", Marks: MarkCode},
+				Inline{Text: " "},
+				Inline{Text: "under", Marks: MarkUnderline | MarkStrike},
+			),
+			NewBlock(BlockQuote, Inline{Text: "quoted\nlines"}),
+			NewBlock(BlockCode, Inline{Text: "func main() {\n\t```\n}"}),
+			NewBlock(BlockOrderedList, Inline{Text: "first"}),
+			NewBlock(BlockOrderedList, Inline{Text: "second"}),
+		),
+		NewDocument(NewBlock(BlockParagraph, Inline{Text: "# not a heading [or a link](x)"})),
+	}
+	var s = NewSchema()
+	for _, doc := range docs {
+		var md = s.RenderMarkdown(doc)
+		var got = s.ParseMarkdown(md)
+		if !reflect.DeepEqual(got, doc) {
+			t.Errorf("round trip of %q:\nexpected %+v\ngot      %+v", md, blocks(doc), blocks(got))
+		}
+	}
+}
+
+func TestMarkdownLinks(t *testing.T) {
+	var tests = []struct {
+		href string
+		want string
+	}{
+		{"https://example.com", "https://example.com"},
+		{"https://en.wikipedia.org/wiki/Go_(programming_language)", "https://en.wikipedia.org/wiki/Go_%28programming_language%29"},
+		{"https://x/a(b", "https://x/a%28b"},
+		{"https://x/a)b", "https://x/a%29b"},
+		{`https://x/a\`, "https://x/a%5C"},
+		{"/path with spaces", "/path%20with%20spaces"},
+	}
+	var s = NewSchema()
+	for _, test := range tests {
+		var doc = NewDocument(NewBlock(BlockParagraph,
+			Inline{Text: "see "},
+			Inline{Text: "link", Href: test.href},
+			Inline{Text: " after"},
+		))
+		var md = s.RenderMarkdown(doc)
+		var inlines = s.ParseMarkdown(md).Blocks[0].Inlines
+		if len(inlines) != 3 || inlines[1].Text != "link" || inlines[1].Href != test.want {
+			t.Errorf("%s: expected a link to %q, got %+v from %q", test.href, test.want, inlines, md)
+		}
+	}
+}
+
+func TestParseMarkdownUnsafeLink(t *testing.T) {
+	var doc = NewSchema().ParseMarkdown("[x](javascript:alert(1))")
+	if href := doc.Blocks[0].Inlines[0].Href; href != "" {
+		t.Fatalf("expected an unsafe link to be removed, got %q", href)
+	}
+}
+
+func blocks(doc *Document) []Block {
+	var b = make([]Block, len(doc.Blocks))
+	for i := range doc.Blocks {
+		b[i] = *doc.Blocks[i]
+	}
+	return b
+}
@@ -0,0 +1,76 @@
+package texteditor
+
+import "strings"
+
+// BlockType describes a custom block type.
+type BlockType struct {
+	// Name is the type of the block in the document.
+	Name string
+	// Tag is the HTML tag of the block, defaults to "div".
+	Tag string
+	// Class is added to the HTML element, and used to recognize the block when parsing HTML.
+	Class string
+	// Markdown is the prefix of each line of the block, for example "!!! ".
+	//
+	// If empty, the block is written as a paragraph in Markdown.
+	Markdown string
+}
+
+// Schema holds the custom block types known to an editor.
+type Schema struct {
+	blocks map[string]*BlockType
+	order  []string
+}
+
+func NewSchema() *Schema {
+	return &Schema{
+		blocks: make(map[string]*BlockType),
+	}
+}
+
+// Register adds a custom block type to the schema.
+func (s *Schema) Register(bt *BlockType) {
+	if bt.Tag == "" {
+		bt.Tag = "div"
+	}
+	if _, ok := s.blocks[bt.Name]; !ok {
+		s.order = append(s.order, bt.Name)
+	}
+	s.blocks[bt.Name] = bt
+}
+
+// BlockType returns the custom block type with the name, or nil.
+func (s *Schema) BlockType(name string) *BlockType {
+	return s.blocks[name]
+}
+
+// match returns the custom block type for the HTML element.
+func (s *Schema) match(tag, class string) *BlockType {
+	var classes = strings.Fields(class)
+	for _, name := range s.order {
+		var bt = s.blocks[name]
+		if bt.Tag != tag {
+			continue
+		}
+		if bt.Class == "" {
+			return bt
+		}
+		for _, c := range classes {
+			if c == bt.Class {
+				return bt
+			}
+		}
+	}
+	return nil
+}
+
+// matchMarkdown returns the custom block type for a line of Markdown.
+func (s *Schema) matchMarkdown(line string) *BlockType {
+	for _, name := range s.order {
+		var bt = s.blocks[name]
+		if bt.Markdown != "" && strings.HasPrefix(line, bt.Markdown) {
+			return bt
+		}
+	}
+	return nil
+}
@@ -3,11 +3,11 @@ package texteditor
 import (
 	"fmt"
 	"net/url"
-	"syscall/js"
 
 	"github.com/Nigel2392/jsext/v2"
 	"github.com/Nigel2392/jsext/v2/jse"
 	"github.com/Nigel2392/jsext/v2/jse/components/svg"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 type Editor struct {
@@ -16,6 +16,12 @@ type Editor struct {
 	Editor       *jse.Element // Editor element
 	Footer       *jse.Element // Footer element
 	Conf         *Config
+
+	doc       *Document
+	history   *History
+	selection Range
+	refs      []textRef
+	sources   [][]node
 }
 
 type LintElement struct {
@@ -42,6 +48,11 @@ type Config struct {
 	LintElements     map[string]*LintElement // [name]LintElement
 	LintElementOrder []string
 	Options          []TextEditorOption
+
+	// Schema holds the custom block types of the editor.
+	Schema       *Schema
+	HistoryLimit int
+	OnChange     func(*Editor)
 }
 
 func (c *Config) SetOrder(order ...string) {
@@ -51,6 +62,10 @@ func (c *Config) SetOrder(order ...string) {
 	c.LintElementOrder = order
 }
 
+// AddLintElement adds a button to the lint.
+//
+// If config["block"] is a *BlockType, the block type is registered in the schema.
+// When onclick is nil, the button then toggles the selected blocks to this type.
 func (c *Config) AddLintElement(name string, svgFunc func(w, h int) *jse.SVG, onclick func(*Editor), config map[string]interface{}) {
 	if c.ButtonSize == 0 {
 		c.ButtonSize = 16
@@ -58,6 +73,17 @@ func (c *Config) AddLintElement(name string, svgFunc func(w, h int) *jse.SVG, on
 	if c.LintElements == nil {
 		c.LintElements = make(map[string]*LintElement)
 	}
+	if bt, ok := config["block"].(*BlockType); ok {
+		if c.Schema == nil {
+			c.Schema = NewSchema()
+		}
+		c.Schema.Register(bt)
+		if onclick == nil {
+			onclick = func(e *Editor) {
+				e.Dispatch(ToggleBlockType(bt.Name))
+			}
+		}
+	}
 	c.LintElements[name] = makeLintElement(c.ButtonSize, svgFunc, onclick, config)
 }
 
@@ -88,6 +114,12 @@ func makeLintElementCommand(size int, svgFunc func(w, h int) *jse.SVG, command s
 	}, nil)
 }
 
+func makeLintElementDispatch(size int, svgFunc func(w, h int) *jse.SVG, cmd Command) *LintElement {
+	return makeLintElement(size, svgFunc, func(e *Editor) {
+		e.Dispatch(cmd)
+	}, nil)
+}
+
 func (c *Config) Defaults() {
 	if c.LintElements == nil {
 		c.LintElements = make(map[string]*LintElement)
@@ -113,23 +145,29 @@ func (c *Config) Defaults() {
 	if c.MaxWidth == "" {
 		c.MaxWidth = "100%"
 	}
+	if c.Schema == nil {
+		c.Schema = NewSchema()
+	}
+	if c.HistoryLimit == 0 {
+		c.HistoryLimit = 100
+	}
 
-	c.LintElements["bold"] = makeLintElementCommand(c.ButtonSize, svg.Bold, "bold")
-	c.LintElements["italic"] = makeLintElementCommand(c.ButtonSize, svg.Italic, "italic")
-	c.LintElements["underline"] = makeLintElementCommand(c.ButtonSize, svg.Underline, "underline")
-	c.LintElements["strikethrough"] = makeLintElementCommand(c.ButtonSize, svg.StrikeThrough, "strikeThrough")
-	c.LintElements["ol"] = makeLintElementCommand(c.ButtonSize, svg.ListOL, "insertOrderedList")
-	c.LintElements["ul"] = makeLintElementCommand(c.ButtonSize, svg.ListUL, "insertUnorderedList")
-	c.LintElements["text-left"] = makeLintElementCommand(c.ButtonSize, svg.TextLeft, "justifyLeft")
-	c.LintElements["text-center"] = makeLintElementCommand(c.ButtonSize, svg.TextCenter, "justifyCenter")
-	c.LintElements["text-right"] = makeLintElementCommand(c.ButtonSize, svg.TextRight, "justifyRight")
+	c.LintElements["bold"] = makeLintElementDispatch(c.ButtonSize, svg.Bold, ToggleMark(MarkBold))
+	c.LintElements["italic"] = makeLintElementDispatch(c.ButtonSize, svg.Italic, ToggleMark(MarkItalic))
+	c.LintElements["underline"] = makeLintElementDispatch(c.ButtonSize, svg.Underline, ToggleMark(MarkUnderline))
+	c.LintElements["strikethrough"] = makeLintElementDispatch(c.ButtonSize, svg.StrikeThrough, ToggleMark(MarkStrike))
+	c.LintElements["ol"] = makeLintElementDispatch(c.ButtonSize, svg.ListOL, ToggleBlockType(BlockOrderedList))
+	c.LintElements["ul"] = makeLintElementDispatch(c.ButtonSize, svg.ListUL, ToggleBlockType(BlockBulletList))
+	c.LintElements["text-left"] = makeLintElementDispatch(c.ButtonSize, svg.TextLeft, SetAlign("left"))
+	c.LintElements["text-center"] = makeLintElementDispatch(c.ButtonSize, svg.TextCenter, SetAlign("center"))
+	c.LintElements["text-right"] = makeLintElementDispatch(c.ButtonSize, svg.TextRight, SetAlign("right"))
 
 	c.LintElements["link"] = makeLintElement(c.ButtonSize, svg.Link, func(e *Editor) {
 		// check if there is a selection
 		var selected = e.CurrentlySelectedText()
 		var _, err = url.Parse(selected)
 		if err == nil {
-			jsext.Document.Call("execCommand", "createLink", false, selected)
+			e.Dispatch(SetLink(selected))
 		}
 	}, nil)
 
@@ -167,7 +205,8 @@ func New(conf *Config) *Editor {
 		Editor:  e.NewElement("text-editor"),
 		Footer:  e.NewElement("text-editor-footer"),
 
-		Conf: conf,
+		Conf:    conf,
+		history: NewHistory(conf.HistoryLimit),
 	}
 
 	editor.Element.Style().Width(conf.Width)
@@ -181,6 +220,16 @@ func New(conf *Config) *Editor {
 		editor.Element.Style().MinWidth(conf.MinWidth)
 	}
 	editor.Editor.SetAttr("contenteditable", "true")
+	jsext.Document.Call("execCommand", "defaultParagraphSeparator", false, "p")
+
+	editor.doc = NewDocument()
+	if conf.Value != "" {
+		if doc, err := conf.Schema.ParseHTML(conf.Value); err == nil {
+			editor.doc = doc
+		}
+	}
+	editor.render()
+	editor.listen()
 
 	if conf.AllowResizeX || conf.AllowResizeY {
 		var resize = svg.TextareaResize(conf.ButtonSize, conf.ButtonSize)
@@ -293,6 +342,49 @@ func WithEditorWidth(w string) TextEditorOption {
 	}
 }
 
+// Document returns the document of the editor.
+//
+// Changes should be made through Dispatch, so they can be undone.
+func (e *Editor) Document() *Document {
+	return e.doc
+}
+
+// SetDocument replaces the document of the editor.
+func (e *Editor) SetDocument(doc *Document) {
+	e.history.push(e.doc, e.selection, false)
+	e.doc = doc.Clone()
+	e.doc.normalize()
+	e.render()
+	e.changed()
+}
+
+func (e *Editor) History() *History {
+	return e.history
+}
+
+// HTML returns the sanitized HTML of the document.
+func (e *Editor) HTML() string {
+	return e.Conf.Schema.RenderHTML(e.doc)
+}
+
+// SetHTML sanitizes the HTML and replaces the document with it.
+func (e *Editor) SetHTML(src string) error {
+	var doc, err = e.Conf.Schema.ParseHTML(src)
+	if err != nil {
+		return err
+	}
+	e.SetDocument(doc)
+	return nil
+}
+
+func (e *Editor) Markdown() string {
+	return e.Conf.Schema.RenderMarkdown(e.doc)
+}
+
+func (e *Editor) SetMarkdown(src string) {
+	e.SetDocument(e.Conf.Schema.ParseMarkdown(src))
+}
+
 func (e *Editor) Value() *jse.Element {
 	return e.Editor
 }
@@ -0,0 +1,300 @@
+package shortcuts
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/Nigel2392/jsext/v2"
+	"github.com/Nigel2392/jsext/v2/jse"
+)
+
+//	<div class="jsext-virtuallist" style="height:400px;overflow:auto;">
+//		<div class="jsext-virtuallist-inner" style="height:<total height>px;">
+//			<div class="jsext-virtuallist-row" data-index="10" style="top:400px;"> ... </div>
+//			<div class="jsext-virtuallist-row" data-index="11" style="top:440px;"> ... </div>
+//			<div class="jsext-virtuallist-row" data-index="12" style="top:480px;"> ... </div>
+//		</div>
+//	</div>
+
+type VirtualListOptions[T any] struct {
+	// Render fills the row element for the item at index.
+	//
+	// Row elements are recycled, the row is cleared before Render is called.
+	Render func(row *jse.Element, item T, index int)
+	// EstimatedHeight is used for rows which have not been measured yet.
+	EstimatedHeight int
+	// Number of rows to render above and below the visible rows.
+	Overscan int
+	// Height of the scrolling container.
+	Height      string
+	ClassPrefix string
+}
+
+func (o *VirtualListOptions[T]) Defaults() {
+	if o.EstimatedHeight == 0 {
+		o.EstimatedHeight = 40
+	}
+	if o.Overscan == 0 {
+		o.Overscan = 5
+	}
+	if o.Height == "" {
+		o.Height = "400px"
+	}
+	if o.ClassPrefix == "" {
+		o.ClassPrefix = "jsext-"
+	}
+	if o.Render == nil {
+		o.Render = func(row *jse.Element, item T, index int) {
+			row.InnerText(fmt.Sprint(item))
+		}
+	}
+}
+
+// VirtualList only keeps the visible items, plus Overscan items, in the DOM.
+//
+// Rows may have different heights; the height of each rendered row is measured and cached.
+type VirtualList[T any] struct {
+	root  *jse.Element
+	inner *jse.Element
+	opts  VirtualListOptions[T]
+
+	items   []T
+	heights []int // 0 if the row has not been measured
+	offsets []int // offsets[i] is the top of row i, offsets[len(items)] is the total height
+	dirty   bool
+
+	active map[int]*jse.Element
+	pool   []*jse.Element
+}
+
+// NewVirtualList creates a new virtual list from the items.
+func NewVirtualList[T any](items []T, opts VirtualListOptions[T]) *VirtualList[T] {
+	opts.Defaults()
+
+	var l = &VirtualList[T]{
+		opts:   opts,
+		active: make(map[int]*jse.Element),
+	}
+
+	l.root = jse.Div(l.class("virtuallist"))
+	l.root.Style().Height(opts.Height)
+	l.inner = l.root.Div(l.class("virtuallist-inner"))
+	l.root.OnScroll(func(_ *jse.Element, _ jsext.Event) {
+		l.render()
+	})
+
+	l.root.StyleBlock(`
+		.` + l.class("virtuallist") + ` {
+			overflow: auto;
+			position: relative;
+		}
+		.` + l.class("virtuallist-inner") + ` {
+			position: relative;
+			width: 100%;
+		}
+		.` + l.class("virtuallist-row") + ` {
+			position: absolute;
+			left: 0;
+			right: 0;
+		}`)
+
+	l.SetItems(items)
+	return l
+}
+
+func (l *VirtualList[T]) Element() *jse.Element {
+	return l.root
+}
+
+func (l *VirtualList[T]) Items() []T {
+	return l.items
+}
+
+func (l *VirtualList[T]) Len() int {
+	return len(l.items)
+}
+
+// SetItems replaces the items of the list and re-renders all visible rows.
+//
+// Cached heights are discarded.
+func (l *VirtualList[T]) SetItems(items []T) {
+	l.items = items
+	l.heights = make([]int, len(items))
+	l.dirty = true
+	l.recycleAll()
+	l.render()
+}
+
+// Append adds items to the end of the list.
+//
+// Useful in combination with OnScrolledToBottom for infinite loading.
+func (l *VirtualList[T]) Append(items ...T) {
+	l.items = append(l.items, items...)
+	l.heights = append(l.heights, make([]int, len(items))...)
+	l.dirty = true
+	l.render()
+}
+
+// Update re-renders the item at index and measures it again.
+func (l *VirtualList[T]) Update(index int, item T) {
+	if index < 0 || index >= len(l.items) {
+		return
+	}
+	l.items[index] = item
+	l.heights[index] = 0
+	l.dirty = true
+	if row, ok := l.active[index]; ok {
+		l.fill(row, index)
+	}
+	l.render()
+}
+
+// Refresh re-renders the visible rows, for example after the list was added to the DOM or resized.
+func (l *VirtualList[T]) Refresh() {
+	l.render()
+}
+
+// ScrollToIndex scrolls the list so that the item at index is at the top of the container.
+func (l *VirtualList[T]) ScrollToIndex(index int) {
+	if len(l.items) == 0 {
+		return
+	}
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(l.items) {
+		index = len(l.items) - 1
+	}
+	l.layout()
+	l.root.ScrollTo(0, l.offsets[index])
+	l.render()
+}
+
+// IndexAt returns the index of the item at the scroll offset y.
+func (l *VirtualList[T]) IndexAt(y int) int {
+	l.layout()
+	var i = sort.Search(len(l.items), func(i int) bool {
+		return l.offsets[i+1] > y
+	})
+	if i >= len(l.items) {
+		i = len(l.items) - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	return i
+}
+
+func (l *VirtualList[T]) class(name string) string {
+	return l.opts.ClassPrefix + name
+}
+
+func (l *VirtualList[T]) height(index int) int {
+	if h := l.heights[index]; h > 0 {
+		return h
+	}
+	return l.opts.EstimatedHeight
+}
+
+// layout recalculates the offsets of the rows if any heights have changed.
+func (l *VirtualList[T]) layout() {
+	if !l.dirty && len(l.offsets) == len(l.items)+1 {
+		return
+	}
+	if cap(l.offsets) >= len(l.items)+1 {
+		l.offsets = l.offsets[:len(l.items)+1]
+	} else {
+		l.offsets = make([]int, len(l.items)+1)
+	}
+	l.offsets[0] = 0
+	for i := range l.items {
+		l.offsets[i+1] = l.offsets[i] + l.height(i)
+	}
+	l.inner.Style().Height(strconv.Itoa(l.offsets[len(l.items)]) + "px")
+	for i, row := range l.active {
+		row.Style().Top(strconv.Itoa(l.offsets[i]) + "px")
+	}
+	l.dirty = false
+}
+
+func (l *VirtualList[T]) render() {
+	l.layout()
+	if len(l.items) == 0 {
+		l.recycleAll()
+		return
+	}
+
+	var viewport = l.root.ClientHeight()
+	if viewport == 0 {
+		// Not yet in the DOM, render enough rows to fill the window.
+		viewport = jsext.Window.Get("innerHeight").Int()
+	}
+	var scrollTop = l.root.ScrollTop()
+	var start = l.IndexAt(scrollTop) - l.opts.Overscan
+	var end = l.IndexAt(scrollTop+viewport) + 1 + l.opts.Overscan
+	if start < 0 {
+		start = 0
+	}
+	if end > len(l.items) {
+		end = len(l.items)
+	}
+
+	for i, row := range l.active {
+		if i < start || i >= end {
+			l.recycle(i, row)
+		}
+	}
+
+	for i := start; i < end; i++ {
+		if _, ok := l.active[i]; ok {
+			continue
+		}
+		var row = l.acquire()
+		l.fill(row, i)
+		row.Style().Top(strconv.Itoa(l.offsets[i]) + "px")
+		l.active[i] = row
+		l.inner.AppendChild(row)
+	}
+
+	l.measure()
+}
+
+// measure caches the heights of the rendered rows.
+func (l *VirtualList[T]) measure() {
+	for i, row := range l.active {
+		var h = row.Get("offsetHeight").Int()
+		if h > 0 && h != l.heights[i] {
+			l.heights[i] = h
+			l.dirty = true
+		}
+	}
+	l.layout()
+}
+
+func (l *VirtualList[T]) fill(row *jse.Element, index int) {
+	row.ClearInnerHTML()
+	row.Dataset().Set("index", index)
+	l.opts.Render(row, l.items[index], index)
+}
+
+func (l *VirtualList[T]) acquire() *jse.Element {
+	if len(l.pool) > 0 {
+		var row = l.pool[len(l.pool)-1]
+		l.pool = l.pool[:len(l.pool)-1]
+		return row
+	}
+	return jse.Div(l.class("virtuallist-row"))
+}
+
+func (l *VirtualList[T]) recycle(index int, row *jse.Element) {
+	delete(l.active, index)
+	row.Remove()
+	l.pool = append(l.pool, row)
+}
+
+func (l *VirtualList[T]) recycleAll() {
+	for i, row := range l.active {
+		l.recycle(i, row)
+	}
+}
@@ -1,16 +1,13 @@
-//go:build js && wasm
-// +build js,wasm
-
 package jse
 
 import (
 	"regexp"
 	"strconv"
 	"strings"
-	"syscall/js"
 	"time"
 
 	"github.com/Nigel2392/jsext/v2"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 func A(href string, text ...string) *Element {
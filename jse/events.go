@@ -1,115 +1,108 @@
 package jse
 
 import (
-	"syscall/js"
 	"time"
 
 	"github.com/Nigel2392/jsext/v2"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 // OnClick adds an event listener to the Element
-func (e *Element) OnClick(callback func(this *Element, event jsext.Event)) js.Func {
-	return e.AddEventListener("click", func(e *Element, event jsext.Event) {
-		callback(e, event)
-	})
+func (e *Element) OnClick(callback func(this *Element, event jsext.Event), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.AddEventListener("click", callback, opts...)
 }
 
 // OnChange adds an event listener to the Element
-func (e *Element) OnChange(callback func(this *Element, event jsext.Event)) js.Func {
-	return e.AddEventListener("change", func(e *Element, event jsext.Event) {
-		callback(e, event)
-	})
+func (e *Element) OnChange(callback func(this *Element, event jsext.Event), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.AddEventListener("change", callback, opts...)
 }
 
 // OnKeyUp adds an event listener to the Element
-func (e *Element) OnKeyUp(callback func(this *Element, event jsext.Event)) js.Func {
-	return e.AddEventListener("keyup", func(e *Element, event jsext.Event) {
-		callback(e, event)
-	})
+func (e *Element) OnKeyUp(callback func(this *Element, event jsext.Event), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.AddEventListener("keyup", callback, opts...)
 }
 
 // OnKeyDown adds an event listener to the Element
-func (e *Element) OnKeyDown(callback func(this *Element, event jsext.Event)) js.Func {
-	return e.AddEventListener("keydown", func(e *Element, event jsext.Event) {
-		callback(e, event)
-	})
+func (e *Element) OnKeyDown(callback func(this *Element, event jsext.Event), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.AddEventListener("keydown", callback, opts...)
 }
 
 // OnKeyPress adds an event listener to the Element
-func (e *Element) OnKeyPress(callback func(this *Element, event jsext.Event)) js.Func {
-	return e.AddEventListener("keypress", func(e *Element, event jsext.Event) {
-		callback(e, event)
-	})
+func (e *Element) OnKeyPress(callback func(this *Element, event jsext.Event), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.AddEventListener("keypress", callback, opts...)
 }
 
 // OnFocus adds an event listener to the Element
-func (e *Element) OnFocus(callback func(this *Element, event jsext.Event)) js.Func {
-	return e.AddEventListener("focus", func(e *Element, event jsext.Event) {
-		callback(e, event)
-	})
+func (e *Element) OnFocus(callback func(this *Element, event jsext.Event), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.AddEventListener("focus", callback, opts...)
 }
 
 // OnBlur adds an event listener to the Element
-func (e *Element) OnBlur(callback func(this *Element, event jsext.Event)) js.Func {
-	return e.AddEventListener("blur", func(e *Element, event jsext.Event) {
-		callback(e, event)
-	})
+func (e *Element) OnBlur(callback func(this *Element, event jsext.Event), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.AddEventListener("blur", callback, opts...)
 }
 
 // OnMouseDown adds an event listener to the Element
-func (e *Element) OnMouseDown(callback func(this *Element, event jsext.Event)) js.Func {
-	return e.AddEventListener("mousedown", func(e *Element, event jsext.Event) {
-		callback(e, event)
-	})
+func (e *Element) OnMouseDown(callback func(this *Element, event jsext.Event), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.AddEventListener("mousedown", callback, opts...)
 }
 
 // OnMouseUp adds an event listener to the Element
-func (e *Element) OnMouseUp(callback func(this *Element, event jsext.Event)) js.Func {
-	return e.AddEventListener("mouseup", func(e *Element, event jsext.Event) {
-		callback(e, event)
-	})
+func (e *Element) OnMouseUp(callback func(this *Element, event jsext.Event), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.AddEventListener("mouseup", callback, opts...)
 }
 
 // OnMouseOver adds an event listener to the Element
-func (e *Element) OnMouseOver(callback func(this *Element, event jsext.Event)) js.Func {
-	return e.AddEventListener("mouseover", func(e *Element, event jsext.Event) {
-		callback(e, event)
-	})
+func (e *Element) OnMouseOver(callback func(this *Element, event jsext.Event), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.AddEventListener("mouseover", callback, opts...)
 }
 
 // OnMouseOut adds an event listener to the Element
-func (e *Element) OnMouseOut(callback func(this *Element, event jsext.Event)) js.Func {
-	return e.AddEventListener("mouseout", func(e *Element, event jsext.Event) {
-		callback(e, event)
-	})
+func (e *Element) OnMouseOut(callback func(this *Element, event jsext.Event), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.AddEventListener("mouseout", callback, opts...)
 }
 
 // OnMouseMove adds an event listener to the Element
-func (e *Element) OnMouseMove(callback func(this *Element, event jsext.Event)) js.Func {
-	return e.AddEventListener("mousemove", func(e *Element, event jsext.Event) {
-		callback(e, event)
-	})
+func (e *Element) OnMouseMove(callback func(this *Element, event jsext.Event), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.AddEventListener("mousemove", callback, opts...)
 }
 
 // OnMouseEnter adds an event listener to the Element
-func (e *Element) OnMouseEnter(callback func(this *Element, event jsext.Event)) js.Func {
-	return e.AddEventListener("mouseenter", func(e *Element, event jsext.Event) {
-		callback(e, event)
-	})
+func (e *Element) OnMouseEnter(callback func(this *Element, event jsext.Event), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.AddEventListener("mouseenter", callback, opts...)
 }
 
 // OnMouseLeave adds an event listener to the Element
-func (e *Element) OnMouseLeave(callback func(this *Element, event jsext.Event)) js.Func {
-	return e.AddEventListener("mouseleave", func(e *Element, event jsext.Event) {
-		callback(e, event)
-	})
+func (e *Element) OnMouseLeave(callback func(this *Element, event jsext.Event), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.AddEventListener("mouseleave", callback, opts...)
 }
 
 // OnScroll adds an event listener to the Element
-func (e *Element) OnScroll(callback func(this *Element, event jsext.Event)) js.Func {
-	return e.AddEventListener("scroll", func(e *Element, event jsext.Event) {
-		callback(e, event)
-	})
+func (e *Element) OnScroll(callback func(this *Element, event jsext.Event), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.AddEventListener("scroll", callback, opts...)
+}
+
+// Delegate adds a single event listener to the Element, which calls the handler
+// for events on descendants matching the CSS selector.
+//
+// The handler receives the element matched with closest() from the event target.
+// Events which do not bubble, like focus and blur, require the Capture option.
+func (e *Element) Delegate(event, selector string, handler func(matched *Element, event jsext.Event), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.AddEventListener(event, func(this *Element, ev jsext.Event) {
+		var target = ev.Target().Value()
+		if !target.IsNull() && !target.IsUndefined() && target.Get("nodeType").Int() != 1 {
+			// Text nodes do not have closest.
+			target = target.Get("parentElement")
+		}
+		if target.IsNull() || target.IsUndefined() {
+			return
+		}
+		var matched = target.Call("closest", selector)
+		if matched.IsNull() || !this.JSValue().Call("contains", matched).Bool() {
+			return
+		}
+		handler((*Element)(&matched), ev)
+	}, opts...)
 }
 
 type debouncer struct {
@@ -202,7 +195,7 @@ func (e *Element) OnHoldClick(wait time.Duration, repeat time.Duration, f func(t
 // callEach is the minimum time between each function call
 //
 // pxFromBottom is the number of pixels from the bottom of the element to call the function
-func (e *Element) OnScrolledToBottom(callEach time.Duration, pxFromBottom int, f func(*Element, jsext.Event)) js.Func {
+func (e *Element) OnScrolledToBottom(callEach time.Duration, pxFromBottom int, f func(*Element, jsext.Event)) *jsext.Listener {
 	var lastCall = time.Time{}
 	return e.AddEventListener("scroll", func(this *Element, event jsext.Event) {
 		if time.Since(lastCall) < callEach {
@@ -2,9 +2,9 @@ package jse
 
 import (
 	"net/url"
-	"syscall/js"
 
 	"github.com/Nigel2392/jsext/v2"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 type FormElement Element
@@ -107,15 +107,15 @@ func (e *FormElement) DelAttr(key string) *FormElement {
 // OnSubmit sets the onsubmit event handler.
 //
 // This function will do nothing if the element on which this was called is not a html form.
-func (e *FormElement) OnSubmit(f func(this *Element, event jsext.Event, v url.Values)) js.Func {
+func (e *FormElement) OnSubmit(f func(this *Element, event jsext.Event, v url.Values), opts ...jsext.ListenerOptions) *jsext.Listener {
 
 	var nodeName = e.Element().Get("nodeName")
 	if nodeName.IsUndefined() || nodeName.IsNull() {
-		return js.Func{Value: js.Null()}
+		return nil
 	}
 
 	if nodeName.String() != "FORM" {
-		return js.Func{Value: js.Null()}
+		return nil
 	}
 
 	var newF = func(this *Element, event jsext.Event) {
@@ -138,7 +138,7 @@ func (e *FormElement) OnSubmit(f func(this *Element, event jsext.Event, v url.Va
 		f(this, event, formValues)
 	}
 
-	return e.Element().AddEventListener("submit", newF)
+	return e.Element().AddEventListener("submit", newF, opts...)
 }
 
 // Reset resets the form.
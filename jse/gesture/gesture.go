@@ -0,0 +1,476 @@
+// Package gesture recognizes drag, pan, pinch, swipe, tap, double tap and long press gestures
+// from pointer events, so mouse, pen and touch input are handled the same way.
+//
+//	gesture.New(canvasElement, gesture.Options{Canvas: true}).
+//		OnDrag(func(d gesture.Drag) { ... }).
+//		OnPinch(func(p gesture.Pinch) { zoom *= p.Delta })
+package gesture
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Nigel2392/jsext/v2"
+	"github.com/Nigel2392/jsext/v2/jse"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+type Phase int
+
+const (
+	Start Phase = iota + 1
+	Move
+	End
+	// Cancel ends a gesture when the browser cancels the pointer, for example to scroll.
+	Cancel
+)
+
+type Direction int
+
+const (
+	Left Direction = iota + 1
+	Right
+	Up
+	Down
+)
+
+func (d Direction) String() string {
+	switch d {
+	case Left:
+		return "left"
+	case Right:
+		return "right"
+	case Up:
+		return "up"
+	case Down:
+		return "down"
+	}
+	return ""
+}
+
+// Point is a position relative to the target element.
+type Point struct {
+	X, Y float64
+}
+
+func (p Point) Sub(o Point) Point {
+	return Point{p.X - o.X, p.Y - o.Y}
+}
+
+func (p Point) Len() float64 {
+	return math.Hypot(p.X, p.Y)
+}
+
+// Drag is a single pointer moving past the drag threshold.
+type Drag struct {
+	Phase Phase
+	Start Point
+	// Current is the current position, Current - Start is the total distance dragged.
+	Current Point
+	// Delta is the distance since the last event.
+	Delta Point
+	// Event is the last event of the dragging pointer.
+	Event jse.PointerEvent
+}
+
+// Pan is two or more pointers moving together.
+type Pan struct {
+	Phase  Phase
+	Center Point
+	Delta  Point
+	Total  Point
+}
+
+// Pinch is the distance between two pointers changing.
+type Pinch struct {
+	Phase  Phase
+	Center Point
+	// Scale is relative to the start of the pinch.
+	Scale float64
+	// Delta is the change in scale since the last event, multiply a zoom level by it.
+	Delta float64
+}
+
+// Swipe is a fast drag which ended.
+type Swipe struct {
+	Direction Direction
+	Start     Point
+	End       Point
+	// Velocity in pixels per millisecond.
+	Velocity float64
+}
+
+// Tap is a pointer which was released close to where it was pressed.
+type Tap struct {
+	Point
+	PointerType string
+}
+
+type Options struct {
+	// DragThreshold is the distance in pixels a pointer must move before a drag starts.
+	DragThreshold float64
+	// TapDistance is the maximum distance a pointer can move during a tap, and between the taps of a double tap.
+	TapDistance float64
+	LongPress   time.Duration
+	DoubleTap   time.Duration
+	// SwipeDistance and SwipeVelocity (pixels per millisecond) are the minimums for a drag to be a swipe.
+	SwipeDistance float64
+	SwipeVelocity float64
+	// Canvas scales positions to the pixels of the canvas element, see jse.PointerEvent.CanvasPosition.
+	Canvas bool
+	// TouchAction is set as the touch-action of the target, "none" by default so touches do not scroll the page.
+	TouchAction string
+}
+
+func (o *Options) Defaults() {
+	if o.DragThreshold == 0 {
+		o.DragThreshold = 5
+	}
+	if o.TapDistance == 0 {
+		o.TapDistance = 10
+	}
+	if o.LongPress == 0 {
+		o.LongPress = 500 * time.Millisecond
+	}
+	if o.DoubleTap == 0 {
+		o.DoubleTap = 300 * time.Millisecond
+	}
+	if o.SwipeDistance == 0 {
+		o.SwipeDistance = 30
+	}
+	if o.SwipeVelocity == 0 {
+		o.SwipeVelocity = 0.3
+	}
+	if o.TouchAction == "" {
+		o.TouchAction = "none"
+	}
+}
+
+type pointer struct {
+	id          int
+	typ         string
+	start, last Point
+	time        float64
+	// event is the last event of the pointer.
+	event jse.PointerEvent
+}
+
+// Recognizer recognizes gestures on an element, handlers are set with the On methods.
+type Recognizer struct {
+	target *jse.Element
+	opts   Options
+
+	pointers map[int]*pointer
+	dragging bool
+	multi    bool
+	// gestured is set when a gesture happened, so releasing the pointers is not a tap.
+	gestured bool
+
+	startDistance float64
+	lastScale     float64
+	startCenter   Point
+	lastCenter    Point
+
+	longPress   js.Value
+	longPressFn js.Func
+	lastTap     float64
+	lastTapAt   Point
+
+	listeners   []*jsext.Listener
+	touchAction string
+
+	onDrag      func(Drag)
+	onPan       func(Pan)
+	onPinch     func(Pinch)
+	onSwipe     func(Swipe)
+	onTap       func(Tap)
+	onDoubleTap func(Tap)
+	onLongPress func(Tap)
+}
+
+func New(target *jse.Element, opts ...Options) *Recognizer {
+	var r = &Recognizer{
+		target:   target,
+		pointers: make(map[int]*pointer),
+		lastTap:  math.Inf(-1),
+	}
+	if len(opts) > 0 {
+		r.opts = opts[0]
+	}
+	r.opts.Defaults()
+
+	var style = target.JSValue().Get("style")
+	r.touchAction = style.Get("touchAction").String()
+	style.Set("touchAction", r.opts.TouchAction)
+
+	r.listeners = append(r.listeners,
+		target.OnPointerDown(func(this *jse.Element, e jse.PointerEvent) { r.down(e) }),
+		target.OnPointerMove(func(this *jse.Element, e jse.PointerEvent) { r.move(e) }),
+		target.OnPointerUp(func(this *jse.Element, e jse.PointerEvent) { r.up(e, false) }),
+		target.OnPointerCancel(func(this *jse.Element, e jse.PointerEvent) { r.up(e, true) }),
+	)
+	return r
+}
+
+func (r *Recognizer) OnDrag(f func(Drag)) *Recognizer     { r.onDrag = f; return r }
+func (r *Recognizer) OnPan(f func(Pan)) *Recognizer       { r.onPan = f; return r }
+func (r *Recognizer) OnPinch(f func(Pinch)) *Recognizer   { r.onPinch = f; return r }
+func (r *Recognizer) OnSwipe(f func(Swipe)) *Recognizer   { r.onSwipe = f; return r }
+func (r *Recognizer) OnTap(f func(Tap)) *Recognizer       { r.onTap = f; return r }
+func (r *Recognizer) OnLongPress(f func(Tap)) *Recognizer { r.onLongPress = f; return r }
+
+// OnDoubleTap sets the handler of double taps, the first tap is also passed to OnTap.
+func (r *Recognizer) OnDoubleTap(f func(Tap)) *Recognizer { r.onDoubleTap = f; return r }
+
+// Destroy removes the listeners, and restores the touch-action of the target.
+func (r *Recognizer) Destroy() {
+	for _, l := range r.listeners {
+		l.Remove()
+	}
+	r.listeners = nil
+	r.cancelLongPress()
+	if !r.longPressFn.IsUndefined() {
+		r.longPressFn.Release()
+	}
+	r.target.JSValue().Get("style").Set("touchAction", r.touchAction)
+}
+
+func (r *Recognizer) position(e jse.PointerEvent) Point {
+	var x, y = jse.RelativePosition(r.target.JSValue(), e.ClientX(), e.ClientY(), r.opts.Canvas)
+	return Point{x, y}
+}
+
+// ordered returns the pointers in the order they were pressed.
+func (r *Recognizer) ordered() []*pointer {
+	var list = make([]*pointer, 0, len(r.pointers))
+	for _, p := range r.pointers {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].time < list[j].time || list[i].time == list[j].time && list[i].id < list[j].id
+	})
+	return list
+}
+
+func (r *Recognizer) down(e jse.PointerEvent) {
+	if e.PointerType() == "mouse" && e.Button() != 0 {
+		return
+	}
+	var p = &pointer{
+		id:    e.PointerID(),
+		typ:   e.PointerType(),
+		start: r.position(e),
+		time:  e.Get("timeStamp").Float(),
+		event: e,
+	}
+	p.last = p.start
+	r.pointers[p.id] = p
+	r.target.SetPointerCapture(p.id)
+
+	switch len(r.pointers) {
+	case 1:
+		r.startLongPress(p)
+	case 2:
+		r.cancelLongPress()
+		if r.dragging {
+			// The second pointer ends the drag of the first one.
+			for _, other := range r.pointers {
+				if other != p {
+					r.endDrag(other, End)
+				}
+			}
+		}
+		r.startMulti()
+	}
+}
+
+func (r *Recognizer) move(e jse.PointerEvent) {
+	var p, ok = r.pointers[e.PointerID()]
+	if !ok {
+		return
+	}
+	var current = r.position(e)
+	var last = p.last
+	p.last, p.event = current, e
+
+	if r.multi {
+		r.moveMulti()
+		return
+	}
+	if !r.dragging {
+		if current.Sub(p.start).Len() < r.opts.DragThreshold {
+			return
+		}
+		r.dragging, r.gestured = true, true
+		r.cancelLongPress()
+		r.drag(Drag{Phase: Start, Start: p.start, Current: current, Delta: current.Sub(p.start), Event: e})
+		return
+	}
+	r.drag(Drag{Phase: Move, Start: p.start, Current: current, Delta: current.Sub(last), Event: e})
+}
+
+func (r *Recognizer) up(e jse.PointerEvent, canceled bool) {
+	var p, ok = r.pointers[e.PointerID()]
+	if !ok {
+		return
+	}
+	p.last, p.event = r.position(e), e
+	var now = e.Get("timeStamp").Float()
+
+	switch {
+	case r.multi:
+		delete(r.pointers, p.id)
+		if len(r.pointers) < 2 {
+			r.endMulti(canceled)
+		} else {
+			r.startMulti()
+		}
+	case r.dragging:
+		delete(r.pointers, p.id)
+		var phase = End
+		if canceled {
+			phase = Cancel
+		}
+		r.endDrag(p, phase)
+		var moved = p.last.Sub(p.start)
+		var velocity = moved.Len() / math.Max(now-p.time, 1)
+		if !canceled && r.onSwipe != nil && moved.Len() >= r.opts.SwipeDistance && velocity >= r.opts.SwipeVelocity {
+			r.onSwipe(Swipe{Direction: direction(moved), Start: p.start, End: p.last, Velocity: velocity})
+		}
+	default:
+		delete(r.pointers, p.id)
+		r.cancelLongPress()
+		if !canceled && !r.gestured {
+			r.tap(p, now)
+		}
+	}
+	if len(r.pointers) == 0 {
+		r.gestured = false
+	}
+}
+
+func (r *Recognizer) tap(p *pointer, now float64) {
+	if p.last.Sub(p.start).Len() > r.opts.TapDistance {
+		return
+	}
+	var t = Tap{Point: p.last, PointerType: p.typ}
+	if r.onDoubleTap != nil && now-r.lastTap <= float64(r.opts.DoubleTap.Milliseconds()) && p.last.Sub(r.lastTapAt).Len() <= 2*r.opts.TapDistance {
+		r.lastTap = math.Inf(-1)
+		r.onDoubleTap(t)
+		return
+	}
+	r.lastTap, r.lastTapAt = now, p.last
+	if r.onTap != nil {
+		r.onTap(t)
+	}
+}
+
+func (r *Recognizer) drag(d Drag) {
+	if r.onDrag != nil {
+		r.onDrag(d)
+	}
+}
+
+// endDrag ends the drag of the pointer, which may already have been released.
+func (r *Recognizer) endDrag(p *pointer, phase Phase) {
+	r.dragging = false
+	r.drag(Drag{Phase: phase, Start: p.start, Current: p.last, Event: p.event})
+}
+
+func (r *Recognizer) center() (Point, float64) {
+	var list = r.ordered()
+	var a, b = list[0].last, list[1].last
+	return Point{(a.X + b.X) / 2, (a.Y + b.Y) / 2}, b.Sub(a).Len()
+}
+
+func (r *Recognizer) startMulti() {
+	var center, distance = r.center()
+	var restart = r.multi
+	r.multi, r.gestured = true, true
+	r.startCenter, r.lastCenter = center, center
+	r.startDistance, r.lastScale = distance, 1
+	if restart {
+		// A third pointer was released, the remaining pair continues the gesture.
+		return
+	}
+	if r.onPinch != nil {
+		r.onPinch(Pinch{Phase: Start, Center: center, Scale: 1, Delta: 1})
+	}
+	if r.onPan != nil {
+		r.onPan(Pan{Phase: Start, Center: center})
+	}
+}
+
+func (r *Recognizer) moveMulti() {
+	var center, distance = r.center()
+	var scale = 1.0
+	if r.startDistance > 0 {
+		scale = distance / r.startDistance
+	}
+	if r.onPinch != nil {
+		r.onPinch(Pinch{Phase: Move, Center: center, Scale: scale, Delta: scale / r.lastScale})
+	}
+	if r.onPan != nil {
+		r.onPan(Pan{Phase: Move, Center: center, Delta: center.Sub(r.lastCenter), Total: center.Sub(r.startCenter)})
+	}
+	r.lastScale, r.lastCenter = scale, center
+}
+
+func (r *Recognizer) endMulti(canceled bool) {
+	r.multi = false
+	var phase = End
+	if canceled {
+		phase = Cancel
+	}
+	if r.onPinch != nil {
+		r.onPinch(Pinch{Phase: phase, Center: r.lastCenter, Scale: r.lastScale, Delta: 1})
+	}
+	if r.onPan != nil {
+		r.onPan(Pan{Phase: phase, Center: r.lastCenter, Total: r.lastCenter.Sub(r.startCenter)})
+	}
+	// The remaining pointer starts from where it is, so it does not jump into a drag.
+	for _, p := range r.pointers {
+		p.start = p.last
+	}
+}
+
+func (r *Recognizer) startLongPress(p *pointer) {
+	if r.onLongPress == nil {
+		return
+	}
+	if r.longPressFn.IsUndefined() {
+		r.longPressFn = js.FuncOf(func(this js.Value, args []js.Value) any {
+			r.longPress = js.Undefined()
+			var list = r.ordered()
+			if len(list) != 1 || r.dragging {
+				return nil
+			}
+			r.gestured = true
+			r.onLongPress(Tap{Point: list[0].last, PointerType: list[0].typ})
+			return nil
+		})
+	}
+	r.cancelLongPress()
+	r.longPress = js.Global().Call("setTimeout", r.longPressFn, r.opts.LongPress.Milliseconds())
+}
+
+func (r *Recognizer) cancelLongPress() {
+	if !r.longPress.IsUndefined() {
+		js.Global().Call("clearTimeout", r.longPress)
+		r.longPress = js.Undefined()
+	}
+}
+
+func direction(d Point) Direction {
+	if math.Abs(d.X) >= math.Abs(d.Y) {
+		if d.X < 0 {
+			return Left
+		}
+		return Right
+	}
+	if d.Y < 0 {
+		return Up
+	}
+	return Down
+}
@@ -1,6 +1,3 @@
-//go:build js && wasm
-// +build js,wasm
-
 package jse
 
 import "github.com/Nigel2392/jsext/v2"
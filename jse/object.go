@@ -2,10 +2,11 @@ package jse
 
 import (
 	"strings"
-	"syscall/js"
 
 	"github.com/Nigel2392/jsext/v2"
+	"github.com/Nigel2392/jsext/v2/dom"
 	"github.com/Nigel2392/jsext/v2/errs"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 // AUTO_KEY is a function that returns a random string.
@@ -217,6 +218,14 @@ func (e *Element) InnerHTML(s string) *Element {
 	return e
 }
 
+// SafeInnerHTML sanitizes the HTML and sets it as the inner HTML of the Element.
+//
+// If no policy is given, dom.DefaultPolicy is used.
+func (e *Element) SafeInnerHTML(s string, policy ...*dom.Policy) *Element {
+	e.Element().SafeInnerHTML(s, policy...)
+	return e
+}
+
 func (e *Element) InnerText(s string) *Element {
 	e.Element().InnerText(s)
 	return e
@@ -235,6 +244,11 @@ func (e *Element) Style() jsext.Style {
 	return e.Element().Style()
 }
 
+// ComputedStyle returns the computed style of the element, or of a pseudo element like "::before".
+func (e *Element) ComputedStyle(pseudo ...string) jsext.ComputedStyle {
+	return e.Element().ComputedStyle(pseudo...)
+}
+
 func (e *Element) SetAttrMap(m map[string]string) *Element {
 	for k, v := range m {
 		e.SetAttr(k, v)
@@ -299,26 +313,25 @@ func (e *Element) GetElementsByTagName(tagName string) *Element {
 
 // Add an event listener to the Element
 //
-// This will return the function that was added to the element.
-func (e *Element) AddEventListener(event string, callback func(this *Element, event jsext.Event)) js.Func {
-	if e == nil {
-		return js.Func{Value: js.Null()}
-	}
-	if callback == nil {
-		return js.Func{Value: js.Null()}
-	}
-
-	var f = js.FuncOf(func(_ js.Value, args []js.Value) interface{} {
-		if len(args) < 1 {
-			return nil
-		}
-		callback(e, jsext.Event(args[0]))
+// This will return a handle which can be used to remove the listener.
+func (e *Element) AddEventListener(event string, callback func(this *Element, event jsext.Event), opts ...jsext.ListenerOptions) *jsext.Listener {
+	if e == nil || callback == nil {
 		return nil
-	})
+	}
+	return jsext.Listen(e.JSValue(), event, func(_ js.Value, event jsext.Event) {
+		callback(e, event)
+	}, opts...)
+}
 
-	e.JSValue().Call("addEventListener", event, f)
+// RemoveEventListener removes all listeners for the event which were added with AddEventListener.
+func (e *Element) RemoveEventListener(event string) *Element {
+	e.Listeners().Remove(event)
+	return e
+}
 
-	return f
+// Listeners returns the registry of listeners added to the Element.
+func (e *Element) Listeners() *jsext.Listeners {
+	return jsext.ListenersOf(e.JSValue())
 }
 
 // Get the scroll height of the Element
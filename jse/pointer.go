@@ -0,0 +1,135 @@
+package jse
+
+import (
+	"github.com/Nigel2392/jsext/v2"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+// PointerEvent is a pointer event from a mouse, pen or touch.
+type PointerEvent struct {
+	jsext.Event
+}
+
+func (p PointerEvent) PointerID() int {
+	return p.Get("pointerId").Int()
+}
+
+// PointerType is "mouse", "pen" or "touch".
+func (p PointerEvent) PointerType() string {
+	return p.Get("pointerType").String()
+}
+
+func (p PointerEvent) IsPrimary() bool {
+	return p.Get("isPrimary").Bool()
+}
+
+func (p PointerEvent) ClientX() float64 {
+	return p.Get("clientX").Float()
+}
+
+func (p PointerEvent) ClientY() float64 {
+	return p.Get("clientY").Float()
+}
+
+// Button is the button which changed, 0 is the main button.
+func (p PointerEvent) Button() int {
+	return p.Get("button").Int()
+}
+
+// Buttons is a bitmask of the buttons which are pressed.
+func (p PointerEvent) Buttons() int {
+	return p.Get("buttons").Int()
+}
+
+// Pressure is between 0 and 1, it is 0.5 for mouse buttons which are pressed.
+func (p PointerEvent) Pressure() float64 {
+	return p.Get("pressure").Float()
+}
+
+func (p PointerEvent) Width() float64 {
+	return p.Get("width").Float()
+}
+
+func (p PointerEvent) Height() float64 {
+	return p.Get("height").Float()
+}
+
+// Position returns the position of the pointer relative to the top left corner of the element.
+func (p PointerEvent) Position(e *Element) (x, y float64) {
+	return RelativePosition(e.JSValue(), p.ClientX(), p.ClientY(), false)
+}
+
+// CanvasPosition returns the position of the pointer in pixels of the canvas.
+//
+// The position is scaled when the canvas is displayed at another size than its width and height.
+func (p PointerEvent) CanvasPosition(canvas js.Value) (x, y float64) {
+	return RelativePosition(canvas, p.ClientX(), p.ClientY(), true)
+}
+
+// RelativePosition converts client coordinates to coordinates relative to the element.
+//
+// If scaled is set, they are scaled from the displayed size to the width and height of a canvas.
+func RelativePosition(element js.Value, clientX, clientY float64, scaled bool) (x, y float64) {
+	var rect = element.Call("getBoundingClientRect")
+	x, y = clientX-rect.Get("left").Float(), clientY-rect.Get("top").Float()
+	if !scaled {
+		return x, y
+	}
+	if w := rect.Get("width").Float(); w > 0 {
+		x *= element.Get("width").Float() / w
+	}
+	if h := rect.Get("height").Float(); h > 0 {
+		y *= element.Get("height").Float() / h
+	}
+	return x, y
+}
+
+func (e *Element) onPointer(event string, callback func(this *Element, event PointerEvent), opts []jsext.ListenerOptions) *jsext.Listener {
+	return e.AddEventListener(event, func(this *Element, ev jsext.Event) {
+		callback(this, PointerEvent{ev})
+	}, opts...)
+}
+
+// OnPointerDown adds an event listener to the Element
+func (e *Element) OnPointerDown(callback func(this *Element, event PointerEvent), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.onPointer("pointerdown", callback, opts)
+}
+
+// OnPointerMove adds an event listener to the Element
+func (e *Element) OnPointerMove(callback func(this *Element, event PointerEvent), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.onPointer("pointermove", callback, opts)
+}
+
+// OnPointerUp adds an event listener to the Element
+func (e *Element) OnPointerUp(callback func(this *Element, event PointerEvent), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.onPointer("pointerup", callback, opts)
+}
+
+// OnPointerCancel adds an event listener to the Element
+func (e *Element) OnPointerCancel(callback func(this *Element, event PointerEvent), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.onPointer("pointercancel", callback, opts)
+}
+
+// OnPointerEnter adds an event listener to the Element
+func (e *Element) OnPointerEnter(callback func(this *Element, event PointerEvent), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.onPointer("pointerenter", callback, opts)
+}
+
+// OnPointerLeave adds an event listener to the Element
+func (e *Element) OnPointerLeave(callback func(this *Element, event PointerEvent), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return e.onPointer("pointerleave", callback, opts)
+}
+
+// SetPointerCapture sends the following events of the pointer to the Element,
+// even when the pointer leaves it, until the pointer is released.
+func (e *Element) SetPointerCapture(pointerID int) {
+	e.JSValue().Call("setPointerCapture", pointerID)
+}
+
+func (e *Element) ReleasePointerCapture(pointerID int) {
+	e.JSValue().Call("releasePointerCapture", pointerID)
+}
+
+func (e *Element) HasPointerCapture(pointerID int) bool {
+	return e.JSValue().Call("hasPointerCapture", pointerID).Bool()
+}
@@ -26,9 +26,8 @@ func (s *SelectElement) ClassList(cls ...string) jsext.Value {
 	return s.Element().ClassList(cls...)
 }
 
-func (s *SelectElement) OnChange(listener func(this *Element, event jsext.Event)) *SelectElement {
-	s.Element().AddEventListener("change", listener)
-	return s
+func (s *SelectElement) OnChange(listener func(this *Element, event jsext.Event), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return s.Element().AddEventListener("change", listener, opts...)
 }
 
 func (s *SelectElement) Append(child any) *SelectElement {
@@ -74,9 +73,11 @@ func (s *SelectElement) Set(name string, value any) *SelectElement {
 	return s
 }
 
-func (s *SelectElement) AddEventListener(name string, listener func(this *Element, event jsext.Event)) *SelectElement {
-	s.Element().AddEventListener(name, listener)
-	return s
+// AddEventListener adds an event listener to the select element.
+//
+// The returned Listener can be used to remove it again.
+func (s *SelectElement) AddEventListener(name string, listener func(this *Element, event jsext.Event), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return s.Element().AddEventListener(name, listener, opts...)
 }
 
 type OptionElement jsext.Element
@@ -0,0 +1,91 @@
+package jse
+
+import (
+	"time"
+
+	"github.com/Nigel2392/jsext/v2"
+)
+
+// Transition is played when an element is mounted or removed.
+type Transition struct {
+	Enter []jsext.Keyframe
+	// Leave defaults to the enter keyframes played in reverse.
+	Leave   []jsext.Keyframe
+	Options jsext.AnimationOptions
+}
+
+// Built-in transitions.
+var (
+	Fade = Transition{
+		Enter: []jsext.Keyframe{
+			{Properties: jsext.StyleMap{"opacity": "0"}},
+			{Properties: jsext.StyleMap{"opacity": "1"}},
+		},
+		Options: jsext.AnimationOptions{Duration: 200 * time.Millisecond, Easing: "ease"},
+	}
+	Scale = Transition{
+		Enter: []jsext.Keyframe{
+			{Properties: jsext.StyleMap{"opacity": "0", "transform": "scale(0.95)"}},
+			{Properties: jsext.StyleMap{"opacity": "1", "transform": "scale(1)"}},
+		},
+		Options: jsext.AnimationOptions{Duration: 200 * time.Millisecond, Easing: "ease-out"},
+	}
+	SlideDown = Transition{
+		Enter: []jsext.Keyframe{
+			{Properties: jsext.StyleMap{"opacity": "0", "transform": "translateY(-10px)"}},
+			{Properties: jsext.StyleMap{"opacity": "1", "transform": "translateY(0)"}},
+		},
+		Options: jsext.AnimationOptions{Duration: 250 * time.Millisecond, Easing: "ease-out"},
+	}
+)
+
+func (t Transition) options(leave bool) jsext.AnimationOptions {
+	var opts = t.Options
+	if leave && len(t.Leave) == 0 {
+		opts.Direction = "reverse"
+	}
+	if jsext.PrefersReducedMotion() {
+		opts.Duration = 0
+		opts.Delay = 0
+	}
+	return opts
+}
+
+// Enter plays the enter transition, use it right after the element has been mounted.
+func (e *Element) Enter(t Transition) *jsext.Animation {
+	return e.Element().AnimateKeyframes(t.Enter, t.options(false))
+}
+
+// Leave plays the leave transition, and removes the element once it has finished.
+//
+// Running animations of the element are canceled first, so leaving during the enter transition works.
+func (e *Element) Leave(t Transition) *jsext.Animation {
+	var running = e.JSValue().Call("getAnimations")
+	for i := 0; i < running.Length(); i++ {
+		running.Index(i).Call("cancel")
+	}
+	var keyframes = t.Leave
+	if len(keyframes) == 0 {
+		keyframes = t.Enter
+	}
+	var opts = t.options(true)
+	opts.Fill = "forwards"
+	var a = e.Element().AnimateKeyframes(keyframes, opts)
+	var listeners jsext.Listeners
+	listeners.Add(
+		a.OnFinish(func(*jsext.Animation) {
+			listeners.Remove()
+			e.Remove()
+		}),
+		a.OnCancel(func(*jsext.Animation) {
+			listeners.Remove()
+		}),
+	)
+	return a
+}
+
+// AppendChildTransition appends the child, and plays the enter transition on it.
+func (e *Element) AppendChildTransition(child *Element, t Transition) *jsext.Animation {
+	e.AppendChild(child)
+	return child.Enter(t)
+}
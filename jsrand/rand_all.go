@@ -2,7 +2,8 @@ package jsrand
 
 import (
 	"encoding/hex"
-	"syscall/js"
+
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 // XorBytes returns a hex encoded string of the XOR of each byte in the string with the previous byte.
@@ -0,0 +1,178 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package jsv
+
+// Event phases.
+const (
+	capturingPhase = 1
+	atTarget       = 2
+	bubblingPhase  = 3
+)
+
+// eventPath returns the targets an event on target propagates through, starting at the target.
+func eventPath(target Value) []Value {
+	var n = nodeOf(target)
+	if n == nil {
+		return []Value{target}
+	}
+	var path []Value
+	for p := n; p != nil; p = p.parent {
+		path = append(path, p.value())
+	}
+	if n.root() == document() {
+		path = append(path, Global())
+	}
+	return path
+}
+
+// dispatch dispatches the event, and reports whether its default action was not prevented.
+func dispatch(target, e Value) bool {
+	var state, _ = e.o.internal.(*eventState)
+	if state == nil {
+		state = &eventState{}
+		e.o.internal = state
+	}
+	var typ = toString(e.Get("type"))
+	var path = eventPath(target)
+	e.Set("target", target)
+	e.Set("cancelBubble", false)
+	state.stopImmediate = false
+
+	for i := len(path) - 1; i > 0 && !e.Get("cancelBubble").Truthy(); i-- {
+		invokeListeners(path[i], e, state, typ, capturingPhase)
+	}
+	if !e.Get("cancelBubble").Truthy() {
+		invokeListeners(path[0], e, state, typ, atTarget)
+	}
+	if e.Get("bubbles").Truthy() {
+		for i := 1; i < len(path) && !e.Get("cancelBubble").Truthy(); i++ {
+			invokeListeners(path[i], e, state, typ, bubblingPhase)
+		}
+	}
+
+	e.Set("eventPhase", 0)
+	e.Set("currentTarget", nil)
+	flushMicrotasks()
+	return !e.Get("defaultPrevented").Truthy()
+}
+
+func invokeListeners(current, e Value, state *eventState, typ string, phase int) {
+	var list = append([]*listener(nil), current.o.events[typ]...)
+	if len(list) == 0 {
+		return
+	}
+	e.Set("currentTarget", current)
+	e.Set("eventPhase", phase)
+	// Capturing listeners are called first at the target.
+	for _, capture := range []bool{true, false} {
+		for _, l := range list {
+			if state.stopImmediate {
+				return
+			}
+			if l.removed || l.capture != capture {
+				continue
+			}
+			if (phase == capturingPhase && !l.capture) || (phase == bubblingPhase && l.capture) {
+				continue
+			}
+			if l.once {
+				removeListener(current.o, typ, func(other *listener) bool { return other == l })
+			}
+			if l.fn.t == TypeFunction {
+				l.fn.o.invoke(current, []Value{e})
+			} else if handle := l.fn.Get("handleEvent"); handle.t == TypeFunction {
+				handle.o.invoke(l.fn, []Value{e})
+			}
+		}
+	}
+}
+
+// Event interfaces, by name of their constructor.
+var eventInterfaces = []string{
+	"UIEvent", "MouseEvent", "PointerEvent", "WheelEvent", "DragEvent", "TouchEvent",
+	"KeyboardEvent", "FocusEvent", "InputEvent", "CompositionEvent", "SubmitEvent",
+	"ProgressEvent", "ErrorEvent", "MessageEvent", "AnimationEvent", "TransitionEvent",
+}
+
+// mouseDefaults are the fields of mouse events, which default to 0.
+var mouseDefaults = []string{
+	"clientX", "clientY", "screenX", "screenY", "pageX", "pageY", "offsetX", "offsetY",
+	"movementX", "movementY", "button", "buttons",
+}
+
+var pointerDefaults = map[string]any{
+	"pointerId": 0, "pointerType": "", "isPrimary": false, "pressure": 0, "width": 1, "height": 1,
+}
+
+func initEventInterfaces(g *object) {
+	for _, name := range eventInterfaces {
+		var name = name
+		var p = newObject(eventProto())
+		g.define(name, constructor(p, func(args []Value) Value {
+			var e = newEvent(p, argString(args, 0), arg(args, 1))
+			for _, key := range []string{"altKey", "ctrlKey", "metaKey", "shiftKey"} {
+				if e.Get(key).IsUndefined() {
+					e.Set(key, false)
+				}
+			}
+			switch name {
+			case "MouseEvent", "PointerEvent", "WheelEvent", "DragEvent":
+				for _, key := range mouseDefaults {
+					if e.Get(key).IsUndefined() {
+						e.Set(key, 0)
+					}
+				}
+				if name == "PointerEvent" {
+					for key, v := range pointerDefaults {
+						if e.Get(key).IsUndefined() {
+							e.Set(key, v)
+						}
+					}
+				}
+			case "KeyboardEvent":
+				for _, key := range []string{"key", "code"} {
+					if e.Get(key).IsUndefined() {
+						e.Set(key, "")
+					}
+				}
+				for _, key := range []string{"repeat", "isComposing", "ctrlKey", "altKey", "shiftKey", "metaKey"} {
+					if e.Get(key).IsUndefined() {
+						e.Set(key, false)
+					}
+				}
+			}
+			return e
+		}))
+	}
+
+	var signalProto = newObject(eventTargetProto())
+	g.define("AbortSignal", constructor(signalProto, func(args []Value) Value {
+		throw("TypeError", "Illegal constructor")
+		return Undefined()
+	}))
+	var controllerProto = newObject(objectProto())
+	controllerProto.method("abort", func(this Value, args []Value) Value {
+		var signal = this.Get("signal")
+		if signal.Get("aborted").Truthy() {
+			return Undefined()
+		}
+		signal.Set("aborted", true)
+		var reason = arg(args, 0)
+		if reason.IsUndefined() {
+			reason = Global().Get("Error").New("signal is aborted without reason")
+			reason.Set("name", "AbortError")
+		}
+		signal.Set("reason", reason)
+		dispatch(signal, Global().Get("Event").New("abort"))
+		return Undefined()
+	})
+	g.define("AbortController", constructor(controllerProto, func(args []Value) Value {
+		var c = newObject(controllerProto).value()
+		var signal = newObject(signalProto).value()
+		signal.Set("aborted", false)
+		signal.Set("reason", Undefined())
+		c.Set("signal", signal)
+		return c
+	}))
+}
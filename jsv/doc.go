@@ -0,0 +1,25 @@
+// Package jsv is the JavaScript backend used by jsext, jse, dom, css and state.
+//
+// In the browser (js && wasm) it is an alias of syscall/js.
+// On other platforms it is an in-memory JavaScript object model with a DOM,
+// so the same code can render HTML on a server or run in plain go test.
+//
+// Packages import it under the name js:
+//
+//	import js "github.com/Nigel2392/jsext/v2/jsv"
+//
+// The in-memory DOM supports event dispatch, attributes, classList, inline styles and selectors.
+// Listeners run synchronously and timers run on a fake clock, tests drive it with
+// Reset, Advance, Dispatch, Click, Input and Key:
+//
+//	js.Reset()
+//	var button = jse.Button("Save", onSave)
+//	jsext.Body.AppendChild(button.Element())
+//	js.Click(button.JSValue())
+//	js.Advance(time.Second)
+//
+// Promise reactions run when the promise settles, instead of in a microtask,
+// so Go code can wait for a promise which is settled in another goroutine.
+//
+// The in-memory DOM is not safe for concurrent use.
+package jsv
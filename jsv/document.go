@@ -0,0 +1,127 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package jsv
+
+import (
+	"strings"
+)
+
+const blankDocument = "<!DOCTYPE html><html><head></head><body></body></html>"
+
+var doc *node
+
+// document returns the global document.
+func document() *node {
+	if doc == nil {
+		doc = parseDocument(blankDocument)
+	}
+	return doc
+}
+
+// ResetDocument empties the global document, and removes the event listeners from its nodes.
+//
+// The html, head and body elements are kept, so references to them stay valid.
+func ResetDocument() {
+	var d = document()
+	var root, head, body = documentElement(d), child(documentElement(d), "head"), child(documentElement(d), "body")
+	for _, n := range []*node{d, root, head, body} {
+		if n != nil {
+			n.removeChildren()
+			n.attrs = nil
+			n.obj.events = nil
+			n.obj.props, n.obj.keys = nil, nil
+		}
+	}
+	var fresh = parseDocument(blankDocument)
+	d.insertBefore(fresh.children[0], nil)
+	root.insertBefore(head, nil)
+	root.insertBefore(body, nil)
+	d.insertBefore(root, nil)
+}
+
+func documentElement(d *node) *node {
+	for _, c := range d.children {
+		if c.kind == elementNode {
+			return c
+		}
+	}
+	return nil
+}
+
+func child(n *node, name string) *node {
+	if n == nil {
+		return nil
+	}
+	for _, c := range n.children {
+		if c.kind == elementNode && c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func documentProto() *object {
+	return proto("Document", func(o *object) {
+		var parentGet, parentSet = parentNodeProto(o)
+		accessors(o, merge(parentGet, getters{
+			"documentElement": func(n *node) Value { return nodeValue(documentElement(n)) },
+			"head":            func(n *node) Value { return nodeValue(child(documentElement(n), "head")) },
+			"body":            func(n *node) Value { return nodeValue(child(documentElement(n), "body")) },
+			"title": func(n *node) Value {
+				if title := child(child(documentElement(n), "head"), "title"); title != nil {
+					return str(strings.Join(strings.Fields(title.text()), " "))
+				}
+				return str("")
+			},
+			"doctype": func(n *node) Value {
+				for _, c := range n.children {
+					if c.kind == doctypeNode {
+						return c.value()
+					}
+				}
+				return Null()
+			},
+			"activeElement": func(n *node) Value {
+				if activeElement != nil && activeElement.root() == n {
+					return activeElement.value()
+				}
+				return nodeValue(child(documentElement(n), "body"))
+			},
+			"readyState":  func(n *node) Value { return str("complete") },
+			"defaultView": func(n *node) Value { return Global() },
+		}), merge(parentSet, setters{
+			"title": func(n *node, v Value) {
+				var head = child(documentElement(n), "head")
+				if head == nil {
+					return
+				}
+				var title = child(head, "title")
+				if title == nil {
+					title = newElement(NamespaceHTML, "title")
+					head.insertBefore(title, nil)
+				}
+				title.setText(toString(v))
+			},
+		}))
+		o.method("createElement", func(this Value, args []Value) Value {
+			return newElement(NamespaceHTML, argString(args, 0)).value()
+		})
+		o.method("createElementNS", func(this Value, args []Value) Value {
+			return newElement(nullableString(arg(args, 0)), argString(args, 1)).value()
+		})
+		o.method("createTextNode", func(this Value, args []Value) Value {
+			return newText(argString(args, 0)).value()
+		})
+		o.method("createComment", func(this Value, args []Value) Value {
+			return newComment(argString(args, 0)).value()
+		})
+		o.method("createDocumentFragment", func(this Value, args []Value) Value {
+			return newFragment().value()
+		})
+		o.method("importNode", func(this Value, args []Value) Value {
+			return mustNode(arg(args, 0), "importNode").clone(arg(args, 1).Truthy()).value()
+		})
+		o.method("getElementById", getElementById)
+	}, nodeProto)
+}
@@ -0,0 +1,378 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package jsv
+
+import (
+	"strings"
+)
+
+// Node types, as in Node.nodeType.
+const (
+	elementNode  = 1
+	textNode     = 3
+	commentNode  = 8
+	documentNode = 9
+	doctypeNode  = 10
+	fragmentNode = 11
+)
+
+// Namespaces of elements.
+const (
+	NamespaceHTML   = "http://www.w3.org/1999/xhtml"
+	NamespaceSVG    = "http://www.w3.org/2000/svg"
+	NamespaceMathML = "http://www.w3.org/1998/Math/MathML"
+)
+
+type attr struct {
+	ns, name, value string
+}
+
+// node is a DOM node, its object is what JavaScript sees.
+type node struct {
+	obj      *object
+	kind     int
+	name     string
+	ns       string
+	attrs    []attr
+	data     string
+	parent   *node
+	children []*node
+}
+
+func newNode(kind int, proto *object) *node {
+	var n = &node{kind: kind}
+	n.obj = newObject(proto)
+	n.obj.internal = n
+	return n
+}
+
+func newElement(ns, name string) *node {
+	if ns == "" || ns == NamespaceHTML {
+		ns = NamespaceHTML
+		name = strings.ToLower(name)
+	}
+	var n = newNode(elementNode, elementProto())
+	n.ns = ns
+	n.name = name
+	return n
+}
+
+func newText(data string) *node {
+	var n = newNode(textNode, textProto())
+	n.data = data
+	return n
+}
+
+func newComment(data string) *node {
+	var n = newNode(commentNode, commentProto())
+	n.data = data
+	return n
+}
+
+func newFragment() *node {
+	return newNode(fragmentNode, fragmentProto())
+}
+
+func (n *node) value() Value {
+	return n.obj.value()
+}
+
+// nodeOf returns the node behind the value, or nil.
+func nodeOf(v Value) *node {
+	if !v.t.isObject() {
+		return nil
+	}
+	var n, _ = v.o.internal.(*node)
+	return n
+}
+
+func mustNode(v Value, method string) *node {
+	var n = nodeOf(v)
+	if n == nil {
+		throw("TypeError", "Failed to execute '"+method+"': parameter is not of type 'Node'.")
+	}
+	return n
+}
+
+func nodeValue(n *node) Value {
+	if n == nil {
+		return Null()
+	}
+	return n.value()
+}
+
+func (n *node) isHTML() bool {
+	return n.kind == elementNode && n.ns == NamespaceHTML
+}
+
+func (n *node) tagName() string {
+	if n.isHTML() {
+		return strings.ToUpper(n.name)
+	}
+	return n.name
+}
+
+func (n *node) nodeName() string {
+	switch n.kind {
+	case elementNode:
+		return n.tagName()
+	case textNode:
+		return "#text"
+	case commentNode:
+		return "#comment"
+	case documentNode:
+		return "#document"
+	case fragmentNode:
+		return "#document-fragment"
+	}
+	return n.name
+}
+
+func (n *node) index() int {
+	if n.parent == nil {
+		return -1
+	}
+	for i, c := range n.parent.children {
+		if c == n {
+			return i
+		}
+	}
+	return -1
+}
+
+func (n *node) sibling(offset int, elements bool) *node {
+	var i = n.index()
+	if i < 0 {
+		return nil
+	}
+	for i += offset; i >= 0 && i < len(n.parent.children); i += offset {
+		if !elements || n.parent.children[i].kind == elementNode {
+			return n.parent.children[i]
+		}
+	}
+	return nil
+}
+
+func (n *node) elementChildren() []*node {
+	var elements []*node
+	for _, c := range n.children {
+		if c.kind == elementNode {
+			elements = append(elements, c)
+		}
+	}
+	return elements
+}
+
+func (n *node) root() *node {
+	var r = n
+	for r.parent != nil {
+		r = r.parent
+	}
+	return r
+}
+
+// contains reports whether other is n or a descendant of n.
+func (n *node) contains(other *node) bool {
+	for ; other != nil; other = other.parent {
+		if other == n {
+			return true
+		}
+	}
+	return false
+}
+
+// walk calls fn for each descendant in tree order, it stops when fn returns false.
+func (n *node) walk(fn func(*node) bool) bool {
+	for _, c := range n.children {
+		if !fn(c) || !c.walk(fn) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *node) detach() {
+	if i := n.index(); i >= 0 {
+		var p = n.parent
+		p.children = append(p.children[:i:i], p.children[i+1:]...)
+	}
+	n.parent = nil
+}
+
+// insertBefore inserts child before ref, or at the end if ref is nil.
+// Fragments are replaced by their children.
+func (n *node) insertBefore(child, ref *node) {
+	if child.contains(n) {
+		throw("HierarchyRequestError", "The new child element contains the parent.")
+	}
+	if ref != nil && ref.parent != n {
+		throw("NotFoundError", "The node before which the new node is to be inserted is not a child of this node.")
+	}
+	var nodes = []*node{child}
+	if child.kind == fragmentNode {
+		nodes = append([]*node(nil), child.children...)
+	}
+	for _, c := range nodes {
+		if c == ref {
+			ref = c.sibling(1, false)
+		}
+		c.detach()
+	}
+	var i = len(n.children)
+	if ref != nil {
+		i = ref.index()
+	}
+	var children = make([]*node, 0, len(n.children)+len(nodes))
+	children = append(children, n.children[:i]...)
+	children = append(children, nodes...)
+	children = append(children, n.children[i:]...)
+	n.children = children
+	for _, c := range nodes {
+		c.parent = n
+	}
+}
+
+func (n *node) removeChildren() {
+	for _, c := range n.children {
+		c.parent = nil
+	}
+	n.children = nil
+}
+
+func (n *node) clone(deep bool) *node {
+	var c = newNode(n.kind, n.obj.proto)
+	c.name, c.ns, c.data = n.name, n.ns, n.data
+	c.attrs = append([]attr(nil), n.attrs...)
+	if deep {
+		for _, child := range n.children {
+			var cc = child.clone(true)
+			cc.parent = c
+			c.children = append(c.children, cc)
+		}
+	}
+	return c
+}
+
+// text returns the text content of the node.
+func (n *node) text() string {
+	switch n.kind {
+	case textNode, commentNode:
+		return n.data
+	}
+	var b strings.Builder
+	n.walk(func(c *node) bool {
+		if c.kind == textNode {
+			b.WriteString(c.data)
+		}
+		return true
+	})
+	return b.String()
+}
+
+func (n *node) setText(s string) {
+	switch n.kind {
+	case textNode, commentNode:
+		n.data = s
+		return
+	}
+	n.removeChildren()
+	if s != "" {
+		n.insertBefore(newText(s), nil)
+	}
+}
+
+func (n *node) attrIndex(ns, name string) int {
+	if n.isHTML() && ns == "" {
+		name = strings.ToLower(name)
+	}
+	for i, a := range n.attrs {
+		if a.name == name && a.ns == ns {
+			return i
+		}
+	}
+	return -1
+}
+
+func (n *node) getAttr(name string) (string, bool) {
+	if i := n.attrIndex("", name); i >= 0 {
+		return n.attrs[i].value, true
+	}
+	return "", false
+}
+
+func (n *node) attr(name string) string {
+	var v, _ = n.getAttr(name)
+	return v
+}
+
+func (n *node) setAttrNS(ns, name, value string) {
+	if n.isHTML() && ns == "" {
+		name = strings.ToLower(name)
+	}
+	if i := n.attrIndex(ns, name); i >= 0 {
+		n.attrs[i].value = value
+		return
+	}
+	n.attrs = append(n.attrs, attr{ns: ns, name: name, value: value})
+}
+
+func (n *node) setAttr(name, value string) {
+	n.setAttrNS("", name, value)
+}
+
+func (n *node) removeAttrNS(ns, name string) {
+	if i := n.attrIndex(ns, name); i >= 0 {
+		n.attrs = append(n.attrs[:i:i], n.attrs[i+1:]...)
+	}
+}
+
+func (n *node) removeAttr(name string) {
+	n.removeAttrNS("", name)
+}
+
+func (n *node) classes() []string {
+	return strings.Fields(n.attr("class"))
+}
+
+func (n *node) hasClass(class string) bool {
+	for _, c := range n.classes() {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *node) elementsBy(match func(*node) bool) Value {
+	var found []Value
+	n.walk(func(c *node) bool {
+		if c.kind == elementNode && match(c) {
+			found = append(found, c.value())
+		}
+		return true
+	})
+	return arrayOf(found)
+}
+
+// toNodes converts the arguments of append, prepend, before and after to nodes,
+// strings become text nodes.
+func toNodes(args []Value) []*node {
+	var nodes = make([]*node, 0, len(args))
+	for _, a := range args {
+		if n := nodeOf(a); n != nil {
+			nodes = append(nodes, n)
+		} else {
+			nodes = append(nodes, newText(toString(a)))
+		}
+	}
+	return nodes
+}
+
+func nodeList(nodes []*node) Value {
+	var values = make([]Value, len(nodes))
+	for i, n := range nodes {
+		values[i] = n.value()
+	}
+	return arrayOf(values)
+}
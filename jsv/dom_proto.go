@@ -0,0 +1,352 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package jsv
+
+import (
+	"strings"
+)
+
+type getters map[string]func(n *node) Value
+type setters map[string]func(n *node, v Value)
+
+// accessors installs the getters and setters as hooks on a prototype of nodes.
+func accessors(o *object, get getters, set setters) {
+	o.get = func(this Value, key string) (Value, bool) {
+		var n = nodeOf(this)
+		if n == nil {
+			return Value{}, false
+		}
+		if f, ok := get[key]; ok {
+			return f(n), true
+		}
+		return Value{}, false
+	}
+	o.set = func(this Value, key string, v Value) bool {
+		var n = nodeOf(this)
+		if n == nil {
+			return false
+		}
+		if f, ok := set[key]; ok {
+			f(n, v)
+			return true
+		}
+		// Read-only accessors ignore assignments, like in sloppy mode.
+		_, ok := get[key]
+		return ok
+	}
+}
+
+func merge[M ~map[string]V, V any](maps ...M) M {
+	var m = make(M)
+	for _, mm := range maps {
+		for k, v := range mm {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+var protos = make(map[string]*object)
+
+// proto returns the named prototype, building it on first use.
+func proto(name string, build func(o *object), parent func() *object) *object {
+	if o, ok := protos[name]; ok {
+		return o
+	}
+	var o = newObject(nil)
+	protos[name] = o
+	o.proto = parent()
+	build(o)
+	return o
+}
+
+func nodeProto() *object {
+	return proto("Node", func(o *object) {
+		accessors(o, getters{
+			"nodeType": func(n *node) Value { return number(float64(n.kind)) },
+			"nodeName": func(n *node) Value { return str(n.nodeName()) },
+			"nodeValue": func(n *node) Value {
+				if n.kind == textNode || n.kind == commentNode {
+					return str(n.data)
+				}
+				return Null()
+			},
+			"textContent": func(n *node) Value {
+				if n.kind == documentNode || n.kind == doctypeNode {
+					return Null()
+				}
+				return str(n.text())
+			},
+			"parentNode": func(n *node) Value { return nodeValue(n.parent) },
+			"parentElement": func(n *node) Value {
+				if n.parent != nil && n.parent.kind == elementNode {
+					return n.parent.value()
+				}
+				return Null()
+			},
+			"childNodes": func(n *node) Value { return nodeList(n.children) },
+			"firstChild": func(n *node) Value {
+				if len(n.children) == 0 {
+					return Null()
+				}
+				return n.children[0].value()
+			},
+			"lastChild": func(n *node) Value {
+				if len(n.children) == 0 {
+					return Null()
+				}
+				return n.children[len(n.children)-1].value()
+			},
+			"previousSibling": func(n *node) Value { return nodeValue(n.sibling(-1, false)) },
+			"nextSibling":     func(n *node) Value { return nodeValue(n.sibling(1, false)) },
+			"isConnected": func(n *node) Value {
+				return boolean(n.root() == document())
+			},
+			"ownerDocument": func(n *node) Value {
+				if n.kind == documentNode {
+					return Null()
+				}
+				return document().value()
+			},
+		}, setters{
+			"textContent": func(n *node, v Value) {
+				if n.kind != documentNode && n.kind != doctypeNode {
+					n.setText(nullableString(v))
+				}
+			},
+			"nodeValue": func(n *node, v Value) {
+				if n.kind == textNode || n.kind == commentNode {
+					n.data = nullableString(v)
+				}
+			},
+		})
+		o.method("appendChild", func(this Value, args []Value) Value {
+			var child = mustNode(arg(args, 0), "appendChild")
+			mustNode(this, "appendChild").insertBefore(child, nil)
+			return child.value()
+		})
+		o.method("insertBefore", func(this Value, args []Value) Value {
+			var child = mustNode(arg(args, 0), "insertBefore")
+			mustNode(this, "insertBefore").insertBefore(child, nodeOf(arg(args, 1)))
+			return child.value()
+		})
+		o.method("removeChild", func(this Value, args []Value) Value {
+			var child = mustNode(arg(args, 0), "removeChild")
+			if child.parent != mustNode(this, "removeChild") {
+				throw("NotFoundError", "The node to be removed is not a child of this node.")
+			}
+			child.detach()
+			return child.value()
+		})
+		o.method("replaceChild", func(this Value, args []Value) Value {
+			var n = mustNode(this, "replaceChild")
+			var child = mustNode(arg(args, 0), "replaceChild")
+			var old = mustNode(arg(args, 1), "replaceChild")
+			if old.parent != n {
+				throw("NotFoundError", "The node to be replaced is not a child of this node.")
+			}
+			if child != old {
+				n.insertBefore(child, old)
+				old.detach()
+			}
+			return old.value()
+		})
+		o.method("contains", func(this Value, args []Value) Value {
+			return boolean(mustNode(this, "contains").contains(nodeOf(arg(args, 0))))
+		})
+		o.method("cloneNode", func(this Value, args []Value) Value {
+			return mustNode(this, "cloneNode").clone(arg(args, 0).Truthy()).value()
+		})
+		o.method("hasChildNodes", func(this Value, args []Value) Value {
+			return boolean(len(mustNode(this, "hasChildNodes").children) > 0)
+		})
+		o.method("getRootNode", func(this Value, args []Value) Value {
+			return mustNode(this, "getRootNode").root().value()
+		})
+		o.method("isSameNode", func(this Value, args []Value) Value {
+			return boolean(mustNode(this, "isSameNode") == nodeOf(arg(args, 0)))
+		})
+	}, eventTargetProto)
+}
+
+// parentNodeProto has the members of elements, documents and fragments which have element children.
+func parentNodeProto(o *object) (getters, setters) {
+	o.method("append", func(this Value, args []Value) Value {
+		var n = mustNode(this, "append")
+		for _, c := range toNodes(args) {
+			n.insertBefore(c, nil)
+		}
+		return Undefined()
+	})
+	o.method("prepend", func(this Value, args []Value) Value {
+		var n = mustNode(this, "prepend")
+		var ref *node
+		if len(n.children) > 0 {
+			ref = n.children[0]
+		}
+		for _, c := range toNodes(args) {
+			n.insertBefore(c, ref)
+		}
+		return Undefined()
+	})
+	o.method("replaceChildren", func(this Value, args []Value) Value {
+		var n = mustNode(this, "replaceChildren")
+		var nodes = toNodes(args)
+		n.removeChildren()
+		for _, c := range nodes {
+			n.insertBefore(c, nil)
+		}
+		return Undefined()
+	})
+	o.method("getElementsByTagName", func(this Value, args []Value) Value {
+		var name = argString(args, 0)
+		return mustNode(this, "getElementsByTagName").elementsBy(func(c *node) bool {
+			return name == "*" || c.name == name || (c.isHTML() && c.name == strings.ToLower(name))
+		})
+	})
+	o.method("getElementsByClassName", func(this Value, args []Value) Value {
+		var classes = strings.Fields(argString(args, 0))
+		return mustNode(this, "getElementsByClassName").elementsBy(func(c *node) bool {
+			for _, class := range classes {
+				if !c.hasClass(class) {
+					return false
+				}
+			}
+			return len(classes) > 0
+		})
+	})
+	o.method("querySelector", func(this Value, args []Value) Value {
+		var found = mustNode(this, "querySelector").querySelector(argString(args, 0), false)
+		if len(found) == 0 {
+			return Null()
+		}
+		return found[0].value()
+	})
+	o.method("querySelectorAll", func(this Value, args []Value) Value {
+		return nodeList(mustNode(this, "querySelectorAll").querySelector(argString(args, 0), true))
+	})
+	return getters{
+		"children":          func(n *node) Value { return nodeList(n.elementChildren()) },
+		"childElementCount": func(n *node) Value { return number(float64(len(n.elementChildren()))) },
+		"firstElementChild": func(n *node) Value {
+			var elements = n.elementChildren()
+			if len(elements) == 0 {
+				return Null()
+			}
+			return elements[0].value()
+		},
+		"lastElementChild": func(n *node) Value {
+			var elements = n.elementChildren()
+			if len(elements) == 0 {
+				return Null()
+			}
+			return elements[len(elements)-1].value()
+		},
+	}, setters{}
+}
+
+// childNodeProto has the members of nodes which can be inserted into a parent.
+func childNodeProto(o *object) (getters, setters) {
+	o.method("remove", func(this Value, args []Value) Value {
+		mustNode(this, "remove").detach()
+		return Undefined()
+	})
+	o.method("before", func(this Value, args []Value) Value {
+		var n = mustNode(this, "before")
+		if n.parent == nil {
+			return Undefined()
+		}
+		for _, c := range toNodes(args) {
+			n.parent.insertBefore(c, n)
+		}
+		return Undefined()
+	})
+	o.method("after", func(this Value, args []Value) Value {
+		var n = mustNode(this, "after")
+		if n.parent == nil {
+			return Undefined()
+		}
+		var parent, ref = n.parent, n.sibling(1, false)
+		for _, c := range toNodes(args) {
+			parent.insertBefore(c, ref)
+		}
+		return Undefined()
+	})
+	o.method("replaceWith", func(this Value, args []Value) Value {
+		var n = mustNode(this, "replaceWith")
+		if n.parent == nil {
+			return Undefined()
+		}
+		var parent, ref = n.parent, n.sibling(1, false)
+		n.detach()
+		for _, c := range toNodes(args) {
+			parent.insertBefore(c, ref)
+		}
+		return Undefined()
+	})
+	return getters{
+		"previousElementSibling": func(n *node) Value { return nodeValue(n.sibling(-1, true)) },
+		"nextElementSibling":     func(n *node) Value { return nodeValue(n.sibling(1, true)) },
+	}, setters{}
+}
+
+func characterDataProto() *object {
+	return proto("CharacterData", func(o *object) {
+		var childGet, childSet = childNodeProto(o)
+		accessors(o, merge(childGet, getters{
+			"data":   func(n *node) Value { return str(n.data) },
+			"length": func(n *node) Value { return number(float64(len([]rune(n.data)))) },
+		}), merge(childSet, setters{
+			"data": func(n *node, v Value) { n.data = nullableString(v) },
+		}))
+	}, nodeProto)
+}
+
+func textProto() *object {
+	return proto("Text", func(o *object) {
+		accessors(o, getters{
+			"wholeText": func(n *node) Value { return str(n.data) },
+		}, nil)
+	}, characterDataProto)
+}
+
+func commentProto() *object {
+	return proto("Comment", func(o *object) {}, characterDataProto)
+}
+
+func doctypeProto() *object {
+	return proto("DocumentType", func(o *object) {
+		accessors(o, getters{
+			"name": func(n *node) Value { return str(n.name) },
+		}, nil)
+	}, nodeProto)
+}
+
+func fragmentProto() *object {
+	return proto("DocumentFragment", func(o *object) {
+		var get, set = parentNodeProto(o)
+		accessors(o, get, set)
+		o.method("getElementById", getElementById)
+	}, nodeProto)
+}
+
+func getElementById(this Value, args []Value) Value {
+	var id = argString(args, 0)
+	var found *node
+	mustNode(this, "getElementById").walk(func(c *node) bool {
+		if c.kind == elementNode && c.attr("id") == id {
+			found = c
+			return false
+		}
+		return true
+	})
+	return nodeValue(found)
+}
+
+func nullableString(v Value) string {
+	if v.IsNull() {
+		return ""
+	}
+	return toString(v)
+}
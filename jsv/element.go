@@ -0,0 +1,556 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package jsv
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Attributes reflected as string properties of elements, by property name.
+var reflectedAttributes = map[string]string{
+	"id":           "id",
+	"className":    "class",
+	"title":        "title",
+	"lang":         "lang",
+	"dir":          "dir",
+	"slot":         "slot",
+	"role":         "role",
+	"href":         "href",
+	"src":          "src",
+	"alt":          "alt",
+	"name":         "name",
+	"rel":          "rel",
+	"target":       "target",
+	"placeholder":  "placeholder",
+	"action":       "action",
+	"method":       "method",
+	"htmlFor":      "for",
+	"accept":       "accept",
+	"autocomplete": "autocomplete",
+	"min":          "min",
+	"max":          "max",
+	"step":         "step",
+	"pattern":      "pattern",
+	"download":     "download",
+	"enctype":      "enctype",
+	"inputMode":    "inputmode",
+}
+
+// Boolean attributes reflected as properties of elements, by property name.
+var booleanAttributes = map[string]string{
+	"hidden":         "hidden",
+	"disabled":       "disabled",
+	"required":       "required",
+	"readOnly":       "readonly",
+	"multiple":       "multiple",
+	"selected":       "selected",
+	"checked":        "checked",
+	"autofocus":      "autofocus",
+	"open":           "open",
+	"noValidate":     "novalidate",
+	"defaultChecked": "checked",
+}
+
+// Layout properties, there is no layout without a browser so these are always 0.
+var layoutProperties = []string{
+	"clientWidth", "clientHeight", "clientTop", "clientLeft",
+	"offsetWidth", "offsetHeight", "offsetTop", "offsetLeft",
+	"scrollWidth", "scrollHeight", "scrollTop", "scrollLeft",
+}
+
+func elementProto() *object {
+	return proto("Element", func(o *object) {
+		var parentGet, parentSet = parentNodeProto(o)
+		var childGet, childSet = childNodeProto(o)
+		var get = getters{
+			"tagName":      func(n *node) Value { return str(n.tagName()) },
+			"localName":    func(n *node) Value { return str(n.name) },
+			"namespaceURI": func(n *node) Value { return str(n.ns) },
+			"classList":    func(n *node) Value { return view(tokenListProto(), n) },
+			"style":        func(n *node) Value { return view(styleProto(), n) },
+			"dataset":      func(n *node) Value { return view(datasetProto(), n) },
+			"attributes": func(n *node) Value {
+				var list = make([]Value, len(n.attrs))
+				for i, a := range n.attrs {
+					var v = newPlainObject()
+					v.Set("name", a.name)
+					v.Set("localName", a.name[strings.IndexByte(a.name, ':')+1:])
+					v.Set("value", a.value)
+					if a.ns != "" {
+						v.Set("namespaceURI", a.ns)
+					} else {
+						v.Set("namespaceURI", nil)
+					}
+					list[i] = v
+				}
+				return arrayOf(list)
+			},
+			"innerHTML": func(n *node) Value { return str(innerHTML(n)) },
+			"outerHTML": func(n *node) Value { return str(outerHTML(n)) },
+			"innerText": func(n *node) Value { return str(n.text()) },
+			"value":     func(n *node) Value { return str(elementValue(n)) },
+			"tabIndex": func(n *node) Value {
+				var i, err = strconv.Atoi(n.attr("tabindex"))
+				if err != nil {
+					return number(-1)
+				}
+				return number(float64(i))
+			},
+		}
+		var set = setters{
+			"classList": func(n *node, v Value) { n.setAttr("class", toString(v)) },
+			"style":     func(n *node, v Value) { n.setAttr("style", toString(v)) },
+			"innerHTML": func(n *node, v Value) { setInnerHTML(n, nullableString(v)) },
+			"outerHTML": func(n *node, v Value) {
+				if n.parent == nil {
+					return
+				}
+				var frag = parseFragment(n.parent, nullableString(v))
+				n.parent.insertBefore(frag, n)
+				n.detach()
+			},
+			"innerText": func(n *node, v Value) { n.setText(nullableString(v)) },
+			"value":     func(n *node, v Value) { setElementValue(n, nullableString(v)) },
+			"tabIndex":  func(n *node, v Value) { n.setAttr("tabindex", toString(v)) },
+		}
+		for property, name := range reflectedAttributes {
+			var name = name
+			get[property] = func(n *node) Value { return str(n.attr(name)) }
+			set[property] = func(n *node, v Value) { n.setAttr(name, toString(v)) }
+		}
+		for property, name := range booleanAttributes {
+			var name = name
+			get[property] = func(n *node) Value {
+				var _, ok = n.getAttr(name)
+				return boolean(ok)
+			}
+			set[property] = func(n *node, v Value) {
+				if v.Truthy() {
+					n.setAttr(name, "")
+				} else {
+					n.removeAttr(name)
+				}
+			}
+		}
+		for _, property := range layoutProperties {
+			get[property] = func(n *node) Value { return number(0) }
+		}
+		// Scroll positions can be assigned, they are stored on the element itself.
+		delete(get, "scrollTop")
+		delete(get, "scrollLeft")
+		accessors(o, merge(parentGet, childGet, get), merge(parentSet, childSet, set))
+
+		o.method("getAttribute", func(this Value, args []Value) Value {
+			if v, ok := mustNode(this, "getAttribute").getAttr(argString(args, 0)); ok {
+				return str(v)
+			}
+			return Null()
+		})
+		o.method("setAttribute", func(this Value, args []Value) Value {
+			mustNode(this, "setAttribute").setAttr(argString(args, 0), argString(args, 1))
+			return Undefined()
+		})
+		o.method("removeAttribute", func(this Value, args []Value) Value {
+			mustNode(this, "removeAttribute").removeAttr(argString(args, 0))
+			return Undefined()
+		})
+		o.method("hasAttribute", func(this Value, args []Value) Value {
+			var _, ok = mustNode(this, "hasAttribute").getAttr(argString(args, 0))
+			return boolean(ok)
+		})
+		o.method("toggleAttribute", func(this Value, args []Value) Value {
+			var n = mustNode(this, "toggleAttribute")
+			var name = argString(args, 0)
+			var _, ok = n.getAttr(name)
+			var force = arg(args, 1)
+			var on = !ok
+			if !force.IsUndefined() {
+				on = force.Truthy()
+			}
+			if on && !ok {
+				n.setAttr(name, "")
+			} else if !on {
+				n.removeAttr(name)
+			}
+			return boolean(on)
+		})
+		o.method("getAttributeNames", func(this Value, args []Value) Value {
+			var n = mustNode(this, "getAttributeNames")
+			var names = make([]Value, len(n.attrs))
+			for i, a := range n.attrs {
+				names[i] = str(a.name)
+			}
+			return arrayOf(names)
+		})
+		o.method("hasAttributes", func(this Value, args []Value) Value {
+			return boolean(len(mustNode(this, "hasAttributes").attrs) > 0)
+		})
+		o.method("getAttributeNS", func(this Value, args []Value) Value {
+			var n = mustNode(this, "getAttributeNS")
+			var local = argString(args, 1)
+			for _, a := range n.attrs {
+				if a.ns == nullableString(arg(args, 0)) && a.name[strings.IndexByte(a.name, ':')+1:] == local {
+					return str(a.value)
+				}
+			}
+			return Null()
+		})
+		o.method("setAttributeNS", func(this Value, args []Value) Value {
+			mustNode(this, "setAttributeNS").setAttrNS(nullableString(arg(args, 0)), argString(args, 1), argString(args, 2))
+			return Undefined()
+		})
+		o.method("removeAttributeNS", func(this Value, args []Value) Value {
+			var n = mustNode(this, "removeAttributeNS")
+			var ns, local = nullableString(arg(args, 0)), argString(args, 1)
+			for _, a := range n.attrs {
+				if a.ns == ns && a.name[strings.IndexByte(a.name, ':')+1:] == local {
+					n.removeAttrNS(ns, a.name)
+					break
+				}
+			}
+			return Undefined()
+		})
+		o.method("insertAdjacentHTML", func(this Value, args []Value) Value {
+			var n = mustNode(this, "insertAdjacentHTML")
+			var context = n
+			switch strings.ToLower(argString(args, 0)) {
+			case "beforebegin", "afterend":
+				context = n.parent
+			}
+			if context == nil {
+				return Undefined()
+			}
+			insertAdjacent(n, argString(args, 0), parseFragment(context, argString(args, 1)))
+			return Undefined()
+		})
+		o.method("insertAdjacentElement", func(this Value, args []Value) Value {
+			var child = mustNode(arg(args, 1), "insertAdjacentElement")
+			insertAdjacent(mustNode(this, "insertAdjacentElement"), argString(args, 0), child)
+			return child.value()
+		})
+		o.method("insertAdjacentText", func(this Value, args []Value) Value {
+			insertAdjacent(mustNode(this, "insertAdjacentText"), argString(args, 0), newText(argString(args, 1)))
+			return Undefined()
+		})
+		o.method("getBoundingClientRect", func(this Value, args []Value) Value {
+			return domRect()
+		})
+		o.method("getClientRects", func(this Value, args []Value) Value {
+			return arrayOf(nil)
+		})
+		o.method("getAnimations", func(this Value, args []Value) Value {
+			return arrayOf(nil)
+		})
+		o.method("matches", func(this Value, args []Value) Value {
+			var n = mustNode(this, "matches")
+			return boolean(parseSelector(argString(args, 0)).matches(n, n))
+		})
+		o.method("closest", func(this Value, args []Value) Value {
+			var n = mustNode(this, "closest")
+			var list = parseSelector(argString(args, 0))
+			for p := n; p != nil && p.kind == elementNode; p = p.parent {
+				if list.matches(p, n) {
+					return p.value()
+				}
+			}
+			return Null()
+		})
+		o.method("click", func(this Value, args []Value) Value {
+			click(mustNode(this, "click"))
+			return Undefined()
+		})
+		o.method("focus", func(this Value, args []Value) Value {
+			focus(mustNode(this, "focus"))
+			return Undefined()
+		})
+		o.method("blur", func(this Value, args []Value) Value {
+			if n := mustNode(this, "blur"); n == activeElement {
+				focus(nil)
+			}
+			return Undefined()
+		})
+		o.method("setPointerCapture", func(this Value, args []Value) Value {
+			pointerCaptures[int(toNumber(arg(args, 0)))] = mustNode(this, "setPointerCapture")
+			return Undefined()
+		})
+		o.method("releasePointerCapture", func(this Value, args []Value) Value {
+			var id = int(toNumber(arg(args, 0)))
+			if pointerCaptures[id] == mustNode(this, "releasePointerCapture") {
+				delete(pointerCaptures, id)
+			}
+			return Undefined()
+		})
+		o.method("hasPointerCapture", func(this Value, args []Value) Value {
+			return boolean(pointerCaptures[int(toNumber(arg(args, 0)))] == mustNode(this, "hasPointerCapture"))
+		})
+		for _, name := range []string{"scroll", "scrollTo", "scrollBy", "scrollIntoView", "scrollIntoViewIfNeeded"} {
+			o.method(name, func(this Value, args []Value) Value {
+				return Undefined()
+			})
+		}
+	}, nodeProto)
+}
+
+// pointerCaptures maps pointer ids to the element which captured them.
+var pointerCaptures = make(map[int]*node)
+
+func insertAdjacent(n *node, position string, child *node) {
+	switch strings.ToLower(position) {
+	case "beforebegin":
+		if n.parent != nil {
+			n.parent.insertBefore(child, n)
+		}
+	case "afterbegin":
+		var ref *node
+		if len(n.children) > 0 {
+			ref = n.children[0]
+		}
+		n.insertBefore(child, ref)
+	case "beforeend":
+		n.insertBefore(child, nil)
+	case "afterend":
+		if n.parent != nil {
+			n.parent.insertBefore(child, n.sibling(1, false))
+		}
+	default:
+		throw("SyntaxError", "The value provided ('"+position+"') is not one of 'beforeBegin', 'afterBegin', 'beforeEnd', or 'afterEnd'.")
+	}
+}
+
+func domRect() Value {
+	var r = newPlainObject()
+	for _, k := range []string{"x", "y", "width", "height", "top", "right", "bottom", "left"} {
+		r.Set(k, 0)
+	}
+	return r
+}
+
+// elementValue returns the value property of form controls.
+func elementValue(n *node) string {
+	switch n.name {
+	case "textarea":
+		return n.text()
+	case "select":
+		var value string
+		var first = true
+		n.walk(func(c *node) bool {
+			if c.kind == elementNode && c.name == "option" {
+				if _, ok := c.getAttr("selected"); ok || first {
+					value = elementValue(c)
+				}
+				first = false
+			}
+			return true
+		})
+		return value
+	case "option":
+		if v, ok := n.getAttr("value"); ok {
+			return v
+		}
+		return strings.TrimSpace(n.text())
+	}
+	return n.attr("value")
+}
+
+// setElementValue sets the value property, it is stored as the value attribute
+// so server-rendered markup contains it.
+func setElementValue(n *node, value string) {
+	switch n.name {
+	case "textarea":
+		n.setText(value)
+	case "select":
+		n.walk(func(c *node) bool {
+			if c.kind == elementNode && c.name == "option" {
+				if elementValue(c) == value {
+					c.setAttr("selected", "")
+				} else {
+					c.removeAttr("selected")
+				}
+			}
+			return true
+		})
+	default:
+		n.setAttr("value", value)
+	}
+}
+
+// view returns an object which exposes part of the node, like its classList or style.
+func view(proto *object, n *node) Value {
+	var o = newObject(proto)
+	o.internal = n
+	return o.value()
+}
+
+// viewHooks installs hooks which handle every key, except the members of the prototype.
+func viewHooks(o *object, get func(n *node, key string) (Value, bool), set func(n *node, key string, v Value) bool) {
+	o.get = func(this Value, key string) (Value, bool) {
+		var n = nodeOf(this)
+		if n == nil || this.o == o {
+			return Value{}, false
+		}
+		for p := o.proto; p != nil; p = p.proto {
+			if _, ok := p.props[key]; ok {
+				return Value{}, false
+			}
+		}
+		return get(n, key)
+	}
+	o.set = func(this Value, key string, v Value) bool {
+		var n = nodeOf(this)
+		if n == nil || this.o == o {
+			return false
+		}
+		if _, ok := o.props[key]; ok {
+			return false
+		}
+		return set(n, key, v)
+	}
+}
+
+func tokenListProto() *object {
+	return proto("DOMTokenList", func(o *object) {
+		viewHooks(o, func(n *node, key string) (Value, bool) {
+			var classes = n.classes()
+			switch key {
+			case "length":
+				return number(float64(len(classes))), true
+			case "value":
+				return str(n.attr("class")), true
+			}
+			if i, err := strconv.Atoi(key); err == nil {
+				if i >= 0 && i < len(classes) {
+					return str(classes[i]), true
+				}
+				return Undefined(), true
+			}
+			return Value{}, false
+		}, func(n *node, key string, v Value) bool {
+			if key == "value" {
+				n.setAttr("class", toString(v))
+				return true
+			}
+			return false
+		})
+		var update = func(n *node, fn func(classes []string) []string) {
+			var classes = fn(n.classes())
+			if _, ok := n.getAttr("class"); ok || len(classes) > 0 {
+				n.setAttr("class", strings.Join(classes, " "))
+			}
+		}
+		var without = func(classes []string, token string) []string {
+			var list = classes[:0:0]
+			for _, c := range classes {
+				if c != token {
+					list = append(list, c)
+				}
+			}
+			return list
+		}
+		o.method("add", func(this Value, args []Value) Value {
+			update(nodeOf(this), func(classes []string) []string {
+				for _, a := range args {
+					var token = toString(a)
+					if !contains(classes, token) {
+						classes = append(classes, token)
+					}
+				}
+				return classes
+			})
+			return Undefined()
+		})
+		o.method("remove", func(this Value, args []Value) Value {
+			update(nodeOf(this), func(classes []string) []string {
+				for _, a := range args {
+					classes = without(classes, toString(a))
+				}
+				return classes
+			})
+			return Undefined()
+		})
+		o.method("toggle", func(this Value, args []Value) Value {
+			var token = argString(args, 0)
+			var on bool
+			update(nodeOf(this), func(classes []string) []string {
+				on = !contains(classes, token)
+				if force := arg(args, 1); !force.IsUndefined() {
+					on = force.Truthy()
+				}
+				classes = without(classes, token)
+				if on {
+					classes = append(classes, token)
+				}
+				return classes
+			})
+			return boolean(on)
+		})
+		o.method("replace", func(this Value, args []Value) Value {
+			var old, token = argString(args, 0), argString(args, 1)
+			var found bool
+			update(nodeOf(this), func(classes []string) []string {
+				for i, c := range classes {
+					if c == old {
+						classes[i] = token
+						found = true
+					}
+				}
+				return classes
+			})
+			return boolean(found)
+		})
+		o.method("contains", func(this Value, args []Value) Value {
+			return boolean(nodeOf(this).hasClass(argString(args, 0)))
+		})
+		o.method("item", func(this Value, args []Value) Value {
+			var classes = nodeOf(this).classes()
+			var i = int(toNumber(arg(args, 0)))
+			if i < 0 || i >= len(classes) {
+				return Null()
+			}
+			return str(classes[i])
+		})
+		o.method("toString", func(this Value, args []Value) Value {
+			return str(nodeOf(this).attr("class"))
+		})
+	}, objectProto)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func datasetProto() *object {
+	return proto("DOMStringMap", func(o *object) {
+		viewHooks(o, func(n *node, key string) (Value, bool) {
+			if v, ok := n.getAttr("data-" + kebabCase(key)); ok {
+				return str(v), true
+			}
+			return Value{}, false
+		}, func(n *node, key string, v Value) bool {
+			n.setAttr("data-"+kebabCase(key), toString(v))
+			return true
+		})
+	}, objectProto)
+}
+
+// kebabCase converts camelCase property names to kebab-case.
+func kebabCase(name string) string {
+	if strings.HasPrefix(name, "--") {
+		return name
+	}
+	var b strings.Builder
+	for _, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			b.WriteByte('-')
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,172 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package jsv
+
+import (
+	"time"
+)
+
+type listener struct {
+	fn      Value
+	capture bool
+	once    bool
+	passive bool
+	removed bool
+}
+
+func listenerOptions(v Value) (capture, once, passive bool, signal Value) {
+	switch {
+	case v.t == TypeBoolean:
+		return v.b, false, false, Undefined()
+	case v.t.isObject():
+		return v.Get("capture").Truthy(), v.Get("once").Truthy(), v.Get("passive").Truthy(), v.Get("signal")
+	}
+	return false, false, false, Undefined()
+}
+
+func eventTargetProto() *object {
+	return proto("EventTarget", func(o *object) {
+		o.method("addEventListener", func(this Value, args []Value) Value {
+			var fn = arg(args, 1)
+			if !this.t.isObject() || !fn.t.isObject() {
+				return Undefined()
+			}
+			var typ = argString(args, 0)
+			var capture, once, passive, signal = listenerOptions(arg(args, 2))
+			if signal.t.isObject() && signal.Get("aborted").Truthy() {
+				return Undefined()
+			}
+			for _, l := range this.o.events[typ] {
+				if l.fn.Equal(fn) && l.capture == capture {
+					return Undefined()
+				}
+			}
+			if this.o.events == nil {
+				this.o.events = make(map[string][]*listener)
+			}
+			var l = &listener{fn: fn, capture: capture, once: once, passive: passive}
+			this.o.events[typ] = append(this.o.events[typ], l)
+			if signal.t.isObject() {
+				var target = this.o
+				signal.Call("addEventListener", "abort", native(func(Value, []Value) Value {
+					removeListener(target, typ, func(other *listener) bool { return other == l })
+					return Undefined()
+				}), map[string]any{"once": true})
+			}
+			return Undefined()
+		})
+		o.method("removeEventListener", func(this Value, args []Value) Value {
+			if !this.t.isObject() {
+				return Undefined()
+			}
+			var typ, fn = argString(args, 0), arg(args, 1)
+			var capture, _, _, _ = listenerOptions(arg(args, 2))
+			removeListener(this.o, typ, func(l *listener) bool {
+				return l.fn.Equal(fn) && l.capture == capture
+			})
+			return Undefined()
+		})
+		o.method("dispatchEvent", func(this Value, args []Value) Value {
+			var e = arg(args, 0)
+			if !this.t.isObject() || !e.t.isObject() {
+				throw("TypeError", "Failed to execute 'dispatchEvent' on 'EventTarget': parameter 1 is not of type 'Event'.")
+			}
+			return boolean(dispatch(this, e))
+		})
+	}, objectProto)
+}
+
+func removeListener(o *object, typ string, match func(l *listener) bool) {
+	var list = o.events[typ]
+	for i, l := range list {
+		if match(l) {
+			l.removed = true
+			o.events[typ] = append(list[:i:i], list[i+1:]...)
+			return
+		}
+	}
+}
+
+var timeOrigin = time.Now()
+
+// eventState is the internal state of an event, which is not visible as a property.
+type eventState struct {
+	stopImmediate bool
+}
+
+func eventProto() *object {
+	return proto("Event", func(o *object) {
+		o.method("preventDefault", func(this Value, args []Value) Value {
+			if this.Get("cancelable").Truthy() {
+				this.Set("defaultPrevented", true)
+			}
+			return Undefined()
+		})
+		o.method("stopPropagation", func(this Value, args []Value) Value {
+			this.Set("cancelBubble", true)
+			return Undefined()
+		})
+		o.method("stopImmediatePropagation", func(this Value, args []Value) Value {
+			this.Set("cancelBubble", true)
+			if state, ok := this.o.internal.(*eventState); ok {
+				state.stopImmediate = true
+			}
+			return Undefined()
+		})
+		o.method("composedPath", func(this Value, args []Value) Value {
+			var path []Value
+			if n := nodeOf(this.Get("currentTarget")); n != nil {
+				for p := nodeOf(this.Get("target")); p != nil; p = p.parent {
+					path = append(path, p.value())
+				}
+				if len(path) > 0 && nodeOf(path[len(path)-1]) == document() {
+					path = append(path, Global())
+				}
+			}
+			return arrayOf(path)
+		})
+		o.method("initEvent", func(this Value, args []Value) Value {
+			initEvent(this, argString(args, 0), arg(args, 1).Truthy(), arg(args, 2).Truthy())
+			return Undefined()
+		})
+	}, objectProto)
+}
+
+func initEvent(e Value, typ string, bubbles, cancelable bool) {
+	e.Set("type", typ)
+	e.Set("bubbles", bubbles)
+	e.Set("cancelable", cancelable)
+}
+
+// newEvent creates an Event, the init object may be undefined.
+func newEvent(proto *object, typ string, init Value) Value {
+	var e = newObject(proto).value()
+	e.o.internal = &eventState{}
+	var get = func(key string) Value {
+		if init.t.isObject() {
+			return init.Get(key)
+		}
+		return Undefined()
+	}
+	initEvent(e, typ, get("bubbles").Truthy(), get("cancelable").Truthy())
+	e.Set("composed", get("composed").Truthy())
+	e.Set("defaultPrevented", false)
+	e.Set("cancelBubble", false)
+	e.Set("isTrusted", false)
+	e.Set("eventPhase", 0)
+	e.Set("target", nil)
+	e.Set("currentTarget", nil)
+	e.Set("timeStamp", float64(time.Since(timeOrigin))/float64(time.Millisecond))
+	if init.t.isObject() {
+		// Subclasses like KeyboardEvent copy their fields from the init object.
+		for _, k := range init.o.ownKeys() {
+			switch k {
+			case "bubbles", "cancelable", "composed":
+			default:
+				e.Set(k, init.Get(k))
+			}
+		}
+	}
+	return e
+}
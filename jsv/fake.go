@@ -0,0 +1,62 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package jsv
+
+import (
+	"time"
+)
+
+// The functions below drive the in-memory DOM from tests.
+//
+// Event listeners run synchronously, and timers only run when the clock is advanced,
+// so tests are deterministic.
+
+// Reset empties the document, removes the listeners of the window and stops all timers.
+func Reset() {
+	ResetDocument()
+	Global().o.events = nil
+	activeElement = nil
+	pointerCaptures = make(map[int]*node)
+	resetTimers()
+}
+
+// Advance moves the fake clock forward by d, running due timers and animation frames.
+func Advance(d time.Duration) {
+	advance(d)
+}
+
+// Now returns the time of the fake clock, as returned by performance.now.
+func Now() time.Duration {
+	return clock.now
+}
+
+// Dispatch dispatches an event on the target, and reports whether its default action was not prevented.
+//
+// The init fields are set on the event, for example bubbles or key.
+func Dispatch(target Value, typ string, init map[string]any) bool {
+	return dispatch(target, newEvent(eventProto(), typ, ValueOf(init)))
+}
+
+// Click clicks the element, like element.click() in the browser.
+func Click(element Value) {
+	click(mustNode(element, "Click"))
+}
+
+// Input sets the value of a form control, and dispatches the input and change events.
+func Input(element Value, value string) {
+	var n = mustNode(element, "Input")
+	setElementValue(n, value)
+	dispatch(element, newEventOf("InputEvent", "input", map[string]any{"bubbles": true, "composed": true, "data": value, "inputType": "insertText"}))
+	dispatch(element, newEventOf("Event", "change", map[string]any{"bubbles": true}))
+}
+
+// Key dispatches a keydown and keyup event for the key on the element, or the focused element if it is undefined.
+func Key(element Value, key string) {
+	if element.IsUndefined() {
+		element = document().value().Get("activeElement")
+	}
+	var init = map[string]any{"bubbles": true, "cancelable": true, "composed": true, "key": key}
+	dispatch(element, newEventOf("KeyboardEvent", "keydown", init))
+	dispatch(element, newEventOf("KeyboardEvent", "keyup", init))
+}
@@ -0,0 +1,283 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package jsv_test
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+func body(t *testing.T, html string) js.Value {
+	t.Helper()
+	js.Reset()
+	var b = js.Global().Get("document").Get("body")
+	b.Set("innerHTML", html)
+	return b
+}
+
+func ids(list js.Value) []string {
+	var s []string
+	for i := 0; i < list.Length(); i++ {
+		s = append(s, list.Index(i).Get("id").String())
+	}
+	return s
+}
+
+func TestQuerySelectorAll(t *testing.T) {
+	var b = body(t, `
+		<ul id="list" class="menu">
+			<li id="a" class="item active"><a id="a-link" href="/a">A</a></li>
+			<li id="b" class="item" data-kind="sub"><span id="b-span">B</span></li>
+			<li id="c" class="item" data-kind="Sub-menu"></li>
+		</ul>
+		<p id="p"><input id="i" type="checkbox" checked></p>`)
+
+	var tests = []struct {
+		selector string
+		want     []string
+	}{
+		{"li", []string{"a", "b", "c"}},
+		{"#list > .item.active", []string{"a"}},
+		{"ul a[href^='/']", []string{"a-link"}},
+		{"li[data-kind|=sub i]", []string{"b", "c"}},
+		{".item:not(.active)", []string{"b", "c"}},
+		{"li:first-child, li:last-child", []string{"a", "c"}},
+		{"li:nth-child(2n+1)", []string{"a", "c"}},
+		{"#a ~ li", []string{"b", "c"}},
+		{"#a + li", []string{"b"}},
+		{"li:empty", []string{"c"}},
+		{"input:checked", []string{"i"}},
+		{"li:has(span)", []string{"b"}},
+	}
+	for _, test := range tests {
+		var got = ids(b.Call("querySelectorAll", test.selector))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("querySelectorAll(%q) = %v, want %v", test.selector, got, test.want)
+		}
+	}
+
+	var span = b.Call("querySelector", "#b-span")
+	if got := span.Call("closest", "li").Get("id").String(); got != "b" {
+		t.Errorf("closest(li) = %q, want b", got)
+	}
+	if !span.Call("matches", "ul .item > span").Bool() {
+		t.Errorf("matches(ul .item > span) = false, want true")
+	}
+}
+
+func TestQuerySelectorInvalid(t *testing.T) {
+	var b = body(t, "")
+	defer func() {
+		var err, ok = recover().(js.Error)
+		if !ok || err.Get("name").String() != "SyntaxError" {
+			t.Errorf("recovered %v, want a SyntaxError", err)
+		}
+	}()
+	b.Call("querySelector", "li[")
+}
+
+func TestDispatch(t *testing.T) {
+	var b = body(t, `<div id="outer"><button id="inner">Go</button></div>`)
+	var outer = b.Call("querySelector", "#outer")
+	var inner = b.Call("querySelector", "#inner")
+
+	var calls []string
+	var listen = func(target js.Value, name string, capture bool) {
+		target.Call("addEventListener", "click", js.FuncOf(func(this js.Value, args []js.Value) any {
+			calls = append(calls, name+":"+strconv.Itoa(args[0].Get("eventPhase").Int()))
+			return nil
+		}), capture)
+	}
+	listen(outer, "outer-capture", true)
+	listen(outer, "outer-bubble", false)
+	listen(inner, "inner", false)
+	listen(js.Global(), "window", false)
+
+	js.Click(inner)
+	var want = []string{"outer-capture:1", "inner:2", "outer-bubble:3", "window:3"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("click called %v, want %v", calls, want)
+	}
+
+	calls = nil
+	inner.Call("addEventListener", "click", js.FuncOf(func(this js.Value, args []js.Value) any {
+		args[0].Call("stopPropagation")
+		args[0].Call("preventDefault")
+		return nil
+	}))
+	if js.Dispatch(inner, "click", map[string]any{"bubbles": true, "cancelable": true}) {
+		t.Errorf("Dispatch returned true, want false after preventDefault")
+	}
+	want = []string{"outer-capture:1", "inner:2"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("stopped click called %v, want %v", calls, want)
+	}
+}
+
+func TestRemoveEventListener(t *testing.T) {
+	var b = body(t, `<input id="name">`)
+	var input = b.Call("querySelector", "#name")
+	var values []string
+	var fn = js.FuncOf(func(this js.Value, args []js.Value) any {
+		values = append(values, args[0].Get("target").Get("value").String())
+		return nil
+	})
+	input.Call("addEventListener", "input", fn)
+	js.Input(input, "a")
+	input.Call("removeEventListener", "input", fn)
+	js.Input(input, "b")
+	if !reflect.DeepEqual(values, []string{"a"}) {
+		t.Errorf("input listener got %v, want [a]", values)
+	}
+	if got := input.Get("value").String(); got != "b" {
+		t.Errorf("value = %q, want b", got)
+	}
+}
+
+func TestTimers(t *testing.T) {
+	js.Reset()
+	var window = js.Global()
+	var calls []string
+	var record = func(name string) js.Func {
+		return js.FuncOf(func(this js.Value, args []js.Value) any {
+			calls = append(calls, name+"@"+js.Now().String())
+			return nil
+		})
+	}
+
+	window.Call("setTimeout", record("timeout"), 100)
+	var interval = window.Call("setInterval", record("interval"), 40)
+	var canceled = window.Call("setTimeout", record("canceled"), 50)
+	window.Call("clearTimeout", canceled)
+	window.Call("queueMicrotask", record("microtask"))
+
+	js.Advance(100 * time.Millisecond)
+	window.Call("clearInterval", interval)
+	js.Advance(time.Second)
+
+	var want = []string{"microtask@0s", "interval@40ms", "interval@80ms", "timeout@100ms"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("timers called %v, want %v", calls, want)
+	}
+	if js.Now() != 1100*time.Millisecond {
+		t.Errorf("Now() = %v, want 1.1s", js.Now())
+	}
+}
+
+func TestAnimationFrame(t *testing.T) {
+	js.Reset()
+	var window = js.Global()
+	var frames []float64
+	var frame js.Func
+	frame = js.FuncOf(func(this js.Value, args []js.Value) any {
+		frames = append(frames, args[0].Float())
+		if len(frames) < 3 {
+			window.Call("requestAnimationFrame", frame)
+		}
+		return nil
+	})
+	window.Call("requestAnimationFrame", frame)
+	js.Advance(time.Second)
+	if len(frames) != 3 {
+		t.Fatalf("got %d frames, want 3", len(frames))
+	}
+	for i := 1; i < len(frames); i++ {
+		if frames[i] <= frames[i-1] {
+			t.Errorf("frame %d at %vms, not after %vms", i, frames[i], frames[i-1])
+		}
+	}
+}
+
+func TestInnerHTMLRoundTrip(t *testing.T) {
+	var b = body(t, `<p class="x">a &amp; <b>b</b></p>`)
+	var p = b.Call("querySelector", "p")
+	p.Get("classList").Call("add", "y")
+	p.Get("style").Set("color", "red")
+	var got = b.Get("innerHTML").String()
+	var want = `<p class="x y" style="color: red;">a &amp; <b>b</b></p>`
+	if got != want {
+		t.Errorf("innerHTML = %s, want %s", got, want)
+	}
+	if !strings.Contains(p.Get("textContent").String(), "a & b") {
+		t.Errorf("textContent = %q, want a & b", p.Get("textContent").String())
+	}
+}
+
+func TestGetRandomValues(t *testing.T) {
+	var a = js.Global().Get("Uint8Array").New(32)
+	js.Global().Get("crypto").Call("getRandomValues", a)
+	var b = make([]byte, 32)
+	js.CopyBytesToGo(b, a)
+	if string(b) == string(make([]byte, 32)) {
+		t.Errorf("getRandomValues left the array empty")
+	}
+}
+
+func TestStyleSetProperty(t *testing.T) {
+	var b = body(t, `<p></p>`)
+	var p = b.Call("querySelector", "p")
+	var style = p.Get("style")
+	style.Call("setProperty", "margin-top", "4px")
+	style.Call("setProperty", "color", "red", "important")
+	style.Call("setProperty", "width", "1px", "invalid")
+	if got := style.Call("getPropertyValue", "margin-top").String(); got != "4px" {
+		t.Errorf("margin-top = %q, want 4px", got)
+	}
+	if got := p.Call("getAttribute", "style").String(); got != "margin-top: 4px; color: red !important;" {
+		t.Errorf("style attribute = %q", got)
+	}
+}
+
+func TestInnerHTMLRawText(t *testing.T) {
+	var b = body(t, `<style>a > b {}</style><script>if (a < b && c) {}</script>`)
+	var want = `a > b {}`
+	if got := b.Call("querySelector", "style").Get("innerHTML").String(); got != want {
+		t.Errorf("style innerHTML = %q, want %q", got, want)
+	}
+	want = `<style>a > b {}</style><script>if (a < b && c) {}</script>`
+	if got := b.Get("innerHTML").String(); got != want {
+		t.Errorf("body innerHTML = %q, want %q", got, want)
+	}
+}
+
+func TestPromise(t *testing.T) {
+	js.Reset()
+	var promise = js.Global().Get("Promise")
+	var results []string
+	var record = func(prefix string) js.Func {
+		return js.FuncOf(func(this js.Value, args []js.Value) any {
+			results = append(results, prefix+args[0].String())
+			return args[0].String() + "!"
+		})
+	}
+
+	var resolve js.Value
+	var p = promise.New(js.FuncOf(func(this js.Value, args []js.Value) any {
+		resolve = args[0]
+		return nil
+	}))
+	p.Call("then", record("then:")).Call("then", record("chained:"))
+	resolve.Invoke(promise.Call("resolve", "a"))
+	promise.Call("reject", "b").Call("then", record("skipped:")).Call("catch", record("catch:"))
+	promise.New(js.FuncOf(func(this js.Value, args []js.Value) any {
+		panic(js.Error{Value: js.Global().Get("Error").New("c")})
+	})).Call("catch", js.FuncOf(func(this js.Value, args []js.Value) any {
+		results = append(results, "thrown:"+args[0].Get("message").String())
+		return nil
+	}))
+	promise.Call("all", []any{1, promise.Call("resolve", 2)}).Call("then", js.FuncOf(func(this js.Value, args []js.Value) any {
+		results = append(results, "all:"+strconv.Itoa(args[0].Length()))
+		return nil
+	}))
+
+	var want = []string{"then:a", "chained:a!", "catch:b", "thrown:c", "all:2"}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("promises settled with %v, want %v", results, want)
+	}
+}
@@ -0,0 +1,305 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package jsv
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+var global *object
+
+// Global returns the window object.
+func Global() Value {
+	if global == nil {
+		global = newObject(eventTargetProto())
+		initGlobal(global)
+	}
+	return global.value()
+}
+
+func initGlobal(g *object) {
+	var w = g.value()
+	g.define("window", w)
+	g.define("self", w)
+	g.define("globalThis", w)
+	g.define("document", document().value())
+	g.define("Infinity", number(math.Inf(1)))
+	g.define("NaN", number(math.NaN()))
+
+	var object = constructor(objectProto(), func(args []Value) Value {
+		if a := arg(args, 0); a.t.isObject() {
+			return a
+		}
+		return newPlainObject()
+	})
+	object.o.method("keys", func(this Value, args []Value) Value {
+		var keys []Value
+		if a := arg(args, 0); a.t.isObject() {
+			for _, k := range a.o.ownKeys() {
+				keys = append(keys, str(k))
+			}
+		}
+		return arrayOf(keys)
+	})
+	object.o.method("values", func(this Value, args []Value) Value {
+		var values []Value
+		if a := arg(args, 0); a.t.isObject() {
+			for _, k := range a.o.ownKeys() {
+				values = append(values, a.Get(k))
+			}
+		}
+		return arrayOf(values)
+	})
+	object.o.method("entries", func(this Value, args []Value) Value {
+		var entries []Value
+		if a := arg(args, 0); a.t.isObject() {
+			for _, k := range a.o.ownKeys() {
+				entries = append(entries, arrayOf([]Value{str(k), a.Get(k)}))
+			}
+		}
+		return arrayOf(entries)
+	})
+	object.o.method("assign", func(this Value, args []Value) Value {
+		var target = arg(args, 0)
+		for _, src := range args[min(1, len(args)):] {
+			if src.t.isObject() {
+				for _, k := range src.o.ownKeys() {
+					target.Set(k, src.Get(k))
+				}
+			}
+		}
+		return target
+	})
+	object.o.method("create", func(this Value, args []Value) Value {
+		var p = arg(args, 0)
+		switch {
+		case p.t.isObject():
+			return newObject(p.o).value()
+		case p.t != TypeNull:
+			throw("TypeError", "Object prototype may only be an Object or null")
+		}
+		return newObject(nil).value()
+	})
+	// Properties with a get or set function are not enumerable, as in the browser.
+	object.o.method("defineProperty", func(this Value, args []Value) Value {
+		var target, key, desc = arg(args, 0), argString(args, 1), arg(args, 2)
+		if !target.t.isObject() || !desc.t.isObject() {
+			throw("TypeError", "Object.defineProperty called on non-object")
+		}
+		var get, set = desc.Get("get"), desc.Get("set")
+		if get.IsUndefined() && set.IsUndefined() {
+			delete(target.o.accessors, key)
+			if desc.Get("enumerable").Truthy() {
+				target.o.define(key, desc.Get("value"))
+				return target
+			}
+			target.o.deleteProp(key)
+			if target.o.props == nil {
+				target.o.props = make(map[string]Value)
+			}
+			target.o.props[key] = desc.Get("value")
+			return target
+		}
+		target.o.deleteProp(key)
+		if target.o.accessors == nil {
+			target.o.accessors = make(map[string]accessor)
+		}
+		target.o.accessors[key] = accessor{get: get, set: set}
+		return target
+	})
+	g.define("Object", object)
+
+	var array = constructor(arrayProto(), func(args []Value) Value {
+		if len(args) == 1 && args[0].t == TypeNumber {
+			return newArray(int(args[0].n))
+		}
+		return arrayOf(append([]Value(nil), args...))
+	})
+	array.o.method("isArray", func(this Value, args []Value) Value {
+		var a = arg(args, 0)
+		return boolean(a.t.isObject() && a.o.isArray)
+	})
+	array.o.method("from", func(this Value, args []Value) Value {
+		var a = arg(args, 0)
+		if !a.t.isObject() {
+			return arrayOf(nil)
+		}
+		var list = make([]Value, a.Length())
+		for i := range list {
+			list[i] = a.Index(i)
+		}
+		return arrayOf(list)
+	})
+	g.define("Array", array)
+
+	var uint8Array = constructor(newObject(objectProto()), func(args []Value) Value {
+		var b []byte
+		switch a := arg(args, 0); {
+		case a.t == TypeNumber:
+			b = make([]byte, int(a.n))
+		case a.t.isObject():
+			b = make([]byte, a.Length())
+			for i := range b {
+				b[i] = byte(int(toNumber(a.Index(i))))
+			}
+		}
+		return uint8ArrayOf(b)
+	})
+	g.define("Uint8Array", uint8Array)
+	g.define("Uint8ClampedArray", uint8Array)
+
+	var errorProto = newObject(objectProto())
+	errorProto.method("toString", func(this Value, args []Value) Value {
+		return str(toString(this.Get("name")) + ": " + toString(this.Get("message")))
+	})
+	for _, name := range []string{"Error", "TypeError", "SyntaxError", "RangeError"} {
+		var name = name
+		var p = errorProto
+		if name != "Error" {
+			p = newObject(errorProto)
+		}
+		g.define(name, constructor(p, func(args []Value) Value {
+			var e = newObject(p).value()
+			e.Set("name", name)
+			e.Set("message", nullableString(arg(args, 0)))
+			return e
+		}))
+	}
+
+	g.define("EventTarget", constructor(eventTargetProto(), func(args []Value) Value {
+		return newObject(eventTargetProto()).value()
+	}))
+	g.define("Event", constructor(eventProto(), func(args []Value) Value {
+		return newEvent(eventProto(), argString(args, 0), arg(args, 1))
+	}))
+	var customEvent = newObject(eventProto())
+	g.define("CustomEvent", constructor(customEvent, func(args []Value) Value {
+		var e = newEvent(customEvent, argString(args, 0), arg(args, 1))
+		if e.Get("detail").IsUndefined() {
+			e.Set("detail", nil)
+		}
+		return e
+	}))
+
+	// DOM interfaces can only be used with instanceof.
+	var illegal = func(args []Value) Value {
+		throw("TypeError", "Illegal constructor")
+		return Undefined()
+	}
+	g.define("Node", constructor(nodeProto(), illegal))
+	g.define("Element", constructor(elementProto(), illegal))
+	g.define("HTMLElement", constructor(elementProto(), illegal))
+	g.define("CharacterData", constructor(characterDataProto(), illegal))
+	g.define("Text", constructor(textProto(), illegal))
+	g.define("Comment", constructor(commentProto(), illegal))
+	g.define("Document", constructor(documentProto(), illegal))
+	g.define("DocumentFragment", constructor(fragmentProto(), illegal))
+	initEventInterfaces(g)
+	initTimers(g)
+	initPromise(g)
+
+	// Objects are never collected by the garbage collector here, so the cleanup callbacks are never called.
+	var registry = newObject(objectProto())
+	registry.method("register", func(this Value, args []Value) Value { return Undefined() })
+	registry.method("unregister", func(this Value, args []Value) Value { return boolean(false) })
+	g.define("FinalizationRegistry", constructor(registry, func(args []Value) Value {
+		return newObject(registry).value()
+	}))
+
+	// Targets are never collected, deref always returns them.
+	var weakRef = newObject(objectProto())
+	weakRef.method("deref", func(this Value, args []Value) Value {
+		if this.t.isObject() {
+			if target, ok := this.o.internal.(Value); ok {
+				return target
+			}
+		}
+		return Undefined()
+	})
+	g.define("WeakRef", constructor(weakRef, func(args []Value) Value {
+		var target = arg(args, 0)
+		if !target.t.isObject() {
+			throw("TypeError", "WeakRef: target must be an object")
+		}
+		var ref = newObject(weakRef)
+		ref.internal = target
+		return ref.value()
+	}))
+
+	var parser = newObject(objectProto())
+	parser.method("parseFromString", func(this Value, args []Value) Value {
+		return parseDocument(argString(args, 0)).value()
+	})
+	g.define("DOMParser", constructor(parser, func(args []Value) Value {
+		return newObject(parser).value()
+	}))
+
+	var navigator = newPlainObject()
+	navigator.Set("userAgent", "jsv")
+	navigator.Set("language", "en-US")
+	g.define("navigator", navigator)
+
+	var crypto = newPlainObject()
+	crypto.o.method("getRandomValues", func(this Value, args []Value) Value {
+		var a = arg(args, 0)
+		if !a.t.isObject() || a.o.bytes == nil {
+			throw("TypeError", "Failed to execute 'getRandomValues' on 'Crypto': parameter 1 is not of type 'ArrayBufferView'.")
+		}
+		rand.Read(*a.o.bytes)
+		return a
+	})
+	g.define("crypto", crypto)
+	g.define("console", console())
+
+	g.method("getComputedStyle", func(this Value, args []Value) Value {
+		// There are no stylesheets or layout, only the inline styles are known.
+		return view(styleProto(), mustNode(arg(args, 0), "getComputedStyle"))
+	})
+	g.method("eval", func(this Value, args []Value) Value {
+		throw("EvalError", "eval is not available outside of the browser")
+		return Undefined()
+	})
+}
+
+func uint8ArrayOf(b []byte) Value {
+	var o = newObject(Global().Get("Uint8Array").Get("prototype").o)
+	o.bytes = &b
+	return o.value()
+}
+
+// console writes its output to stderr.
+func console() Value {
+	var c = newPlainObject()
+	var print = func(prefix string) func(this Value, args []Value) Value {
+		return func(this Value, args []Value) Value {
+			var parts = make([]string, len(args))
+			for i, a := range args {
+				parts[i] = toString(a)
+			}
+			fmt.Fprintln(os.Stderr, prefix+strings.Join(parts, " "))
+			return Undefined()
+		}
+	}
+	for _, name := range []string{"log", "info", "debug", "dir", "dirxml", "group", "groupCollapsed", "table", "trace"} {
+		c.o.method(name, print(""))
+	}
+	c.o.method("warn", print("warning: "))
+	c.o.method("error", print("error: "))
+	c.o.method("assert", func(this Value, args []Value) Value {
+		if !arg(args, 0).Truthy() {
+			print("Assertion failed: ")(this, args[min(1, len(args)):])
+		}
+		return Undefined()
+	})
+	for _, name := range []string{"clear", "count", "countReset", "groupEnd", "time", "timeEnd", "timeLog"} {
+		c.o.method(name, func(this Value, args []Value) Value {
+			return Undefined()
+		})
+	}
+	return c
+}
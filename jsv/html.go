@@ -0,0 +1,182 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package jsv
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+func shortNamespace(ns string) string {
+	switch ns {
+	case NamespaceSVG:
+		return "svg"
+	case NamespaceMathML:
+		return "math"
+	}
+	return ""
+}
+
+func longNamespace(ns string) string {
+	switch ns {
+	case "svg":
+		return NamespaceSVG
+	case "math":
+		return NamespaceMathML
+	}
+	return NamespaceHTML
+}
+
+// toHTML converts the node to an x/net/html node for rendering.
+func toHTML(n *node) *html.Node {
+	var h = &html.Node{}
+	switch n.kind {
+	case elementNode:
+		h.Type = html.ElementNode
+		h.Data = n.name
+		h.DataAtom = atom.Lookup([]byte(n.name))
+		h.Namespace = shortNamespace(n.ns)
+		for _, a := range n.attrs {
+			h.Attr = append(h.Attr, html.Attribute{Key: a.name, Val: a.value})
+		}
+	case textNode:
+		h.Type = html.TextNode
+		h.Data = n.data
+	case commentNode:
+		h.Type = html.CommentNode
+		h.Data = n.data
+	case doctypeNode:
+		h.Type = html.DoctypeNode
+		h.Data = n.name
+	case documentNode, fragmentNode:
+		h.Type = html.DocumentNode
+	}
+	for _, c := range n.children {
+		h.AppendChild(toHTML(c))
+	}
+	return h
+}
+
+// fromHTML converts a parsed x/net/html node to a node.
+func fromHTML(h *html.Node) *node {
+	var n *node
+	switch h.Type {
+	case html.ElementNode:
+		n = newElement(longNamespace(h.Namespace), h.Data)
+		for _, a := range h.Attr {
+			var ns, name = "", a.Key
+			if a.Namespace != "" {
+				ns, name = attributeNamespace(a.Namespace), a.Namespace+":"+a.Key
+			}
+			n.attrs = append(n.attrs, attr{ns: ns, name: name, value: a.Val})
+		}
+	case html.TextNode:
+		n = newText(h.Data)
+	case html.CommentNode:
+		n = newComment(h.Data)
+	case html.DoctypeNode:
+		n = newNode(doctypeNode, doctypeProto())
+		n.name = h.Data
+	case html.DocumentNode:
+		n = newNode(documentNode, documentProto())
+	default:
+		return nil
+	}
+	for c := h.FirstChild; c != nil; c = c.NextSibling {
+		if child := fromHTML(c); child != nil {
+			child.parent = n
+			n.children = append(n.children, child)
+		}
+	}
+	return n
+}
+
+func attributeNamespace(prefix string) string {
+	switch prefix {
+	case "xlink":
+		return "http://www.w3.org/1999/xlink"
+	case "xml":
+		return "http://www.w3.org/XML/1998/namespace"
+	case "xmlns":
+		return "http://www.w3.org/2000/xmlns/"
+	}
+	return ""
+}
+
+func render(h *html.Node) string {
+	var b strings.Builder
+	if err := html.Render(&b, h); err != nil {
+		throw("Error", err.Error())
+	}
+	return b.String()
+}
+
+func outerHTML(n *node) string {
+	return render(toHTML(n))
+}
+
+func innerHTML(n *node) string {
+	var b strings.Builder
+	var h = toHTML(n)
+	for c := h.FirstChild; c != nil; c = c.NextSibling {
+		// The text of script and style elements is not escaped, as when the parent is rendered.
+		if c.Type == html.TextNode && h.Namespace == "" && rawText[h.Data] {
+			b.WriteString(c.Data)
+			continue
+		}
+		b.WriteString(render(c))
+	}
+	return b.String()
+}
+
+// rawText are the elements of which the text is rendered without escaping.
+var rawText = map[string]bool{
+	"iframe":    true,
+	"noembed":   true,
+	"noframes":  true,
+	"noscript":  true,
+	"plaintext": true,
+	"script":    true,
+	"style":     true,
+	"xmp":       true,
+}
+
+// parseFragment parses markup in the context of the element, like innerHTML.
+func parseFragment(context *node, markup string) *node {
+	var ctx = &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	if context.kind == elementNode {
+		ctx.Data = context.name
+		ctx.DataAtom = atom.Lookup([]byte(context.name))
+		ctx.Namespace = shortNamespace(context.ns)
+	}
+	var nodes, err = html.ParseFragment(strings.NewReader(markup), ctx)
+	if err != nil {
+		throw("SyntaxError", err.Error())
+	}
+	var frag = newFragment()
+	for _, h := range nodes {
+		if c := fromHTML(h); c != nil {
+			c.parent = frag
+			frag.children = append(frag.children, c)
+		}
+	}
+	return frag
+}
+
+func setInnerHTML(n *node, markup string) {
+	var frag = parseFragment(n, markup)
+	n.removeChildren()
+	n.insertBefore(frag, nil)
+}
+
+// parseDocument parses a complete HTML document.
+func parseDocument(markup string) *node {
+	var h, err = html.Parse(strings.NewReader(markup))
+	if err != nil {
+		throw("SyntaxError", err.Error())
+	}
+	return fromHTML(h)
+}
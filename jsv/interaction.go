@@ -0,0 +1,135 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package jsv
+
+// activeElement is the focused element, nil if the body has focus.
+var activeElement *node
+
+func newEventOf(name, typ string, init map[string]any) Value {
+	return Global().Get(name).New(typ, init)
+}
+
+// focus moves the focus to n, or to the body if n is nil.
+func focus(n *node) {
+	if n == activeElement {
+		return
+	}
+	var old = activeElement
+	activeElement = n
+	if old != nil {
+		var related any = nil
+		if n != nil {
+			related = n.value()
+		}
+		dispatch(old.value(), newEventOf("FocusEvent", "blur", map[string]any{"relatedTarget": related}))
+		dispatch(old.value(), newEventOf("FocusEvent", "focusout", map[string]any{"bubbles": true, "relatedTarget": related}))
+	}
+	if n != nil {
+		var related any = nil
+		if old != nil {
+			related = old.value()
+		}
+		dispatch(n.value(), newEventOf("FocusEvent", "focus", map[string]any{"relatedTarget": related}))
+		dispatch(n.value(), newEventOf("FocusEvent", "focusin", map[string]any{"bubbles": true, "relatedTarget": related}))
+	}
+}
+
+// form returns the form the control belongs to.
+func (n *node) form() *node {
+	if id, ok := n.getAttr("form"); ok {
+		var found = document().querySelector("form[id=\""+id+"\"]", false)
+		if len(found) > 0 {
+			return found[0]
+		}
+		return nil
+	}
+	for p := n.parent; p != nil; p = p.parent {
+		if p.kind == elementNode && p.name == "form" {
+			return p
+		}
+	}
+	return nil
+}
+
+// click dispatches a click event, and performs the default action of checkboxes, radio buttons and submit buttons.
+func click(n *node) {
+	if _, disabled := n.getAttr("disabled"); disabled && isFormControl(n) {
+		return
+	}
+	var typ = n.attr("type")
+	var checkable = n.name == "input" && (typ == "checkbox" || typ == "radio")
+	var _, wasChecked = n.getAttr("checked")
+	var unchecked *node
+	if checkable {
+		if typ == "checkbox" {
+			n.toggleAttr("checked", !wasChecked)
+		} else if !wasChecked {
+			unchecked = n.radioGroupChecked()
+			if unchecked != nil {
+				unchecked.removeAttr("checked")
+			}
+			n.setAttr("checked", "")
+		}
+	}
+
+	var ok = dispatch(n.value(), newEventOf("MouseEvent", "click", map[string]any{
+		"bubbles": true, "cancelable": true, "composed": true, "detail": 1,
+	}))
+
+	if checkable {
+		var _, checked = n.getAttr("checked")
+		if !ok {
+			n.toggleAttr("checked", wasChecked)
+			if unchecked != nil {
+				unchecked.setAttr("checked", "")
+			}
+			return
+		}
+		if checked != wasChecked {
+			dispatch(n.value(), newEventOf("Event", "input", map[string]any{"bubbles": true, "composed": true}))
+			dispatch(n.value(), newEventOf("Event", "change", map[string]any{"bubbles": true}))
+		}
+		return
+	}
+	if !ok {
+		return
+	}
+	var submits = (n.name == "button" && (typ == "" || typ == "submit")) || (n.name == "input" && (typ == "submit" || typ == "image"))
+	if form := n.form(); submits && form != nil {
+		dispatch(form.value(), newEventOf("SubmitEvent", "submit", map[string]any{
+			"bubbles": true, "cancelable": true, "submitter": n.value(),
+		}))
+	}
+}
+
+func (n *node) toggleAttr(name string, on bool) {
+	if on {
+		n.setAttr(name, "")
+	} else {
+		n.removeAttr(name)
+	}
+}
+
+// radioGroupChecked returns the checked radio button in the group of n.
+func (n *node) radioGroupChecked() *node {
+	var name = n.attr("name")
+	if name == "" {
+		return nil
+	}
+	var scope = n.form()
+	if scope == nil {
+		scope = n.root()
+	}
+	var found *node
+	scope.walk(func(c *node) bool {
+		if c != n && c.name == "input" && c.attr("type") == "radio" && c.attr("name") == name {
+			if _, checked := c.getAttr("checked"); checked && c.form() == n.form() {
+				found = c
+				return false
+			}
+		}
+		return true
+	})
+	return found
+}
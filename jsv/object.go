@@ -0,0 +1,389 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package jsv
+
+import (
+	"strconv"
+)
+
+// object is a JavaScript object, array or function.
+type object struct {
+	props map[string]Value
+	keys  []string
+	proto *object
+
+	isArray bool
+	array   []Value
+	bytes   *[]byte
+
+	// call is set for functions, construct for native constructors.
+	call      func(this Value, args []Value) Value
+	construct func(args []Value) Value
+	released  bool
+
+	// Accessor hooks, these also apply to objects which inherit from this one.
+	// Own properties of the object take precedence over its get hook.
+	get func(this Value, key string) (Value, bool)
+	set func(this Value, key string, v Value) bool
+
+	// accessors are the properties defined with getters or setters by Object.defineProperty.
+	accessors map[string]accessor
+
+	// internal holds the Go value behind host objects, like a DOM node.
+	internal any
+	events   map[string][]*listener
+}
+
+type accessor struct {
+	get, set Value
+}
+
+func newObject(proto *object) *object {
+	return &object{proto: proto}
+}
+
+func (o *object) value() Value {
+	if o.call != nil || o.construct != nil {
+		return Value{t: TypeFunction, o: o}
+	}
+	return Value{t: TypeObject, o: o}
+}
+
+func newPlainObject() Value {
+	return newObject(objectProto()).value()
+}
+
+func newArray(length int) Value {
+	var o = newObject(arrayProto())
+	o.isArray = true
+	o.array = make([]Value, length)
+	return o.value()
+}
+
+func arrayOf(values []Value) Value {
+	var a = newArray(0)
+	a.o.array = values
+	return a
+}
+
+func (o *object) getProp(key string, this Value) Value {
+	for cur := o; cur != nil; cur = cur.proto {
+		if a, ok := cur.accessors[key]; ok {
+			if a.get.t != TypeFunction {
+				return Undefined()
+			}
+			return a.get.o.invoke(this, nil)
+		}
+		if v, ok := cur.props[key]; ok {
+			return v
+		}
+		if cur.isArray || cur.bytes != nil {
+			if v, ok := cur.indexed(key); ok {
+				return v
+			}
+		}
+		if cur.get != nil {
+			if v, ok := cur.get(this, key); ok {
+				return v
+			}
+		}
+	}
+	return Undefined()
+}
+
+func (o *object) indexed(key string) (Value, bool) {
+	var length int
+	if o.bytes != nil {
+		length = len(*o.bytes)
+	} else {
+		length = len(o.array)
+	}
+	if key == "length" || (o.bytes != nil && key == "byteLength") {
+		return number(float64(length)), true
+	}
+	var i, err = strconv.Atoi(key)
+	if err != nil || i < 0 {
+		return Value{}, false
+	}
+	if i >= length {
+		return Undefined(), true
+	}
+	if o.bytes != nil {
+		return number(float64((*o.bytes)[i])), true
+	}
+	return o.array[i], true
+}
+
+func (o *object) setProp(key string, v Value, this Value) {
+	for cur := o; cur != nil; cur = cur.proto {
+		if a, ok := cur.accessors[key]; ok {
+			if a.set.t == TypeFunction {
+				a.set.o.invoke(this, []Value{v})
+			}
+			return
+		}
+		if cur.set != nil && cur.set(this, key, v) {
+			return
+		}
+	}
+	if o.isArray {
+		if key == "length" {
+			var n = int(toNumber(v))
+			for len(o.array) < n {
+				o.array = append(o.array, Undefined())
+			}
+			o.array = o.array[:n]
+			return
+		}
+		if i, err := strconv.Atoi(key); err == nil && i >= 0 {
+			for len(o.array) <= i {
+				o.array = append(o.array, Undefined())
+			}
+			o.array[i] = v
+			return
+		}
+	}
+	if o.bytes != nil {
+		if i, err := strconv.Atoi(key); err == nil {
+			if i >= 0 && i < len(*o.bytes) {
+				(*o.bytes)[i] = byte(int(toNumber(v)))
+			}
+			return
+		}
+	}
+	o.define(key, v)
+}
+
+func (o *object) deleteProp(key string) {
+	if _, ok := o.props[key]; !ok {
+		return
+	}
+	delete(o.props, key)
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i], o.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// ownKeys returns the enumerable own keys, like Object.keys.
+func (o *object) ownKeys() []string {
+	var keys []string
+	if o.isArray {
+		for i := range o.array {
+			keys = append(keys, strconv.Itoa(i))
+		}
+	}
+	return append(keys, o.keys...)
+}
+
+func (o *object) invoke(this Value, args []Value) Value {
+	if o.released {
+		panic("call to released function")
+	}
+	if o.call == nil {
+		if o.construct != nil {
+			throw("TypeError", "class constructors must be invoked with 'new'")
+		}
+		panic(&ValueError{Method: "Value.Invoke", Type: TypeObject})
+	}
+	return o.call(this, args)
+}
+
+// throw panics with a JavaScript error, like a thrown exception in the browser.
+func throw(name, message string) {
+	var e = newPlainObject()
+	e.Set("name", name)
+	e.Set("message", message)
+	panic(Error{Value: e})
+}
+
+// native returns a function implemented in Go.
+func native(fn func(this Value, args []Value) Value) Value {
+	var o = newObject(functionProto())
+	o.call = fn
+	return o.value()
+}
+
+// define sets an own property, without calling accessor hooks.
+func (o *object) define(name string, v Value) {
+	if o.props == nil {
+		o.props = make(map[string]Value)
+	}
+	if _, ok := o.props[name]; !ok {
+		o.keys = append(o.keys, name)
+	}
+	o.props[name] = v
+}
+
+// method defines a native method on the object.
+func (o *object) method(name string, fn func(this Value, args []Value) Value) {
+	o.define(name, native(fn))
+}
+
+// constructor returns a native constructor with its prototype.
+func constructor(proto *object, construct func(args []Value) Value) Value {
+	var o = newObject(functionProto())
+	o.construct = construct
+	var c = o.value()
+	o.define("prototype", proto.value())
+	proto.define("constructor", c)
+	return c
+}
+
+func arg(args []Value, i int) Value {
+	if i < len(args) {
+		return args[i]
+	}
+	return Undefined()
+}
+
+func argString(args []Value, i int) string {
+	return toString(arg(args, i))
+}
+
+var (
+	objProto, fnProto, arrProto *object
+)
+
+func objectProto() *object {
+	if objProto == nil {
+		objProto = newObject(nil)
+		objProto.method("hasOwnProperty", func(this Value, args []Value) Value {
+			var _, ok = this.o.props[argString(args, 0)]
+			return boolean(ok)
+		})
+		objProto.method("toString", func(this Value, args []Value) Value {
+			return str("[object Object]")
+		})
+	}
+	return objProto
+}
+
+func functionProto() *object {
+	if fnProto == nil {
+		fnProto = newObject(objectProto())
+		fnProto.method("call", func(this Value, args []Value) Value {
+			return this.o.invoke(arg(args, 0), args[min(1, len(args)):])
+		})
+		fnProto.method("apply", func(this Value, args []Value) Value {
+			var list []Value
+			if a := arg(args, 1); a.t.isObject() && a.o.isArray {
+				list = a.o.array
+			}
+			return this.o.invoke(arg(args, 0), list)
+		})
+	}
+	return fnProto
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func arrayProto() *object {
+	if arrProto != nil {
+		return arrProto
+	}
+	arrProto = newObject(objectProto())
+	arrProto.method("push", func(this Value, args []Value) Value {
+		this.o.array = append(this.o.array, args...)
+		return number(float64(len(this.o.array)))
+	})
+	arrProto.method("pop", func(this Value, args []Value) Value {
+		if len(this.o.array) == 0 {
+			return Undefined()
+		}
+		var last = this.o.array[len(this.o.array)-1]
+		this.o.array = this.o.array[:len(this.o.array)-1]
+		return last
+	})
+	arrProto.method("concat", func(this Value, args []Value) Value {
+		var list = append([]Value(nil), this.o.array...)
+		for _, a := range args {
+			if a.t.isObject() && a.o.isArray {
+				list = append(list, a.o.array...)
+			} else {
+				list = append(list, a)
+			}
+		}
+		return arrayOf(list)
+	})
+	arrProto.method("slice", func(this Value, args []Value) Value {
+		var start, end = sliceBounds(len(this.o.array), args)
+		return arrayOf(append([]Value(nil), this.o.array[start:end]...))
+	})
+	arrProto.method("indexOf", func(this Value, args []Value) Value {
+		for i, v := range this.o.array {
+			if v.Equal(arg(args, 0)) {
+				return number(float64(i))
+			}
+		}
+		return number(-1)
+	})
+	arrProto.method("includes", func(this Value, args []Value) Value {
+		for _, v := range this.o.array {
+			if v.Equal(arg(args, 0)) {
+				return boolean(true)
+			}
+		}
+		return boolean(false)
+	})
+	arrProto.method("join", func(this Value, args []Value) Value {
+		var sep = ","
+		if a := arg(args, 0); !a.IsUndefined() {
+			sep = toString(a)
+		}
+		var s string
+		for i, v := range this.o.array {
+			if i > 0 {
+				s += sep
+			}
+			if !v.IsUndefined() && !v.IsNull() {
+				s += toString(v)
+			}
+		}
+		return str(s)
+	})
+	arrProto.method("forEach", func(this Value, args []Value) Value {
+		var fn = arg(args, 0)
+		for i, v := range append([]Value(nil), this.o.array...) {
+			fn.o.invoke(Undefined(), []Value{v, number(float64(i)), this})
+		}
+		return Undefined()
+	})
+	arrProto.method("toString", func(this Value, args []Value) Value {
+		return str(toString(this))
+	})
+	return arrProto
+}
+
+func sliceBounds(length int, args []Value) (int, int) {
+	var clampIndex = func(v Value, def int) int {
+		if v.IsUndefined() {
+			return def
+		}
+		var i = int(toNumber(v))
+		if i < 0 {
+			i += length
+		}
+		if i < 0 {
+			return 0
+		}
+		if i > length {
+			return length
+		}
+		return i
+	}
+	var start = clampIndex(arg(args, 0), 0)
+	var end = clampIndex(arg(args, 1), length)
+	if end < start {
+		end = start
+	}
+	return start, end
+}
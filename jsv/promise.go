@@ -0,0 +1,222 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package jsv
+
+import (
+	"sync"
+)
+
+// Promise states.
+const (
+	pending = iota
+	fulfilled
+	rejected
+)
+
+// promise is the state of a Promise.
+//
+// Reactions run as soon as the promise settles instead of in a microtask,
+// so a goroutine can wait for a promise which is settled by another goroutine.
+type promise struct {
+	mu        sync.Mutex
+	state     int
+	result    Value
+	reactions []func(state int, result Value)
+}
+
+func promiseOf(v Value) *promise {
+	if !v.t.isObject() {
+		return nil
+	}
+	var p, _ = v.o.internal.(*promise)
+	return p
+}
+
+func newPromise() (Value, *promise) {
+	var p = &promise{}
+	var o = newObject(promiseProto())
+	o.internal = p
+	return o.value(), p
+}
+
+// resolve fulfills the promise with the value, or follows it if it is a thenable.
+func (p *promise) resolve(v Value) {
+	if v.t.isObject() {
+		if then := v.Get("then"); then.t == TypeFunction {
+			var once sync.Once
+			var settle = func(resolve bool) Value {
+				return native(func(this Value, args []Value) Value {
+					once.Do(func() {
+						if resolve {
+							p.resolve(arg(args, 0))
+						} else {
+							p.settle(rejected, arg(args, 0))
+						}
+					})
+					return Undefined()
+				})
+			}
+			p.try(func() { then.o.invoke(v, []Value{settle(true), settle(false)}) })
+			return
+		}
+	}
+	p.settle(fulfilled, v)
+}
+
+func (p *promise) settle(state int, result Value) {
+	p.mu.Lock()
+	if p.state != pending {
+		p.mu.Unlock()
+		return
+	}
+	p.state, p.result = state, result
+	var reactions = p.reactions
+	p.reactions = nil
+	p.mu.Unlock()
+	for _, fn := range reactions {
+		fn(state, result)
+	}
+}
+
+// try calls fn, a thrown error rejects the promise.
+func (p *promise) try(fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			var err, ok = r.(Error)
+			if !ok {
+				panic(r)
+			}
+			p.settle(rejected, err.Value)
+		}
+	}()
+	fn()
+}
+
+// then calls fn when the promise settles, or right away if it has settled.
+func (p *promise) then(fn func(state int, result Value)) {
+	p.mu.Lock()
+	if p.state == pending {
+		p.reactions = append(p.reactions, fn)
+		p.mu.Unlock()
+		return
+	}
+	var state, result = p.state, p.result
+	p.mu.Unlock()
+	fn(state, result)
+}
+
+// chain returns a promise which settles with the result of the handler for the state of p.
+func (p *promise) chain(onFulfilled, onRejected Value) Value {
+	var v, next = newPromise()
+	p.then(func(state int, result Value) {
+		var handler = onFulfilled
+		if state == rejected {
+			handler = onRejected
+		}
+		if handler.t != TypeFunction {
+			next.settle(state, result)
+			return
+		}
+		next.try(func() { next.resolve(handler.o.invoke(Undefined(), []Value{result})) })
+	})
+	return v
+}
+
+func mustPromise(this Value, method string) *promise {
+	var p = promiseOf(this)
+	if p == nil {
+		throw("TypeError", "Method Promise.prototype."+method+" called on incompatible receiver")
+	}
+	return p
+}
+
+func promiseProto() *object {
+	return proto("Promise", func(o *object) {
+		o.method("then", func(this Value, args []Value) Value {
+			return mustPromise(this, "then").chain(arg(args, 0), arg(args, 1))
+		})
+		o.method("catch", func(this Value, args []Value) Value {
+			return mustPromise(this, "catch").chain(Undefined(), arg(args, 0))
+		})
+		o.method("finally", func(this Value, args []Value) Value {
+			var fn = arg(args, 0)
+			var v, next = newPromise()
+			mustPromise(this, "finally").then(func(state int, result Value) {
+				next.try(func() {
+					if fn.t == TypeFunction {
+						fn.o.invoke(Undefined(), nil)
+					}
+					next.settle(state, result)
+				})
+			})
+			return v
+		})
+	}, objectProto)
+}
+
+func initPromise(g *object) {
+	var c = constructor(promiseProto(), func(args []Value) Value {
+		var executor = arg(args, 0)
+		if executor.t != TypeFunction {
+			throw("TypeError", "Promise resolver is not a function")
+		}
+		var v, p = newPromise()
+		var resolve = native(func(this Value, args []Value) Value {
+			p.resolve(arg(args, 0))
+			return Undefined()
+		})
+		var reject = native(func(this Value, args []Value) Value {
+			p.settle(rejected, arg(args, 0))
+			return Undefined()
+		})
+		p.try(func() { executor.o.invoke(Undefined(), []Value{resolve, reject}) })
+		return v
+	})
+	c.o.method("resolve", func(this Value, args []Value) Value {
+		if promiseOf(arg(args, 0)) != nil {
+			return args[0]
+		}
+		var v, p = newPromise()
+		p.resolve(arg(args, 0))
+		return v
+	})
+	c.o.method("reject", func(this Value, args []Value) Value {
+		var v, p = newPromise()
+		p.settle(rejected, arg(args, 0))
+		return v
+	})
+	c.o.method("all", func(this Value, args []Value) Value {
+		var list = arg(args, 0)
+		var v, p = newPromise()
+		var n = list.Length()
+		var results = make([]Value, n)
+		var mu sync.Mutex
+		var remaining = n
+		if n == 0 {
+			p.settle(fulfilled, arrayOf(results))
+			return v
+		}
+		for i := 0; i < n; i++ {
+			var i = i
+			var item, next = newPromise()
+			next.resolve(list.Index(i))
+			promiseOf(item).then(func(state int, result Value) {
+				if state == rejected {
+					p.settle(rejected, result)
+					return
+				}
+				mu.Lock()
+				results[i] = result
+				remaining--
+				var done = remaining == 0
+				mu.Unlock()
+				if done {
+					p.settle(fulfilled, arrayOf(results))
+				}
+			})
+		}
+		return v
+	})
+	g.define("Promise", c)
+}
@@ -0,0 +1,516 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package jsv
+
+import (
+	"strconv"
+	"strings"
+)
+
+// selectorList is a parsed group of selectors, like "a, b > c".
+type selectorList []complexSelector
+
+// complexSelector is a list of compound selectors joined by combinators, from left to right.
+type complexSelector []compoundSelector
+
+type compoundSelector struct {
+	// combinator joins the selector to the one before it: ' ', '>', '+' or '~'.
+	combinator byte
+	tag        string
+	ids        []string
+	classes    []string
+	attrs      []attrSelector
+	pseudos    []pseudoSelector
+}
+
+type attrSelector struct {
+	name, op, value string
+	insensitive     bool
+}
+
+type pseudoSelector struct {
+	name string
+	list selectorList
+	a, b int
+}
+
+var selectorCache = make(map[string]selectorList)
+
+func parseSelector(text string) selectorList {
+	if list, ok := selectorCache[text]; ok {
+		return list
+	}
+	var p = &selectorParser{s: text}
+	var list = p.list()
+	p.space()
+	if p.i < len(p.s) {
+		p.fail()
+	}
+	selectorCache[text] = list
+	return list
+}
+
+type selectorParser struct {
+	s string
+	i int
+}
+
+func (p *selectorParser) fail() {
+	throw("SyntaxError", "'"+p.s+"' is not a valid selector.")
+}
+
+func (p *selectorParser) peek() byte {
+	if p.i < len(p.s) {
+		return p.s[p.i]
+	}
+	return 0
+}
+
+func (p *selectorParser) space() bool {
+	var start = p.i
+	for p.i < len(p.s) && strings.IndexByte(" \t\n\r\f", p.s[p.i]) >= 0 {
+		p.i++
+	}
+	return p.i > start
+}
+
+func isIdentByte(c byte) bool {
+	return c == '-' || c == '_' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func (p *selectorParser) ident() string {
+	var b strings.Builder
+	for p.i < len(p.s) {
+		var c = p.s[p.i]
+		if c == '\\' && p.i+1 < len(p.s) {
+			b.WriteByte(p.s[p.i+1])
+			p.i += 2
+			continue
+		}
+		if !isIdentByte(c) {
+			break
+		}
+		b.WriteByte(c)
+		p.i++
+	}
+	if b.Len() == 0 {
+		p.fail()
+	}
+	return b.String()
+}
+
+func (p *selectorParser) list() selectorList {
+	var list selectorList
+	for {
+		p.space()
+		list = append(list, p.complex())
+		p.space()
+		if p.peek() != ',' {
+			return list
+		}
+		p.i++
+	}
+}
+
+func (p *selectorParser) complex() complexSelector {
+	var sel = complexSelector{p.compound(' ')}
+	for {
+		var spaced = p.space()
+		var c = p.peek()
+		switch {
+		case c == '>' || c == '+' || c == '~':
+			p.i++
+			p.space()
+			sel = append(sel, p.compound(c))
+		case spaced && c != 0 && c != ',' && c != ')':
+			sel = append(sel, p.compound(' '))
+		default:
+			return sel
+		}
+	}
+}
+
+func (p *selectorParser) compound(combinator byte) compoundSelector {
+	var c = compoundSelector{combinator: combinator}
+	var start = p.i
+	if p.peek() == '*' {
+		p.i++
+	} else if isIdentByte(p.peek()) || p.peek() == '\\' {
+		c.tag = strings.ToLower(p.ident())
+	}
+	for {
+		switch p.peek() {
+		case '#':
+			p.i++
+			c.ids = append(c.ids, p.ident())
+		case '.':
+			p.i++
+			c.classes = append(c.classes, p.ident())
+		case '[':
+			p.i++
+			c.attrs = append(c.attrs, p.attr())
+		case ':':
+			p.i++
+			if p.peek() == ':' {
+				// Pseudo-elements never match elements.
+				p.fail()
+			}
+			c.pseudos = append(c.pseudos, p.pseudo())
+		default:
+			if p.i == start {
+				p.fail()
+			}
+			return c
+		}
+	}
+}
+
+func (p *selectorParser) attr() attrSelector {
+	p.space()
+	var a = attrSelector{name: strings.ToLower(p.ident())}
+	p.space()
+	if p.peek() == ']' {
+		p.i++
+		return a
+	}
+	for _, op := range []string{"=", "~=", "|=", "^=", "$=", "*="} {
+		if strings.HasPrefix(p.s[p.i:], op) {
+			a.op = op
+			p.i += len(op)
+			break
+		}
+	}
+	if a.op == "" {
+		p.fail()
+	}
+	p.space()
+	if q := p.peek(); q == '"' || q == '\'' {
+		var end = strings.IndexByte(p.s[p.i+1:], q)
+		if end < 0 {
+			p.fail()
+		}
+		a.value = p.s[p.i+1 : p.i+1+end]
+		p.i += end + 2
+	} else {
+		a.value = p.ident()
+	}
+	p.space()
+	if c := p.peek(); c == 'i' || c == 'I' {
+		a.insensitive = true
+		p.i++
+		p.space()
+	}
+	if p.peek() != ']' {
+		p.fail()
+	}
+	p.i++
+	return a
+}
+
+func (p *selectorParser) pseudo() pseudoSelector {
+	var ps = pseudoSelector{name: strings.ToLower(p.ident())}
+	switch ps.name {
+	case "not", "is", "where", "has":
+		p.open()
+		ps.list = p.list()
+		p.close()
+	case "nth-child", "nth-last-child", "nth-of-type", "nth-last-of-type":
+		p.open()
+		var end = strings.IndexByte(p.s[p.i:], ')')
+		if end < 0 {
+			p.fail()
+		}
+		var ok bool
+		ps.a, ps.b, ok = parseNth(p.s[p.i : p.i+end])
+		if !ok {
+			p.fail()
+		}
+		p.i += end
+		p.close()
+	case "first-child", "last-child", "only-child", "first-of-type", "last-of-type", "only-of-type",
+		"empty", "root", "scope", "checked", "disabled", "enabled", "required", "optional",
+		"focus", "focus-within", "focus-visible", "hover", "active", "link", "any-link", "defined":
+	default:
+		p.fail()
+	}
+	return ps
+}
+
+func (p *selectorParser) open() {
+	if p.peek() != '(' {
+		p.fail()
+	}
+	p.i++
+	p.space()
+}
+
+func (p *selectorParser) close() {
+	p.space()
+	if p.peek() != ')' {
+		p.fail()
+	}
+	p.i++
+}
+
+// parseNth parses the an+b syntax of :nth-child.
+func parseNth(s string) (a, b int, ok bool) {
+	s = strings.ToLower(strings.ReplaceAll(s, " ", ""))
+	switch s {
+	case "odd":
+		return 2, 1, true
+	case "even":
+		return 2, 0, true
+	}
+	var n = strings.IndexByte(s, 'n')
+	if n < 0 {
+		b, err := strconv.Atoi(s)
+		return 0, b, err == nil
+	}
+	switch s[:n] {
+	case "", "+":
+		a = 1
+	case "-":
+		a = -1
+	default:
+		var err error
+		if a, err = strconv.Atoi(s[:n]); err != nil {
+			return 0, 0, false
+		}
+	}
+	if rest := s[n+1:]; rest != "" {
+		var err error
+		if b, err = strconv.Atoi(rest); err != nil {
+			return 0, 0, false
+		}
+	}
+	return a, b, true
+}
+
+// matches reports whether the element matches the selector list, scope is the element for :scope.
+func (list selectorList) matches(n, scope *node) bool {
+	for _, sel := range list {
+		if sel.matches(n, len(sel)-1, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+func (sel complexSelector) matches(n *node, i int, scope *node) bool {
+	if !sel[i].matches(n, scope) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+	switch sel[i].combinator {
+	case '>':
+		var p = n.parent
+		return p != nil && p.kind == elementNode && sel.matches(p, i-1, scope)
+	case '+':
+		var prev = n.sibling(-1, true)
+		return prev != nil && sel.matches(prev, i-1, scope)
+	case '~':
+		for prev := n.sibling(-1, true); prev != nil; prev = prev.sibling(-1, true) {
+			if sel.matches(prev, i-1, scope) {
+				return true
+			}
+		}
+		return false
+	}
+	for p := n.parent; p != nil && p.kind == elementNode; p = p.parent {
+		if sel.matches(p, i-1, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *compoundSelector) matches(n *node, scope *node) bool {
+	if n.kind != elementNode {
+		return false
+	}
+	if c.tag != "" && !strings.EqualFold(c.tag, n.name) {
+		return false
+	}
+	for _, id := range c.ids {
+		if n.attr("id") != id {
+			return false
+		}
+	}
+	for _, class := range c.classes {
+		if !n.hasClass(class) {
+			return false
+		}
+	}
+	for _, a := range c.attrs {
+		if !a.matches(n) {
+			return false
+		}
+	}
+	for _, ps := range c.pseudos {
+		if !ps.matches(n, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a attrSelector) matches(n *node) bool {
+	var v, ok = n.getAttr(a.name)
+	if !ok {
+		return false
+	}
+	var want = a.value
+	if a.insensitive {
+		v, want = strings.ToLower(v), strings.ToLower(want)
+	}
+	switch a.op {
+	case "":
+		return true
+	case "=":
+		return v == want
+	case "~=":
+		return contains(strings.Fields(v), want)
+	case "|=":
+		return v == want || strings.HasPrefix(v, want+"-")
+	case "^=":
+		return want != "" && strings.HasPrefix(v, want)
+	case "$=":
+		return want != "" && strings.HasSuffix(v, want)
+	case "*=":
+		return want != "" && strings.Contains(v, want)
+	}
+	return false
+}
+
+func (ps pseudoSelector) matches(n, scope *node) bool {
+	switch ps.name {
+	case "not":
+		return !ps.list.matches(n, scope)
+	case "is", "where":
+		return ps.list.matches(n, scope)
+	case "has":
+		var found bool
+		n.walk(func(c *node) bool {
+			found = c.kind == elementNode && ps.list.matches(c, n)
+			return !found
+		})
+		return found
+	case "first-child":
+		return n.sibling(-1, true) == nil
+	case "last-child":
+		return n.sibling(1, true) == nil
+	case "only-child":
+		return n.sibling(-1, true) == nil && n.sibling(1, true) == nil
+	case "first-of-type", "last-of-type", "only-of-type":
+		var i, count = typeIndex(n, false)
+		switch ps.name {
+		case "first-of-type":
+			return i == 1
+		case "last-of-type":
+			return i == count
+		}
+		return count == 1
+	case "nth-child", "nth-last-child", "nth-of-type", "nth-last-of-type":
+		var i, count = typeIndex(n, !strings.HasSuffix(ps.name, "of-type"))
+		if strings.Contains(ps.name, "last") {
+			i = count - i + 1
+		}
+		return nthMatches(ps.a, ps.b, i)
+	case "empty":
+		for _, c := range n.children {
+			if c.kind == elementNode || (c.kind == textNode && c.data != "") {
+				return false
+			}
+		}
+		return true
+	case "root":
+		return n.parent != nil && n.parent.kind == documentNode
+	case "scope":
+		if scope == nil {
+			return n.parent != nil && n.parent.kind == documentNode
+		}
+		return n == scope
+	case "checked":
+		var _, checked = n.getAttr("checked")
+		var _, selected = n.getAttr("selected")
+		return (n.name == "input" && checked) || (n.name == "option" && selected)
+	case "disabled", "enabled":
+		var _, disabled = n.getAttr("disabled")
+		if !isFormControl(n) {
+			return false
+		}
+		return disabled == (ps.name == "disabled")
+	case "required", "optional":
+		var _, required = n.getAttr("required")
+		if !isFormControl(n) {
+			return false
+		}
+		return required == (ps.name == "required")
+	case "focus", "focus-visible":
+		return n == activeElement
+	case "focus-within":
+		return activeElement != nil && n.contains(activeElement)
+	case "link", "any-link":
+		var _, href = n.getAttr("href")
+		return href && (n.name == "a" || n.name == "area")
+	case "defined":
+		return true
+	}
+	// :hover and :active, there is no pointer.
+	return false
+}
+
+func isFormControl(n *node) bool {
+	switch n.name {
+	case "input", "button", "select", "textarea", "option", "fieldset":
+		return n.isHTML()
+	}
+	return false
+}
+
+// typeIndex returns the 1-based index of the element among its element siblings,
+// or among its siblings of the same type, and the number of them.
+func typeIndex(n *node, anyType bool) (index, count int) {
+	if n.parent == nil {
+		return 1, 1
+	}
+	for _, c := range n.parent.children {
+		if c.kind != elementNode || (!anyType && c.name != n.name) {
+			continue
+		}
+		count++
+		if c == n {
+			index = count
+		}
+	}
+	return index, count
+}
+
+func nthMatches(a, b, i int) bool {
+	if a == 0 {
+		return i == b
+	}
+	return (i-b)%a == 0 && (i-b)/a >= 0
+}
+
+// querySelector returns the first descendant of n matching the selector, or all of them.
+func (n *node) querySelector(selector string, all bool) []*node {
+	var list = parseSelector(selector)
+	var scope = n
+	if n.kind != elementNode {
+		scope = nil
+	}
+	var found []*node
+	n.walk(func(c *node) bool {
+		if c.kind == elementNode && list.matches(c, scope) {
+			found = append(found, c)
+			return all
+		}
+		return true
+	})
+	return found
+}
@@ -0,0 +1,198 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package jsv
+
+import (
+	"strconv"
+	"strings"
+)
+
+type declaration struct {
+	property, value, priority string
+}
+
+// declarations parses the style attribute of the element.
+func declarations(n *node) []declaration {
+	var list []declaration
+	for _, part := range splitDeclarations(n.attr("style")) {
+		var i = strings.IndexByte(part, ':')
+		if i < 0 {
+			continue
+		}
+		var d = declaration{
+			property: strings.ToLower(strings.TrimSpace(part[:i])),
+			value:    strings.TrimSpace(part[i+1:]),
+		}
+		if strings.HasPrefix(d.property, "--") {
+			d.property = strings.TrimSpace(part[:i])
+		}
+		if j := strings.LastIndex(d.value, "!"); j >= 0 && strings.EqualFold(strings.TrimSpace(d.value[j+1:]), "important") {
+			d.value, d.priority = strings.TrimSpace(d.value[:j]), "important"
+		}
+		if d.property != "" && d.value != "" {
+			list = append(list, d)
+		}
+	}
+	return list
+}
+
+// splitDeclarations splits CSS text on semicolons outside of parentheses and quotes.
+func splitDeclarations(text string) []string {
+	var parts []string
+	var depth int
+	var quote rune
+	var start int
+	for i, r := range text {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case r == ';' && depth == 0:
+			parts = append(parts, text[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, text[start:])
+}
+
+func cssText(list []declaration) string {
+	var b strings.Builder
+	for i, d := range list {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(d.property)
+		b.WriteString(": ")
+		b.WriteString(d.value)
+		if d.priority != "" {
+			b.WriteString(" !")
+			b.WriteString(d.priority)
+		}
+		b.WriteByte(';')
+	}
+	return b.String()
+}
+
+func setDeclaration(n *node, property, value, priority string) {
+	var list = declarations(n)
+	var found bool
+	for i, d := range list {
+		if d.property == property {
+			if value == "" {
+				list = append(list[:i:i], list[i+1:]...)
+			} else {
+				list[i].value, list[i].priority = value, priority
+			}
+			found = true
+			break
+		}
+	}
+	if !found && value != "" {
+		list = append(list, declaration{property: property, value: value, priority: priority})
+	}
+	if len(list) == 0 {
+		n.removeAttr("style")
+		return
+	}
+	n.setAttr("style", cssText(list))
+}
+
+func getDeclaration(n *node, property string) (declaration, bool) {
+	for _, d := range declarations(n) {
+		if d.property == property {
+			return d, true
+		}
+	}
+	return declaration{}, false
+}
+
+// styleProperty converts a property of CSSStyleDeclaration to its CSS name.
+func styleProperty(key string) string {
+	switch key {
+	case "cssFloat":
+		return "float"
+	}
+	if strings.HasPrefix(key, "Webkit") || strings.HasPrefix(key, "Moz") || strings.HasPrefix(key, "ms") {
+		return "-" + strings.TrimPrefix(kebabCase(key), "-")
+	}
+	return kebabCase(key)
+}
+
+// styleProto is the prototype of CSSStyleDeclaration, backed by the style attribute.
+//
+// Every string key is treated as a CSS property, there is no list of known properties.
+func styleProto() *object {
+	return proto("CSSStyleDeclaration", func(o *object) {
+		viewHooks(o, func(n *node, key string) (Value, bool) {
+			switch key {
+			case "cssText":
+				return str(cssText(declarations(n))), true
+			case "length":
+				return number(float64(len(declarations(n)))), true
+			case "parentRule":
+				return Null(), true
+			}
+			if i, err := strconv.Atoi(key); err == nil {
+				var list = declarations(n)
+				if i >= 0 && i < len(list) {
+					return str(list[i].property), true
+				}
+				return Undefined(), true
+			}
+			var d, _ = getDeclaration(n, styleProperty(key))
+			return str(d.value), true
+		}, func(n *node, key string, v Value) bool {
+			if key == "cssText" {
+				n.setAttr("style", nullableString(v))
+				if len(declarations(n)) == 0 {
+					n.removeAttr("style")
+				}
+				return true
+			}
+			setDeclaration(n, styleProperty(key), nullableString(v), "")
+			return true
+		})
+		o.method("setProperty", func(this Value, args []Value) Value {
+			var priority string
+			if p := arg(args, 2); !p.IsUndefined() && !p.IsNull() {
+				priority = strings.ToLower(toString(p))
+			}
+			// Other priorities are invalid, and the declaration is ignored as in the browser.
+			if priority != "" && priority != "important" {
+				return Undefined()
+			}
+			setDeclaration(nodeOf(this), argString(args, 0), nullableString(arg(args, 1)), priority)
+			return Undefined()
+		})
+		o.method("getPropertyValue", func(this Value, args []Value) Value {
+			var d, _ = getDeclaration(nodeOf(this), argString(args, 0))
+			return str(d.value)
+		})
+		o.method("getPropertyPriority", func(this Value, args []Value) Value {
+			var d, _ = getDeclaration(nodeOf(this), argString(args, 0))
+			return str(d.priority)
+		})
+		o.method("removeProperty", func(this Value, args []Value) Value {
+			var n = nodeOf(this)
+			var d, _ = getDeclaration(n, argString(args, 0))
+			setDeclaration(n, argString(args, 0), "", "")
+			return str(d.value)
+		})
+		o.method("item", func(this Value, args []Value) Value {
+			var list = declarations(nodeOf(this))
+			var i = int(toNumber(arg(args, 0)))
+			if i < 0 || i >= len(list) {
+				return str("")
+			}
+			return str(list[i].property)
+		})
+	}, objectProto)
+}
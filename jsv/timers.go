@@ -0,0 +1,174 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package jsv
+
+import (
+	"sort"
+	"time"
+)
+
+// frameInterval is the time between animation frames of the fake clock.
+const frameInterval = time.Second / 60
+
+type timer struct {
+	id       int
+	at       time.Duration
+	interval time.Duration
+	repeat   bool
+	frame    bool
+	fn       Value
+	args     []Value
+}
+
+// clock is a fake clock, timers only run when it is advanced.
+var clock struct {
+	now        time.Duration
+	nextID     int
+	timers     map[int]*timer
+	microtasks []Value
+}
+
+func addTimer(t *timer) Value {
+	if clock.timers == nil {
+		clock.timers = make(map[int]*timer)
+	}
+	clock.nextID++
+	t.id = clock.nextID
+	clock.timers[t.id] = t
+	return number(float64(t.id))
+}
+
+func delay(v Value) time.Duration {
+	var ms = toNumber(v)
+	if ms != ms || ms < 0 {
+		ms = 0
+	}
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// nextTimer returns the timer which is due first, before or at the deadline.
+func nextTimer(deadline time.Duration) *timer {
+	var due []*timer
+	for _, t := range clock.timers {
+		if t.at <= deadline {
+			due = append(due, t)
+		}
+	}
+	if len(due) == 0 {
+		return nil
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].at != due[j].at {
+			return due[i].at < due[j].at
+		}
+		return due[i].id < due[j].id
+	})
+	return due[0]
+}
+
+// advance moves the clock forward, running the timers which become due in order.
+func advance(d time.Duration) {
+	var deadline = clock.now + d
+	flushMicrotasks()
+	for {
+		var t = nextTimer(deadline)
+		if t == nil {
+			break
+		}
+		if t.at > clock.now {
+			clock.now = t.at
+		}
+		if t.frame {
+			runFrame()
+			continue
+		}
+		if t.repeat {
+			t.at += t.interval
+		} else {
+			delete(clock.timers, t.id)
+		}
+		if t.fn.t == TypeFunction {
+			t.fn.o.invoke(Undefined(), t.args)
+		}
+		flushMicrotasks()
+	}
+	clock.now = deadline
+}
+
+// runFrame runs all pending animation frame callbacks, callbacks requested by them run in the next frame.
+func runFrame() {
+	var frames []*timer
+	for _, t := range clock.timers {
+		if t.frame && t.at <= clock.now {
+			frames = append(frames, t)
+		}
+	}
+	sort.Slice(frames, func(i, j int) bool { return frames[i].id < frames[j].id })
+	var now = number(float64(clock.now) / float64(time.Millisecond))
+	for _, t := range frames {
+		if _, ok := clock.timers[t.id]; !ok {
+			continue
+		}
+		delete(clock.timers, t.id)
+		t.fn.o.invoke(Undefined(), []Value{now})
+	}
+	flushMicrotasks()
+}
+
+func flushMicrotasks() {
+	for len(clock.microtasks) > 0 {
+		var fn = clock.microtasks[0]
+		clock.microtasks = clock.microtasks[1:]
+		fn.o.invoke(Undefined(), nil)
+	}
+}
+
+func resetTimers() {
+	clock.timers = nil
+	clock.microtasks = nil
+}
+
+func initTimers(g *object) {
+	var set = func(repeat bool) func(this Value, args []Value) Value {
+		return func(this Value, args []Value) Value {
+			var d = delay(arg(args, 1))
+			if repeat && d < time.Millisecond {
+				d = time.Millisecond
+			}
+			return addTimer(&timer{
+				at:       clock.now + d,
+				interval: d,
+				repeat:   repeat,
+				fn:       arg(args, 0),
+				args:     append([]Value(nil), args[min(2, len(args)):]...),
+			})
+		}
+	}
+	var clear = func(this Value, args []Value) Value {
+		delete(clock.timers, int(toNumber(arg(args, 0))))
+		return Undefined()
+	}
+	g.method("setTimeout", set(false))
+	g.method("setInterval", set(true))
+	g.method("clearTimeout", clear)
+	g.method("clearInterval", clear)
+	g.method("requestAnimationFrame", func(this Value, args []Value) Value {
+		// Frames are due at the next multiple of the frame interval.
+		var at = (clock.now/frameInterval + 1) * frameInterval
+		return addTimer(&timer{at: at, frame: true, fn: arg(args, 0)})
+	})
+	g.method("cancelAnimationFrame", clear)
+	g.method("queueMicrotask", func(this Value, args []Value) Value {
+		if fn := arg(args, 0); fn.t == TypeFunction {
+			clock.microtasks = append(clock.microtasks, fn)
+		}
+		return Undefined()
+	})
+
+	var performance = newPlainObject()
+	performance.o.method("now", func(this Value, args []Value) Value {
+		return number(float64(clock.now) / float64(time.Millisecond))
+	})
+	g.define("performance", performance)
+}
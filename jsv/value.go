@@ -0,0 +1,454 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package jsv
+
+import (
+	"math"
+	"strconv"
+)
+
+// Browser reports whether the values are backed by a real JavaScript runtime.
+const Browser = false
+
+// Type is the JavaScript type of a Value.
+type Type int
+
+const (
+	TypeUndefined Type = iota
+	TypeNull
+	TypeBoolean
+	TypeNumber
+	TypeString
+	TypeSymbol
+	TypeObject
+	TypeFunction
+)
+
+func (t Type) String() string {
+	switch t {
+	case TypeUndefined:
+		return "undefined"
+	case TypeNull:
+		return "null"
+	case TypeBoolean:
+		return "boolean"
+	case TypeNumber:
+		return "number"
+	case TypeString:
+		return "string"
+	case TypeSymbol:
+		return "symbol"
+	case TypeObject:
+		return "object"
+	case TypeFunction:
+		return "function"
+	}
+	panic("bad type")
+}
+
+func (t Type) isObject() bool {
+	return t == TypeObject || t == TypeFunction
+}
+
+// Value is a JavaScript value, the zero Value is undefined.
+type Value struct {
+	_ [0]func() // uncomparable, like js.Value
+	t Type
+	b bool
+	n float64
+	s string
+	o *object
+}
+
+// Error wraps a thrown JavaScript value.
+type Error struct {
+	Value
+}
+
+func (e Error) Error() string {
+	return "JavaScript error: " + e.Get("message").String()
+}
+
+// ValueError occurs when a Value method is used on a value of the wrong type.
+type ValueError struct {
+	Method string
+	Type   Type
+}
+
+func (e *ValueError) Error() string {
+	return "syscall/js: call of " + e.Method + " on " + e.Type.String()
+}
+
+func Undefined() Value {
+	return Value{}
+}
+
+func Null() Value {
+	return Value{t: TypeNull}
+}
+
+func number(n float64) Value {
+	return Value{t: TypeNumber, n: n}
+}
+
+func str(s string) Value {
+	return Value{t: TypeString, s: s}
+}
+
+func boolean(b bool) Value {
+	return Value{t: TypeBoolean, b: b}
+}
+
+// ValueOf returns x as a Value, following the rules of js.ValueOf.
+func ValueOf(x any) Value {
+	switch x := x.(type) {
+	case Value:
+		return x
+	case Func:
+		return x.Value
+	case nil:
+		return Null()
+	case bool:
+		return boolean(x)
+	case int:
+		return number(float64(x))
+	case int8:
+		return number(float64(x))
+	case int16:
+		return number(float64(x))
+	case int32:
+		return number(float64(x))
+	case int64:
+		return number(float64(x))
+	case uint:
+		return number(float64(x))
+	case uint8:
+		return number(float64(x))
+	case uint16:
+		return number(float64(x))
+	case uint32:
+		return number(float64(x))
+	case uint64:
+		return number(float64(x))
+	case uintptr:
+		return number(float64(x))
+	case float32:
+		return number(float64(x))
+	case float64:
+		return number(x)
+	case string:
+		return str(x)
+	case []any:
+		var a = newArray(len(x))
+		for i, v := range x {
+			a.o.array[i] = ValueOf(v)
+		}
+		return a
+	case map[string]any:
+		var o = newPlainObject()
+		for k, v := range x {
+			o.Set(k, v)
+		}
+		return o
+	}
+	panic(&ValueError{Method: "ValueOf", Type: TypeUndefined})
+}
+
+func toValues(args []any) []Value {
+	var values = make([]Value, len(args))
+	for i, a := range args {
+		values[i] = ValueOf(a)
+	}
+	return values
+}
+
+func (v Value) Type() Type {
+	return v.t
+}
+
+func (v Value) mustObject(method string) {
+	if !v.t.isObject() {
+		panic(&ValueError{Method: method, Type: v.t})
+	}
+}
+
+func (v Value) Get(p string) Value {
+	v.mustObject("Value.Get")
+	return v.o.getProp(p, v)
+}
+
+func (v Value) Set(p string, x any) {
+	v.mustObject("Value.Set")
+	v.o.setProp(p, ValueOf(x), v)
+}
+
+func (v Value) Delete(p string) {
+	v.mustObject("Value.Delete")
+	v.o.deleteProp(p)
+}
+
+func (v Value) Index(i int) Value {
+	v.mustObject("Value.Index")
+	return v.o.getProp(strconv.Itoa(i), v)
+}
+
+func (v Value) SetIndex(i int, x any) {
+	v.mustObject("Value.SetIndex")
+	v.o.setProp(strconv.Itoa(i), ValueOf(x), v)
+}
+
+func (v Value) Length() int {
+	v.mustObject("Value.Length")
+	return v.Get("length").Int()
+}
+
+// Call calls the method m of the value.
+func (v Value) Call(m string, args ...any) Value {
+	v.mustObject("Value.Call")
+	var fn = v.Get(m)
+	if fn.t != TypeFunction {
+		panic("syscall/js: Value.Call: property " + m + " is not a function, got " + fn.t.String())
+	}
+	return fn.o.invoke(v, toValues(args))
+}
+
+// Invoke calls the function value with this set to undefined.
+func (v Value) Invoke(args ...any) Value {
+	if v.t != TypeFunction {
+		panic(&ValueError{Method: "Value.Invoke", Type: v.t})
+	}
+	return v.o.invoke(Undefined(), toValues(args))
+}
+
+// New calls the function value as a constructor.
+func (v Value) New(args ...any) Value {
+	if v.t != TypeFunction {
+		panic(&ValueError{Method: "Value.New", Type: v.t})
+	}
+	var values = toValues(args)
+	if v.o.construct != nil {
+		return v.o.construct(values)
+	}
+	var proto *object
+	if p := v.Get("prototype"); p.t.isObject() {
+		proto = p.o
+	}
+	var this = Value{t: TypeObject, o: newObject(proto)}
+	var ret = v.o.invoke(this, values)
+	if ret.t.isObject() {
+		return ret
+	}
+	return this
+}
+
+func (v Value) Float() float64 {
+	if v.t != TypeNumber {
+		panic(&ValueError{Method: "Value.Float", Type: v.t})
+	}
+	return v.n
+}
+
+func (v Value) Int() int {
+	if v.t != TypeNumber {
+		panic(&ValueError{Method: "Value.Int", Type: v.t})
+	}
+	return int(v.n)
+}
+
+func (v Value) Bool() bool {
+	if v.t != TypeBoolean {
+		panic(&ValueError{Method: "Value.Bool", Type: v.t})
+	}
+	return v.b
+}
+
+// String returns the string of a string value, and a description of the value otherwise.
+func (v Value) String() string {
+	switch v.t {
+	case TypeString:
+		return v.s
+	case TypeUndefined:
+		return "<undefined>"
+	case TypeNull:
+		return "<null>"
+	case TypeBoolean:
+		return "<boolean: " + strconv.FormatBool(v.b) + ">"
+	case TypeNumber:
+		return "<number: " + formatNumber(v.n) + ">"
+	case TypeSymbol:
+		return "<symbol>"
+	case TypeObject:
+		return "<object>"
+	case TypeFunction:
+		return "<function>"
+	}
+	panic("bad type")
+}
+
+func (v Value) Truthy() bool {
+	switch v.t {
+	case TypeUndefined, TypeNull:
+		return false
+	case TypeBoolean:
+		return v.b
+	case TypeNumber:
+		return v.n != 0 && !math.IsNaN(v.n)
+	case TypeString:
+		return v.s != ""
+	}
+	return true
+}
+
+func (v Value) IsUndefined() bool {
+	return v.t == TypeUndefined
+}
+
+func (v Value) IsNull() bool {
+	return v.t == TypeNull
+}
+
+func (v Value) IsNaN() bool {
+	return v.t == TypeNumber && math.IsNaN(v.n)
+}
+
+// Equal reports whether the values are strictly equal, like ===.
+func (v Value) Equal(w Value) bool {
+	if v.t != w.t {
+		return false
+	}
+	switch v.t {
+	case TypeUndefined, TypeNull:
+		return true
+	case TypeBoolean:
+		return v.b == w.b
+	case TypeNumber:
+		return v.n == w.n
+	case TypeString:
+		return v.s == w.s
+	}
+	return v.o == w.o
+}
+
+// InstanceOf reports whether t.prototype is in the prototype chain of v.
+func (v Value) InstanceOf(t Value) bool {
+	if !v.t.isObject() || t.t != TypeFunction {
+		return false
+	}
+	var proto = t.Get("prototype")
+	if !proto.t.isObject() {
+		return false
+	}
+	for p := v.o.proto; p != nil; p = p.proto {
+		if p == proto.o {
+			return true
+		}
+	}
+	return false
+}
+
+// Func is a Go function which can be called from JavaScript.
+type Func struct {
+	Value
+}
+
+// FuncOf returns a function which calls fn.
+func FuncOf(fn func(this Value, args []Value) any) Func {
+	var o = newObject(functionProto())
+	o.call = func(this Value, args []Value) Value {
+		return ValueOf(fn(this, args))
+	}
+	return Func{Value: Value{t: TypeFunction, o: o}}
+}
+
+// Release frees the function, calling it afterwards panics.
+func (f Func) Release() {
+	if f.o != nil {
+		f.o.released = true
+	}
+}
+
+// CopyBytesToGo copies bytes from a Uint8Array to dst.
+func CopyBytesToGo(dst []byte, src Value) int {
+	if !src.t.isObject() || src.o.bytes == nil {
+		panic("syscall/js: CopyBytesToGo: expected src to be a Uint8Array or Uint8ClampedArray")
+	}
+	return copy(dst, *src.o.bytes)
+}
+
+// CopyBytesToJS copies bytes from src to a Uint8Array.
+func CopyBytesToJS(dst Value, src []byte) int {
+	if !dst.t.isObject() || dst.o.bytes == nil {
+		panic("syscall/js: CopyBytesToJS: expected dst to be a Uint8Array or Uint8ClampedArray")
+	}
+	return copy(*dst.o.bytes, src)
+}
+
+func formatNumber(n float64) string {
+	switch {
+	case math.IsNaN(n):
+		return "NaN"
+	case math.IsInf(n, 1):
+		return "Infinity"
+	case math.IsInf(n, -1):
+		return "-Infinity"
+	}
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// toString converts the value to a string like String(v) in JavaScript.
+func toString(v Value) string {
+	switch v.t {
+	case TypeString:
+		return v.s
+	case TypeUndefined:
+		return "undefined"
+	case TypeNull:
+		return "null"
+	case TypeBoolean:
+		return strconv.FormatBool(v.b)
+	case TypeNumber:
+		return formatNumber(v.n)
+	}
+	if v.o.isArray {
+		var s string
+		for i, item := range v.o.array {
+			if i > 0 {
+				s += ","
+			}
+			if !item.IsUndefined() && !item.IsNull() {
+				s += toString(item)
+			}
+		}
+		return s
+	}
+	if toStr := v.Get("toString"); toStr.t == TypeFunction {
+		if r := toStr.o.invoke(v, nil); r.t == TypeString {
+			return r.s
+		}
+	}
+	return "[object Object]"
+}
+
+// toNumber converts the value to a number like Number(v) in JavaScript.
+func toNumber(v Value) float64 {
+	switch v.t {
+	case TypeNumber:
+		return v.n
+	case TypeBoolean:
+		if v.b {
+			return 1
+		}
+		return 0
+	case TypeNull:
+		return 0
+	case TypeString:
+		if v.s == "" {
+			return 0
+		}
+		if n, err := strconv.ParseFloat(v.s, 64); err == nil {
+			return n
+		}
+	}
+	return math.NaN()
+}
@@ -0,0 +1,195 @@
+package keys
+
+import (
+	"strings"
+
+	"github.com/Nigel2392/jsext/v2/errs"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+const (
+	ErrEmptyBinding errs.Error = "keys: empty key binding"
+	ErrNoKey        errs.Error = "keys: key binding has only modifiers"
+)
+
+// Combo is a key pressed together with modifiers.
+type Combo struct {
+	// Key is the lower case key, as in KeyboardEvent.key, for example "k", "escape" or "arrowup".
+	Key   string
+	Ctrl  bool
+	Alt   bool
+	Shift bool
+	Meta  bool
+}
+
+// aliases are the names which can be used for keys in bindings.
+var aliases = map[string]string{
+	"esc":    "escape",
+	"space":  " ",
+	"up":     "arrowup",
+	"down":   "arrowdown",
+	"left":   "arrowleft",
+	"right":  "arrowright",
+	"del":    "delete",
+	"ins":    "insert",
+	"plus":   "+",
+	"return": "enter",
+}
+
+// ParseCombo parses a combo like "Ctrl+Shift+K".
+//
+// Mod is Cmd on macOS and Ctrl elsewhere. The key "+" is written as "Plus".
+func ParseCombo(s string) (Combo, error) {
+	var c Combo
+	var parts = strings.Split(strings.TrimSpace(s), "+")
+	for i, part := range parts {
+		var name = strings.ToLower(strings.TrimSpace(part))
+		if i < len(parts)-1 {
+			switch name {
+			case "ctrl", "control":
+				c.Ctrl = true
+				continue
+			case "alt", "option", "opt":
+				c.Alt = true
+				continue
+			case "shift":
+				c.Shift = true
+				continue
+			case "meta", "cmd", "command", "super", "win":
+				c.Meta = true
+				continue
+			case "mod":
+				if IsMac() {
+					c.Meta = true
+				} else {
+					c.Ctrl = true
+				}
+				continue
+			}
+		}
+		if name == "" {
+			return c, ErrNoKey
+		}
+		if alias, ok := aliases[name]; ok {
+			name = alias
+		}
+		c.Key = name
+	}
+	return c, nil
+}
+
+// ParseSequence parses a binding of one or more combos, like "Mod+K" or "g then i".
+func ParseSequence(s string) ([]Combo, error) {
+	var parts = strings.Split(s, " then ")
+	var seq = make([]Combo, 0, len(parts))
+	for _, part := range parts {
+		if strings.TrimSpace(part) == "" {
+			return nil, ErrEmptyBinding
+		}
+		var c, err = ParseCombo(part)
+		if err != nil {
+			return nil, err
+		}
+		seq = append(seq, c)
+	}
+	return seq, nil
+}
+
+// ComboOf returns the combo of a keydown event, ok is false if only a modifier was pressed.
+func ComboOf(event js.Value) (c Combo, ok bool) {
+	c = Combo{
+		Key:   strings.ToLower(event.Get("key").String()),
+		Ctrl:  event.Get("ctrlKey").Truthy(),
+		Alt:   event.Get("altKey").Truthy(),
+		Shift: event.Get("shiftKey").Truthy(),
+		Meta:  event.Get("metaKey").Truthy(),
+	}
+	switch c.Key {
+	case "control", "alt", "shift", "meta", "altgraph", "capslock", "":
+		return c, false
+	}
+	// Alt changes the key on macOS, the code still has the letter or digit.
+	if c.Alt {
+		var code = event.Get("code").String()
+		if strings.HasPrefix(code, "Key") || strings.HasPrefix(code, "Digit") {
+			c.Key = strings.ToLower(code[len(code)-1:])
+		}
+	}
+	return c, true
+}
+
+// Match reports whether the pressed combo matches the combo of a binding.
+//
+// Shift is not compared for keys like "?" which can only be typed with it.
+func (c Combo) Match(pressed Combo) bool {
+	if c.Key != pressed.Key || c.Ctrl != pressed.Ctrl || c.Alt != pressed.Alt || c.Meta != pressed.Meta {
+		return false
+	}
+	return c.Shift == pressed.Shift || !c.Shift && len(c.Key) == 1 && !isLetter(c.Key[0])
+}
+
+// modified reports whether the combo has a modifier which does not type text.
+func (c Combo) modified() bool {
+	return c.Ctrl || c.Alt || c.Meta
+}
+
+// String formats the combo for display, with the modifier names of the platform.
+func (c Combo) String() string {
+	var parts []string
+	var mac = IsMac()
+	if c.Ctrl {
+		parts = append(parts, "Ctrl")
+	}
+	if c.Alt {
+		if mac {
+			parts = append(parts, "Option")
+		} else {
+			parts = append(parts, "Alt")
+		}
+	}
+	if c.Shift {
+		parts = append(parts, "Shift")
+	}
+	if c.Meta {
+		if mac {
+			parts = append(parts, "Cmd")
+		} else {
+			parts = append(parts, "Meta")
+		}
+	}
+	return strings.Join(append(parts, keyName(c.Key)), "+")
+}
+
+func keyName(key string) string {
+	switch key {
+	case " ":
+		return "Space"
+	case "+":
+		return "Plus"
+	}
+	if strings.HasPrefix(key, "arrow") {
+		key = key[len("arrow"):]
+	}
+	if len(key) == 1 {
+		return strings.ToUpper(key)
+	}
+	return strings.ToUpper(key[:1]) + key[1:]
+}
+
+func isLetter(b byte) bool {
+	return b >= 'a' && b <= 'z'
+}
+
+// IsMac reports whether the user agent runs on macOS or iOS, where Mod is the Cmd key.
+func IsMac() bool {
+	var navigator = js.Global().Get("navigator")
+	if navigator.IsUndefined() || navigator.IsNull() {
+		return false
+	}
+	var platform = navigator.Get("platform")
+	if platform.IsUndefined() || platform.String() == "" {
+		platform = navigator.Get("userAgent")
+	}
+	var p = platform.String()
+	return strings.Contains(p, "Mac") || strings.Contains(p, "iPhone") || strings.Contains(p, "iPad")
+}
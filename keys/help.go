@@ -0,0 +1,144 @@
+package keys
+
+import (
+	"strings"
+
+	"github.com/Nigel2392/jsext/v2"
+	"github.com/Nigel2392/jsext/v2/css"
+	"github.com/Nigel2392/jsext/v2/jse"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+type help struct {
+	root  *jse.Element
+	scope *Scope
+}
+
+func (m *Manager) class(name string) string {
+	return m.opts.ClassPrefix + name
+}
+
+// HelpElement returns a table of the bindings which have a description, grouped by scope.
+func (m *Manager) HelpElement() *jse.Element {
+	var root = jse.Div(m.class("keys-help-list"))
+	for _, s := range append([]*Scope{m.global}, m.scopes...) {
+		var rows []*Binding
+		for _, b := range s.bindings {
+			if b.Description != "" {
+				rows = append(rows, b)
+			}
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		var name = s.Name
+		if name == "" {
+			name = "General"
+		}
+		root.Heading(3, name)
+		var table = root.Table(m.class("keys-help-table"))
+		for _, b := range rows {
+			var tr = table.Tr()
+			var keys = tr.Td()
+			for i, c := range b.sequence {
+				if i > 0 {
+					keys.Span(" then ")
+				}
+				keys.Kbd(c.String())
+			}
+			tr.Td(b.Description)
+		}
+	}
+	return root
+}
+
+// ShowHelp shows an overlay which lists the bindings, it is closed with Escape or by clicking outside of it.
+func (m *Manager) ShowHelp() {
+	m.HideHelp()
+	var h = &help{root: jse.Div(m.class("keys-help"))}
+	h.root.Style().Set("position", "fixed")
+	h.root.Style().Set("inset", "0")
+	h.root.Style().Set("z-index", "1000")
+	h.root.Style().Display("flex")
+	h.root.Style().Set("align-items", "center")
+	h.root.Style().Set("justify-content", "center")
+	h.root.Style().Set("background", css.Var(css.ColorBackdrop))
+
+	var panel = h.root.Div(m.class("keys-help-panel"))
+	panel.Heading(2, "Keyboard shortcuts")
+	panel.AppendChild(m.HelpElement())
+	h.root.OnClick(func(this *jse.Element, event jsext.Event) {
+		if event.Get("target").Equal(this.JSValue()) {
+			m.HideHelp()
+		}
+	})
+	h.root.StyleBlock(`
+		.` + m.class("keys-help-panel") + ` {
+			max-width: 90%;
+			max-height: 80%;
+			overflow: auto;
+			padding: 16px 24px;
+			border-radius: 5px;
+			border: 1px solid ` + css.Var(css.ColorBorder) + `;
+			background: ` + css.Var(css.ColorBackground) + `;
+			color: ` + css.Var(css.ColorText) + `;
+		}
+		.` + m.class("keys-help-table") + ` td {
+			padding: 2px 12px 2px 0;
+		}
+		.` + m.class("keys-help-table") + ` kbd {
+			padding: 1px 5px;
+			border-radius: 3px;
+			border: 1px solid ` + css.Var(css.ColorBorder) + `;
+			font-family: monospace;
+		}`)
+
+	h.scope = &Scope{Name: "Help", m: m, modal: true}
+	h.scope.Bind("Escape", "", func(jsext.Event) { m.HideHelp() })
+	h.scope.Activate()
+
+	js.Global().Get("document").Get("body").Call("appendChild", h.root.JSValue())
+	m.help = h
+}
+
+func (m *Manager) HideHelp() {
+	if m.help == nil {
+		return
+	}
+	m.help.scope.Deactivate()
+	m.help.root.Remove()
+	m.help = nil
+}
+
+func (m *Manager) ToggleHelp() {
+	if m.help != nil {
+		m.HideHelp()
+	} else {
+		m.ShowHelp()
+	}
+}
+
+func (m *Manager) HelpVisible() bool {
+	return m.help != nil
+}
+
+// String lists the bindings with a description, one per line.
+func (m *Manager) String() string {
+	var b strings.Builder
+	for _, s := range append([]*Scope{m.global}, m.scopes...) {
+		for _, binding := range s.bindings {
+			if binding.Description == "" {
+				continue
+			}
+			var keys = make([]string, len(binding.sequence))
+			for i, c := range binding.sequence {
+				keys[i] = c.String()
+			}
+			if s.Name != "" {
+				b.WriteString(s.Name + ": ")
+			}
+			b.WriteString(strings.Join(keys, " then ") + "\t" + binding.Description + "\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,350 @@
+// Package keys registers keyboard shortcuts for the whole document.
+//
+// Bindings are combos like "Mod+K" or sequences like "g then i", Mod is Cmd on macOS and Ctrl elsewhere.
+// Bindings belong to scopes: the global scope is always active, other scopes are active while
+// the focus is inside their element, or while they are activated, for example when a dialog is open.
+//
+//	keys.Bind("Mod+K", "Open search", func(jsext.Event) { search.Open() })
+//	keys.Bind("g then i", "Go to inbox", func(jsext.Event) { router.Go("/inbox") })
+//	keys.Bind("?", "Show shortcuts", func(jsext.Event) { keys.ToggleHelp() })
+//
+//	var dialog = keys.ScopeNamed("Dialog").Modal()
+//	dialog.Bind("Escape", "Close dialog", func(jsext.Event) { d.Close(); dialog.Deactivate() })
+//	dialog.Activate()
+package keys
+
+import (
+	"time"
+
+	"github.com/Nigel2392/jsext/v2"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+type Options struct {
+	// Target receives the keydown events, the document by default.
+	Target js.Value
+	// SequenceTimeout is the maximum time between the keys of a sequence.
+	SequenceTimeout time.Duration
+	// ClassPrefix is prepended to the classes of the help overlay.
+	ClassPrefix string
+}
+
+func (o *Options) Defaults() {
+	if o.Target.IsUndefined() || o.Target.IsNull() {
+		o.Target = js.Global().Get("document")
+	}
+	if o.SequenceTimeout == 0 {
+		o.SequenceTimeout = time.Second
+	}
+	if o.ClassPrefix == "" {
+		o.ClassPrefix = "jsext-"
+	}
+}
+
+// Binding is a registered shortcut.
+type Binding struct {
+	Keys        string
+	Description string
+	// InInputs lets the binding fire while typing in an input, textarea or editable element.
+	// Bindings which start with Ctrl, Alt or Meta always fire.
+	InInputs bool
+
+	sequence []Combo
+	handler  func(event jsext.Event)
+	scope    *Scope
+}
+
+func (b *Binding) Sequence() []Combo {
+	return b.sequence
+}
+
+func (b *Binding) Scope() *Scope {
+	return b.scope
+}
+
+func (b *Binding) Remove() {
+	var s = b.scope
+	for i, other := range s.bindings {
+		if other == b {
+			s.bindings = append(s.bindings[:i], s.bindings[i+1:]...)
+			break
+		}
+	}
+}
+
+// Scope is a group of bindings which are active together.
+type Scope struct {
+	Name     string
+	m        *Manager
+	element  js.Value
+	modal    bool
+	bindings []*Binding
+}
+
+func (s *Scope) Bind(keys, description string, handler func(event jsext.Event)) (*Binding, error) {
+	var seq, err = ParseSequence(keys)
+	if err != nil {
+		return nil, err
+	}
+	var b = &Binding{
+		Keys:        keys,
+		Description: description,
+		sequence:    seq,
+		handler:     handler,
+		scope:       s,
+	}
+	s.bindings = append(s.bindings, b)
+	return b, nil
+}
+
+func (s *Scope) Bindings() []*Binding {
+	return s.bindings
+}
+
+// Within makes the scope active while the focus is inside the element.
+func (s *Scope) Within(element js.Value) *Scope {
+	s.element = element
+	return s
+}
+
+// Modal makes the scope block the scopes below it while it is activated,
+// only scopes activated after it stay active.
+func (s *Scope) Modal() *Scope {
+	s.modal = true
+	return s
+}
+
+// Activate makes the scope active, bindings of scopes activated later take precedence.
+func (s *Scope) Activate() {
+	s.Deactivate()
+	s.m.stack = append(s.m.stack, s)
+}
+
+func (s *Scope) Deactivate() {
+	for i, other := range s.m.stack {
+		if other == s {
+			s.m.stack = append(s.m.stack[:i], s.m.stack[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *Scope) Active() bool {
+	for _, other := range s.m.active() {
+		if other == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Remove deactivates the scope and removes it from the manager.
+func (s *Scope) Remove() {
+	s.Deactivate()
+	for i, other := range s.m.scopes {
+		if other == s {
+			s.m.scopes = append(s.m.scopes[:i], s.m.scopes[i+1:]...)
+			return
+		}
+	}
+}
+
+// Manager dispatches keydown events to the bindings of the active scopes.
+type Manager struct {
+	opts     Options
+	global   *Scope
+	scopes   []*Scope
+	stack    []*Scope
+	pending  []Combo
+	last     float64
+	listener *jsext.Listener
+	help     *help
+}
+
+func New(opts ...Options) *Manager {
+	var m = &Manager{}
+	if len(opts) > 0 {
+		m.opts = opts[0]
+	}
+	m.opts.Defaults()
+	m.global = &Scope{m: m}
+	m.listener = jsext.Listen(m.opts.Target, "keydown", func(this js.Value, event jsext.Event) {
+		m.keydown(event)
+	})
+	return m
+}
+
+// Global returns the scope which is always active, unless a modal scope is.
+func (m *Manager) Global() *Scope {
+	return m.global
+}
+
+// Scope returns the scope with the name, it is created if it does not exist.
+func (m *Manager) Scope(name string) *Scope {
+	for _, s := range m.scopes {
+		if s.Name == name {
+			return s
+		}
+	}
+	var s = &Scope{Name: name, m: m}
+	m.scopes = append(m.scopes, s)
+	return s
+}
+
+// Bind adds a binding to the global scope.
+func (m *Manager) Bind(keys, description string, handler func(event jsext.Event)) (*Binding, error) {
+	return m.global.Bind(keys, description, handler)
+}
+
+// Destroy removes the event listener and the help overlay.
+func (m *Manager) Destroy() {
+	m.listener.Remove()
+	m.HideHelp()
+}
+
+// active returns the active scopes, the first has the highest precedence.
+func (m *Manager) active() []*Scope {
+	var scopes []*Scope
+	for i := len(m.stack) - 1; i >= 0; i-- {
+		scopes = append(scopes, m.stack[i])
+		if m.stack[i].modal {
+			return scopes
+		}
+	}
+
+	var focused = js.Global().Get("document").Get("activeElement")
+	if !focused.IsUndefined() && !focused.IsNull() {
+		var within []*Scope
+		for _, s := range m.scopes {
+			if s.element.IsUndefined() || s.element.IsNull() || contains(scopes, s) {
+				continue
+			}
+			if s.element.Call("contains", focused).Bool() {
+				within = append(within, s)
+			}
+		}
+		// The innermost element comes first.
+		for i := 1; i < len(within); i++ {
+			for j := i; j > 0 && within[j].element.Call("contains", within[j-1].element).Bool(); j-- {
+				within[j], within[j-1] = within[j-1], within[j]
+			}
+		}
+		scopes = append(scopes, within...)
+	}
+	return append(scopes, m.global)
+}
+
+func (m *Manager) keydown(event jsext.Event) {
+	if event.Get("isComposing").Truthy() {
+		return
+	}
+	var combo, ok = ComboOf(event.JSValue())
+	if !ok {
+		return
+	}
+	var now = event.Get("timeStamp").Float()
+	if len(m.pending) > 0 && now-m.last > float64(m.opts.SequenceTimeout.Milliseconds()) {
+		m.pending = nil
+	}
+	m.last = now
+
+	var typing = editable(event.Get("target"))
+	var scopes = m.active()
+	var seq = append(m.pending, combo)
+	var b, prefix = find(scopes, seq, typing)
+	if b == nil && !prefix && len(m.pending) > 0 {
+		// The sequence was broken, the key may start a new one.
+		seq = []Combo{combo}
+		b, prefix = find(scopes, seq, typing)
+	}
+	switch {
+	case b != nil:
+		m.pending = nil
+		event.PreventDefault()
+		b.handler(event)
+	case prefix:
+		m.pending = seq
+	default:
+		m.pending = nil
+	}
+}
+
+// find returns the first binding which matches the sequence,
+// or reports whether a binding starts with it.
+func find(scopes []*Scope, seq []Combo, typing bool) (match *Binding, prefix bool) {
+	for _, s := range scopes {
+		for _, b := range s.bindings {
+			if typing && !b.InInputs && !b.sequence[0].modified() || len(b.sequence) < len(seq) {
+				continue
+			}
+			var matched = true
+			for i, c := range seq {
+				if !b.sequence[i].Match(c) {
+					matched = false
+					break
+				}
+			}
+			switch {
+			case !matched:
+			case len(b.sequence) == len(seq):
+				return b, false
+			default:
+				prefix = true
+			}
+		}
+	}
+	return nil, prefix
+}
+
+// editable reports whether typing in the element inserts text.
+func editable(element js.Value) bool {
+	if element.IsUndefined() || element.IsNull() {
+		return false
+	}
+	switch element.Get("tagName").String() {
+	case "INPUT", "TEXTAREA", "SELECT":
+		return true
+	}
+	return element.Get("isContentEditable").Truthy()
+}
+
+func contains(scopes []*Scope, s *Scope) bool {
+	for _, other := range scopes {
+		if other == s {
+			return true
+		}
+	}
+	return false
+}
+
+var std *Manager
+
+// Default returns the manager used by the functions of the package, it is created on first use.
+func Default() *Manager {
+	if std == nil {
+		std = New()
+	}
+	return std
+}
+
+// Bind adds a binding to the global scope of the default manager.
+func Bind(keys, description string, handler func(event jsext.Event)) (*Binding, error) {
+	return Default().Bind(keys, description, handler)
+}
+
+// ScopeNamed returns the scope with the name of the default manager, it is created if it does not exist.
+func ScopeNamed(name string) *Scope {
+	return Default().Scope(name)
+}
+
+func ShowHelp() {
+	Default().ShowHelp()
+}
+
+func HideHelp() {
+	Default().HideHelp()
+}
+
+func ToggleHelp() {
+	Default().ToggleHelp()
+}
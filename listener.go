@@ -0,0 +1,237 @@
+package jsext
+
+import (
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+// ListenerOptions are passed to addEventListener.
+type ListenerOptions struct {
+	// Once removes the listener after it has been called once.
+	Once bool
+	// Passive indicates the listener will not call PreventDefault.
+	Passive bool
+	Capture bool
+	// Signal is an AbortSignal, the listener is removed when it is aborted.
+	Signal js.Value
+}
+
+func (o ListenerOptions) MarshalJS() js.Value {
+	var obj = js.Global().Get("Object").New()
+	obj.Set("once", o.Once)
+	obj.Set("passive", o.Passive)
+	obj.Set("capture", o.Capture)
+	if o.hasSignal() {
+		obj.Set("signal", o.Signal)
+	}
+	return obj
+}
+
+func (o ListenerOptions) hasSignal() bool {
+	return !o.Signal.IsUndefined() && !o.Signal.IsNull()
+}
+
+// Listener is a handle to an event listener.
+//
+// Remove removes the listener and releases the underlying js.Func.
+type Listener struct {
+	target  js.Value
+	event   string
+	fn      js.Func
+	capture bool
+	removed bool
+	abort   *Listener
+}
+
+// Listen adds an event listener to the target, and registers it with the target's registry.
+func Listen(target js.Value, event string, fn func(this js.Value, event Event), opts ...ListenerOptions) *Listener {
+	var o ListenerOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	var l = &Listener{
+		target:  target,
+		event:   event,
+		capture: o.Capture,
+	}
+	l.fn = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if l.removed {
+			return nil
+		}
+		var event Event
+		if len(args) > 0 {
+			event = Event(args[0])
+		}
+		fn(this, event)
+		if o.Once {
+			// The browser has already removed the listener.
+			l.release()
+		}
+		return nil
+	})
+	target.Call("addEventListener", event, l.fn, o.MarshalJS())
+	if o.hasSignal() {
+		l.abort = Listen(o.Signal, "abort", func(js.Value, Event) {
+			l.release()
+		}, ListenerOptions{Once: true})
+	}
+	registryOf(target, true).add(l)
+	return l
+}
+
+// Event returns the name of the event the listener was added for.
+func (l *Listener) Event() string {
+	return l.event
+}
+
+func (l *Listener) Target() Value {
+	return Value(l.target)
+}
+
+// Func returns the underlying js.Func, it is invalid after the listener has been removed.
+func (l *Listener) Func() js.Func {
+	return l.fn
+}
+
+func (l *Listener) Removed() bool {
+	return l == nil || l.removed
+}
+
+// Remove removes the event listener and releases the js.Func.
+//
+// It is safe to call Remove multiple times.
+func (l *Listener) Remove() {
+	if l.Removed() {
+		return
+	}
+	l.target.Call("removeEventListener", l.event, l.fn, l.capture)
+	l.release()
+}
+
+func (l *Listener) release() {
+	if l.removed {
+		return
+	}
+	l.removed = true
+	if l.abort != nil {
+		l.abort.Remove()
+	}
+	if r := registryOf(l.target, false); r != nil {
+		r.delete(l)
+		if len(r.list) == 0 {
+			deleteRegistry(l.target)
+		}
+	}
+	l.fn.Release()
+}
+
+// Listeners is a group of listeners which can be removed at once.
+type Listeners struct {
+	list []*Listener
+}
+
+// Add adds listeners to the group.
+func (r *Listeners) Add(listeners ...*Listener) *Listeners {
+	for _, l := range listeners {
+		if !l.Removed() {
+			r.list = append(r.list, l)
+		}
+	}
+	return r
+}
+
+// Len returns the number of listeners which have not been removed.
+func (r *Listeners) Len() int {
+	var n int
+	for _, l := range r.list {
+		if !l.Removed() {
+			n++
+		}
+	}
+	return n
+}
+
+// List returns the listeners in the group.
+func (r *Listeners) List() []*Listener {
+	var list = make([]*Listener, 0, len(r.list))
+	for _, l := range r.list {
+		if !l.Removed() {
+			list = append(list, l)
+		}
+	}
+	return list
+}
+
+// Remove removes all listeners in the group, or only those for the given events.
+func (r *Listeners) Remove(events ...string) {
+	for _, l := range r.List() {
+		if len(events) == 0 || contains(events, l.event) {
+			l.Remove()
+		}
+	}
+	r.list = r.List()
+}
+
+func (r *Listeners) add(l *Listener) {
+	r.list = append(r.list, l)
+}
+
+func (r *Listeners) delete(l *Listener) {
+	for i, item := range r.list {
+		if item == l {
+			r.list = append(r.list[:i], r.list[i+1:]...)
+			return
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// The registry of each target is stored under this property of the target.
+const registryKey = "__jsext_listeners"
+
+var (
+	registries   = make(map[int]*Listeners)
+	nextRegistry int
+)
+
+func registryOf(target js.Value, create bool) *Listeners {
+	var id = target.Get(registryKey)
+	if id.Type() == js.TypeNumber {
+		if r, ok := registries[id.Int()]; ok {
+			return r
+		}
+	}
+	if !create {
+		return nil
+	}
+	nextRegistry++
+	var r = &Listeners{}
+	registries[nextRegistry] = r
+	target.Set(registryKey, nextRegistry)
+	return r
+}
+
+func deleteRegistry(target js.Value) {
+	var id = target.Get(registryKey)
+	if id.Type() == js.TypeNumber {
+		delete(registries, id.Int())
+	}
+	target.Delete(registryKey)
+}
+
+// ListenersOf returns the registry of listeners added to the target through Listen.
+//
+// The registry is discarded once all of its listeners have been removed.
+func ListenersOf(target js.Value) *Listeners {
+	if r := registryOf(target, false); r != nil {
+		return r
+	}
+	return &Listeners{}
+}
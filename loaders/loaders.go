@@ -8,15 +8,16 @@ import (
 	"github.com/Nigel2392/jsext/v2/jse"
 )
 
+// Colors of the loaders, these follow the current css.Theme by default.
 var (
-	COLOR_MAIN       = css.COLOR_MAIN
-	COLOR_ONE        = css.COLOR_ONE
-	COLOR_TWO        = css.COLOR_TWO
-	COLOR_THREE      = css.COLOR_THREE
-	COLOR_FOUR       = css.COLOR_FOUR
-	COLOR_FIVE       = css.COLOR_FIVE
+	COLOR_MAIN       = css.Var(css.ColorOnBackdrop)
+	COLOR_ONE        = css.Var(css.ColorPrimary)
+	COLOR_TWO        = css.Var(css.ColorSecondary)
+	COLOR_THREE      = css.Var(css.ColorTertiary)
+	COLOR_FOUR       = css.Var(css.ColorAccent)
+	COLOR_FIVE       = css.Var(css.ColorInfo)
 	LOADING_TEXT     = "loading"
-	BACKGROUND_COLOR = css.BACKGROUND_COLOR
+	BACKGROUND_COLOR = css.Var(css.ColorBackdrop)
 )
 
 // Function for use in the loader.
@@ -1,10 +1,9 @@
 package localstorage
 
 import (
-	"syscall/js"
-
 	"github.com/Nigel2392/jsext/v2/errs"
 	"github.com/Nigel2392/jsext/v2/jsc"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 const (
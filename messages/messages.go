@@ -40,8 +40,8 @@ func Error(message string) {
 	Emit(TypeError, message)
 }
 
-func Listen(callback func(typ string, message string)) {
-	jsext.EventOn("jsextMessages", func(args ...interface{}) {
+func Listen(callback func(typ string, message string)) *jsext.Listener {
+	return jsext.EventOn("jsextMessages", func(args ...interface{}) {
 		var typStr string = args[0].(string)
 		var message string = args[1].(string)
 		callback(typStr, message)
@@ -0,0 +1,129 @@
+package plugins
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Nigel2392/jsext/v2"
+	"github.com/Nigel2392/jsext/v2/errs"
+	"github.com/Nigel2392/jsext/v2/jsc"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+// APIVersion is the version of the plugin protocol of this package.
+//
+// A manifest is compatible if its API has the same major version, and a minor version which is not newer.
+const APIVersion = "1.0"
+
+const (
+	ErrInvalidManifest errs.Error = "plugins: invalid manifest"
+	ErrIncompatible    errs.Error = "plugins: incompatible plugin API version"
+)
+
+// Manifest describes a plugin, it is usually a JSON file next to the WebAssembly module.
+//
+//	{
+//		"name": "markdown",
+//		"version": "1.4.2",
+//		"api": "1.0",
+//		"url": "markdown.wasm"
+//	}
+type Manifest struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// API is the version of the plugin protocol the plugin was built with.
+	API string `json:"api"`
+	// URL of the WebAssembly module, relative to the manifest.
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+	// Exports is the path of the object on the window with the exports of the plugin, if it has any.
+	Exports string `json:"exports,omitempty"`
+}
+
+// Validate checks the required fields and the API version.
+func (m *Manifest) Validate() error {
+	switch {
+	case m.Name == "":
+		return fmt.Errorf("%w: missing name", ErrInvalidManifest)
+	case m.URL == "":
+		return fmt.Errorf("%w: %s: missing url", ErrInvalidManifest, m.Name)
+	}
+	if _, err := ParseVersion(m.Version); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidManifest, m.Name, err)
+	}
+	var api, err = ParseVersion(m.API)
+	if err != nil {
+		return fmt.Errorf("%w: %s: api: %v", ErrInvalidManifest, m.Name, err)
+	}
+	var host, _ = ParseVersion(APIVersion)
+	if api[0] != host[0] || api[1] > host[1] {
+		return fmt.Errorf("%w: %s needs %s, the host supports %s", ErrIncompatible, m.Name, m.API, APIVersion)
+	}
+	return nil
+}
+
+// Version is a semantic version, missing parts are 0.
+type Version [3]int
+
+// ParseVersion parses versions like "1", "1.2" and "v1.2.3", pre-release and build suffixes are ignored.
+func ParseVersion(s string) (Version, error) {
+	var v Version
+	var trimmed = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(trimmed, "-+"); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+	var parts = strings.Split(trimmed, ".")
+	if trimmed == "" || len(parts) > 3 {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+	for i, part := range parts {
+		var n, err = strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version %q", s)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+func (v Version) String() string {
+	return strconv.Itoa(v[0]) + "." + strconv.Itoa(v[1]) + "." + strconv.Itoa(v[2])
+}
+
+// Less reports whether v is older than other.
+func (v Version) Less(other Version) bool {
+	for i := range v {
+		if v[i] != other[i] {
+			return v[i] < other[i]
+		}
+	}
+	return false
+}
+
+// FetchManifest fetches and validates a manifest, the URL of the module is resolved against the manifest URL.
+//
+// It blocks, so it should not be called from a javascript callback.
+func FetchManifest(url string) (*Manifest, error) {
+	var resp, err = jsext.Await(fetch.Invoke(url))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrLoad, url, err)
+	}
+	if !resp.Get("ok").Bool() {
+		return nil, fmt.Errorf("%w: %s: status %d", ErrLoad, url, resp.Get("status").Int())
+	}
+	var manifestURL = resp.Get("url").String()
+	body, err := jsext.Await(resp.Call("json"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidManifest, url, err)
+	}
+	var m Manifest
+	if err = jsc.Scan(body, &m); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidManifest, url, err)
+	}
+	if err = m.Validate(); err != nil {
+		return nil, err
+	}
+	m.URL = js.Global().Get("URL").New(m.URL, manifestURL).Get("href").String()
+	return &m, nil
+}
@@ -1,14 +1,56 @@
 package plugins
 
 import (
+	"errors"
 	"fmt"
 	"strings"
-	"syscall/js"
+	"sync"
+	"time"
 
+	"github.com/Nigel2392/jsext/v2"
 	"github.com/Nigel2392/jsext/v2/errs"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
+const (
+	ErrNilPlugins errs.Error = "plugins: nil *Plugins"
+	ErrLoad       errs.Error = "plugins: could not load plugin"
+	ErrLoaded     errs.Error = "plugins: plugin is already loaded"
+	ErrNotFound   errs.Error = "plugins: plugin not found"
+	ErrStop       errs.Error = "plugins: plugin did not stop"
+)
+
+// StopTimeout is how long Unload waits for the Go runtime of a plugin to exit.
+var StopTimeout = 5 * time.Second
+
+type State uint8
+
+const (
+	Loading State = iota
+	Running
+	Stopped
+	Failed
+)
+
+func (s State) String() string {
+	switch s {
+	case Loading:
+		return "loading"
+	case Running:
+		return "running"
+	case Stopped:
+		return "stopped"
+	case Failed:
+		return "failed"
+	}
+	return fmt.Sprintf("State(%d)", uint8(s))
+}
+
 type Plugin struct {
+	Name string `js:"name"`
+	// Manifest is nil for plugins loaded with NewPlugin.
+	Manifest     *Manifest           `js:"manifest"`
+	URL          string              `js:"url"`
 	ExportPath   string              `js:"exportPath"`
 	Exports      map[string]js.Value `js:"exports"`
 	Module       js.Value            `js:"module"`
@@ -16,6 +58,15 @@ type Plugin struct {
 	ExportObject js.Value            `js:"exportObject"`
 	GoObject     js.Value            `js:"goObject"`
 	Flags        uint8
+	// RPC is the host side of the channel to the plugin, the plugin connects to it with Connect.
+	RPC *Channel `js:"-"`
+
+	setup  []func(p *Plugin)
+	mu     sync.Mutex
+	state  State
+	err    error
+	exited chan struct{}
+	onExit js.Func
 }
 
 func (e *Plugin) Call(varName string, args ...any) (js.Value, error) {
@@ -34,21 +85,261 @@ func (e *Plugin) Get(varName string) (js.Value, error) {
 	return v, nil
 }
 
+func (e *Plugin) State() State {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.state
+}
+
+// Err returns the error which made the plugin fail to load.
+func (e *Plugin) Err() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.err
+}
+
+// Exited is closed when the Go runtime of the plugin has exited.
+func (e *Plugin) Exited() <-chan struct{} {
+	return e.exited
+}
+
+// Unload stops the plugin, and releases the references the host holds to it.
+//
+// The Go runtime of a plugin can only be stopped if the plugin is connected, see Connect.
+// It is asked to stop over the channel, main should return when the channel is done.
+// If it does not exit within StopTimeout, an ErrStop error is returned and the plugin is detached regardless.
+//
+// It blocks, so it should not be called from a javascript callback.
+func (e *Plugin) Unload() error {
+	e.mu.Lock()
+	if e.state != Running {
+		e.mu.Unlock()
+		return nil
+	}
+	e.state = Stopped
+	e.mu.Unlock()
+
+	var err = e.stop()
+	e.detach()
+	return err
+}
+
+func (e *Plugin) stop() error {
+	select {
+	case <-e.exited:
+		return nil
+	default:
+	}
+	if !e.RPC.Connected() {
+		return fmt.Errorf("%w: %s is not connected", ErrStop, e.Name)
+	}
+
+	var timeout = time.NewTimer(StopTimeout)
+	defer timeout.Stop()
+	var stopped = make(chan error, 1)
+	go func() {
+		var _, err = e.RPC.Call(stopMethod, nil)
+		stopped <- err
+	}()
+	select {
+	case err := <-stopped:
+		if err != nil && !errors.Is(err, ErrClosed) {
+			return fmt.Errorf("%w: %s: %v", ErrStop, e.Name, err)
+		}
+	case <-timeout.C:
+		return fmt.Errorf("%w: %s did not respond within %s", ErrStop, e.Name, StopTimeout)
+	}
+	select {
+	case <-e.exited:
+		return nil
+	case <-timeout.C:
+		return fmt.Errorf("%w: %s did not exit within %s", ErrStop, e.Name, StopTimeout)
+	}
+}
+
+// detach closes the channel and drops the references to the module, so it can be garbage collected.
+func (e *Plugin) detach() {
+	if e.RPC != nil {
+		e.RPC.Close()
+		var registry = js.Global().Get(registryKey)
+		if registry.Type() == js.TypeObject && registry.Get(e.Name).Equal(e.RPC.v) {
+			registry.Delete(e.Name)
+		}
+	}
+	e.Exports = nil
+	e.ExportObject = js.Undefined()
+	e.Module = js.Undefined()
+	e.Instance = js.Undefined()
+	e.GoObject = js.Undefined()
+}
+
+// exit is called when the run promise of the Go object settles.
+func (e *Plugin) exit(this js.Value, args []js.Value) any {
+	e.onExit.Release()
+	close(e.exited)
+	e.RPC.Close()
+	e.mu.Lock()
+	if e.state == Running {
+		e.state = Stopped
+	}
+	e.mu.Unlock()
+	return nil
+}
+
+// load fetches, instantiates and runs the module.
+//
+// A failure, including a javascript exception, is returned as an error and leaves other plugins alone.
+func (e *Plugin) load() (err error) {
+	e.mu.Lock()
+	e.state = Loading
+	e.err = nil
+	e.mu.Unlock()
+	e.exited = make(chan struct{})
+
+	var started bool
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %s: %v", ErrLoad, e.Name, r)
+		}
+		if err == nil {
+			return
+		}
+		if started {
+			e.stop()
+		}
+		e.detach()
+		e.mu.Lock()
+		e.state = Failed
+		e.err = err
+		e.mu.Unlock()
+	}()
+
+	if globalGo.Type() != js.TypeFunction {
+		return fmt.Errorf("%w: %s: wasm_exec.js is not loaded", ErrLoad, e.Name)
+	}
+	polyfill()
+
+	var goObj = globalGo.New()
+	var env = goObj.Get("env")
+	if env.Type() != js.TypeObject {
+		env = obj.New()
+		goObj.Set("env", env)
+	}
+	env.Set(envName, e.Name)
+
+	e.RPC = newChannel(e.Name, register(e.Name), hostSide, pluginSide)
+	for _, fn := range e.setup {
+		fn(e)
+	}
+
+	resp, err := jsext.Await(fetch.Invoke(e.URL))
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrLoad, e.Name, err)
+	}
+	if !resp.Get("ok").Bool() {
+		return fmt.Errorf("%w: %s: %s: status %d", ErrLoad, e.Name, e.URL, resp.Get("status").Int())
+	}
+	source, err := jsext.Await(webAssembly.Call("instantiateStreaming", resp, goObj.Get("importObject")))
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrLoad, e.Name, err)
+	}
+	e.Module = source.Get("module")
+	e.Instance = source.Get("instance")
+	e.GoObject = goObj
+
+	e.onExit = js.FuncOf(e.exit)
+	started = true
+	goObj.Call("run", e.Instance).Call("then", e.onExit, e.onExit)
+
+	if e.Manifest == nil || e.ExportPath != "" {
+		if err = e.scrape(); err != nil {
+			return err
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	select {
+	case <-e.exited:
+		e.state = Stopped
+	default:
+		e.state = Running
+	}
+	return nil
+}
+
+// scrape collects the exports of the plugin, from the object at ExportPath.
+func (e *Plugin) scrape() error {
+	var exportObject js.Value
+	var split = strings.Split(e.ExportPath, ".")
+	switch {
+	case e.Flags&F_IMPORT_FROM_GO_OBJECT != 0:
+		exportObject = e.GoObject
+	case e.Flags&F_IMPORT_FROM_MODULE != 0:
+		exportObject = e.Module
+	case e.Flags&F_IMPORT_FROM_INSTANCE != 0:
+		exportObject = e.Instance
+	case e.Flags&F_IMPORT_FROM_GLOBAL != 0:
+		exportObject = js.Global()
+	default:
+		return fmt.Errorf("invalid flag %d", e.Flags)
+	}
+	for i := 0; i < len(split); i++ {
+		if strings.HasPrefix(split[i], "[") {
+			var index = strings.TrimPrefix(split[i], "[")
+			index = strings.TrimSuffix(index, "]")
+			split[i] = index
+		}
+		exportObject = exportObject.Get(split[i])
+		if exportObject.IsUndefined() {
+			return fmt.Errorf("export %s not found", e.ExportPath)
+		}
+	}
+	var exports = make(map[string]js.Value)
+	var keys = obj.Call("keys", exportObject)
+	for i := 0; i < keys.Length(); i++ {
+		var key = keys.Index(i).String()
+		exports[key] = exportObject.Get(key)
+	}
+	e.Exports = exports
+	e.ExportObject = exportObject
+	return nil
+}
+
+// register creates the object shared by the channels of the host and the plugin.
+func register(name string) js.Value {
+	var registry = js.Global().Get(registryKey)
+	if registry.Type() != js.TypeObject {
+		registry = obj.New()
+		js.Global().Set(registryKey, registry)
+	}
+	var v = obj.New()
+	registry.Set(name, v)
+	return v
+}
+
 type Plugins map[string]*Plugin
 
 func New() Plugins {
 	var m = make(map[string]*Plugin)
-	var instantiateStreaming = webAssembly.Get("instantiateStreaming")
-	if instantiateStreaming.IsUndefined() {
-		// polyfill
-		instantiateStreaming = js.Global().Call("eval", `
+	polyfill()
+	return m
+}
+
+var polyfillOnce sync.Once
+
+func polyfill() {
+	polyfillOnce.Do(func() {
+		var instantiateStreaming = webAssembly.Get("instantiateStreaming")
+		if instantiateStreaming.IsUndefined() {
+			instantiateStreaming = js.Global().Call("eval", `
 		WebAssembly.instantiateStreaming = async (resp, importObject) => {
 			const source = await (await resp).arrayBuffer();
 			return await WebAssembly.instantiate(source, importObject);
 		};`)
-		webAssembly.Set("instantiateStreaming", instantiateStreaming)
-	}
-	return m
+			webAssembly.Set("instantiateStreaming", instantiateStreaming)
+		}
+	})
 }
 
 var (
@@ -65,77 +356,153 @@ const (
 	F_IMPORT_FROM_GLOBAL
 )
 
+// mu guards the maps of Plugins, plugins can be loaded concurrently.
+var mu sync.Mutex
+
 func (e *Plugins) NewPlugin(name, url, pathToExports string, flag uint8) (*Plugin, error) {
-	if e == nil {
-		*e = make(map[string]*Plugin)
-	}
+	return e.add(&Plugin{
+		Name:       name,
+		URL:        url,
+		ExportPath: pathToExports,
+		Flags:      flag,
+	})
+}
 
-	var wait chan any = make(chan any)
-	var goObj = globalGo.New()
-	var importObject = goObj.Get("importObject")
-	var instantiateStreaming = webAssembly.Get("instantiateStreaming")
-	var module, instance js.Value
-	var err error
-	instantiateStreaming.Invoke(fetch.Invoke(url), importObject).Call("then", js.FuncOf(func(this js.Value, args []js.Value) any {
-		module = args[0].Get("module")
-		instance = args[0].Get("instance")
-		goObj.Call("run", instance)
-		wait <- true
-		return nil
-	})).Call("catch", js.FuncOf(func(this js.Value, args []js.Value) any {
-		var jsErr = args[0]
-		err = errs.Error(jsErr.Get("message").String())
-		wait <- true
-		return nil
-	}))
-	<-wait
+// Load fetches the manifest, and loads the plugin it describes.
+//
+// The setup functions are called before the plugin runs, to add handlers to its RPC channel.
+//
+//	var p, err = loaded.Load("/static/plugins/markdown.json", func(p *plugins.Plugin) {
+//		plugins.Handle(p.RPC, "theme", func(plugins.Void) (string, error) {
+//			return "dark", nil
+//		})
+//	})
+func (e *Plugins) Load(manifestURL string, setup ...func(p *Plugin)) (*Plugin, error) {
+	var m, err = FetchManifest(manifestURL)
 	if err != nil {
 		return nil, err
 	}
-	var exportObject js.Value
-	var split = strings.Split(pathToExports, ".")
-	switch {
-	case flag&F_IMPORT_FROM_GO_OBJECT != 0:
-		exportObject = goObj
-	case flag&F_IMPORT_FROM_MODULE != 0:
-		exportObject = module
-	case flag&F_IMPORT_FROM_INSTANCE != 0:
-		exportObject = instance
-	case flag&F_IMPORT_FROM_GLOBAL != 0:
-		exportObject = js.Global()
-	default:
-		return nil, fmt.Errorf("invalid flag %d", flag)
+	return e.LoadManifest(*m, setup...)
+}
+
+// LoadManifest loads the plugin of a manifest, exports are read from the window if the manifest has an exports path.
+func (e *Plugins) LoadManifest(m Manifest, setup ...func(p *Plugin)) (*Plugin, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
 	}
-	for i := 0; i < len(split); i++ {
-		if strings.HasPrefix(split[i], "[") {
-			var index = strings.TrimPrefix(split[i], "[")
-			index = strings.TrimSuffix(index, "]")
-			split[i] = index
-		}
-		exportObject = exportObject.Get(split[i])
-		if exportObject.IsUndefined() {
-			return nil, fmt.Errorf("export %s not found", pathToExports)
+	return e.add(&Plugin{
+		Name:       m.Name,
+		Manifest:   &m,
+		URL:        m.URL,
+		ExportPath: m.Exports,
+		Flags:      F_IMPORT_FROM_GLOBAL,
+		setup:      setup,
+	})
+}
+
+// LoadAll loads the plugins of the manifests concurrently.
+//
+// A plugin which fails to load does not stop the others, the errors are returned by manifest URL.
+func (e *Plugins) LoadAll(manifestURLs []string, setup ...func(p *Plugin)) map[string]error {
+	var wg sync.WaitGroup
+	var errMu sync.Mutex
+	var failed = make(map[string]error)
+	for _, url := range manifestURLs {
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			var err error
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						err = fmt.Errorf("%w: %s: %v", ErrLoad, url, r)
+					}
+				}()
+				_, err = e.Load(url, setup...)
+			}()
+			if err != nil {
+				errMu.Lock()
+				failed[url] = err
+				errMu.Unlock()
+			}
+		}(url)
+	}
+	wg.Wait()
+	if len(failed) == 0 {
+		return nil
+	}
+	return failed
+}
+
+func (e *Plugins) add(p *Plugin) (*Plugin, error) {
+	if e == nil {
+		return nil, ErrNilPlugins
+	}
+	mu.Lock()
+	if *e == nil {
+		*e = make(map[string]*Plugin)
+	}
+	if old := (*e)[p.Name]; old != nil {
+		if s := old.State(); s == Loading || s == Running {
+			mu.Unlock()
+			return nil, fmt.Errorf("%w: %s", ErrLoaded, p.Name)
 		}
 	}
-	var exports = make(map[string]js.Value)
-	var keys = obj.Call("keys", exportObject)
-	for i := 0; i < keys.Length(); i++ {
-		var key = keys.Index(i).String()
-		exports[key] = exportObject.Get(key)
+	// The plugin is added while it loads, so it is not loaded twice.
+	(*e)[p.Name] = p
+	mu.Unlock()
+
+	if err := p.load(); err != nil {
+		mu.Lock()
+		if (*e)[p.Name] == p {
+			delete(*e, p.Name)
+		}
+		mu.Unlock()
+		return nil, err
 	}
+	return p, nil
+}
 
-	var exp = &Plugin{
-		ExportPath:   pathToExports,
-		Exports:      exports,
-		Module:       module,
-		Instance:     instance,
-		ExportObject: exportObject,
-		GoObject:     goObj,
-		Flags:        flag,
+// Unload stops the plugin and removes it, see Plugin.Unload.
+func (e *Plugins) Unload(name string) error {
+	if e == nil {
+		return ErrNilPlugins
+	}
+	mu.Lock()
+	var p = (*e)[name]
+	delete(*e, name)
+	mu.Unlock()
+	if p == nil {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
 	}
+	return p.Unload()
+}
 
-	(*e)[name] = exp
-	return exp, nil
+// Reload unloads the plugin, and loads it again from the same URL with the same setup functions.
+//
+// If the old Go runtime does not stop, it is detached and the plugin is loaded regardless.
+func (e *Plugins) Reload(name string) (*Plugin, error) {
+	if e == nil {
+		return nil, ErrNilPlugins
+	}
+	mu.Lock()
+	var p = (*e)[name]
+	mu.Unlock()
+	if p == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if s := p.State(); s == Loading {
+		return nil, fmt.Errorf("%w: %s", ErrLoaded, name)
+	}
+	p.Unload()
+	return e.add(&Plugin{
+		Name:       p.Name,
+		Manifest:   p.Manifest,
+		URL:        p.URL,
+		ExportPath: p.ExportPath,
+		Flags:      p.Flags,
+		setup:      p.setup,
+	})
 }
 
 func (e *Plugins) Call(pluginName, varName string, args ...any) (js.Value, error) {
@@ -147,6 +514,9 @@ func (e *Plugins) Call(pluginName, varName string, args ...any) (js.Value, error
 }
 
 func (e *Plugins) Get(pluginName, varName string) (js.Value, error) {
+	if e == nil {
+		return js.Undefined(), ErrNilPlugins
+	}
 	var export = (*e)[pluginName]
 	if export == nil {
 		return js.Undefined(), fmt.Errorf("export %s not found", pluginName)
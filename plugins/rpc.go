@@ -0,0 +1,379 @@
+package plugins
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Nigel2392/jsext/v2/errs"
+	"github.com/Nigel2392/jsext/v2/jsc"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+const (
+	ErrClosed         errs.Error = "plugins: channel closed"
+	ErrTimeout        errs.Error = "plugins: call timed out"
+	ErrRemote         errs.Error = "plugins: remote error"
+	ErrMethodNotFound errs.Error = "plugins: method not found"
+	ErrNotPlugin      errs.Error = "plugins: not running as a plugin"
+)
+
+// CallTimeout is the default timeout of calls over a Channel.
+var CallTimeout = 30 * time.Second
+
+const (
+	// registryKey is the object on the window which holds the shared object of each plugin channel.
+	registryKey = "__jsextPlugins"
+	// envName is the environment variable with the name of the plugin, it is set on the Go object of the plugin.
+	envName    = "JSEXT_PLUGIN"
+	hostSide   = "host"
+	pluginSide = "plugin"
+	stopMethod = "$stop"
+)
+
+// Channel is a connection between the host and a plugin.
+//
+// Both sides share a javascript object, on which each side sets a function to receive messages.
+// Messages are delivered asynchronously, and queued until the other side is connected.
+// Parameters and results are encoded with jsc.
+//
+//	// Host
+//	plugins.Handle(p.RPC, "log", func(msg string) (plugins.Void, error) {
+//		console.Log(msg)
+//		return plugins.Void{}, nil
+//	})
+//
+//	// Plugin
+//	var host, _ = plugins.Connect()
+//	plugins.Call[plugins.Void](host, "log", "Hello from the plugin")
+//	<-host.Done()
+type Channel struct {
+	Name string
+	// Timeout of calls, 0 waits until the channel is closed.
+	Timeout time.Duration
+
+	v       js.Value
+	local   string
+	remote  string
+	receive js.Func
+
+	mu      sync.Mutex
+	nextID  int
+	calls   map[int]chan js.Value
+	methods map[string]func(params js.Value) (any, error)
+	events  map[string][]func(data js.Value)
+	done    chan struct{}
+	closed  bool
+}
+
+// Void is used as the parameters or result of methods without a value.
+type Void = struct{}
+
+func newChannel(name string, v js.Value, local, remote string) *Channel {
+	var c = &Channel{
+		Name:    name,
+		Timeout: CallTimeout,
+		v:       v,
+		local:   local,
+		remote:  remote,
+		calls:   make(map[int]chan js.Value),
+		methods: make(map[string]func(params js.Value) (any, error)),
+		events:  make(map[string][]func(data js.Value)),
+		done:    make(chan struct{}),
+	}
+	c.receive = js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) > 0 {
+			c.dispatch(args[0])
+		}
+		return nil
+	})
+	v.Set(local, c.receive)
+
+	// Messages which were sent before this side was connected.
+	var queued = v.Get(queueKey(local))
+	v.Delete(queueKey(local))
+	if queued.Type() == js.TypeObject {
+		for i := 0; i < queued.Length(); i++ {
+			c.dispatch(queued.Index(i))
+		}
+	}
+	return c
+}
+
+// Connect connects a plugin to the host which loaded it.
+//
+// The channel is closed when the host unloads the plugin, main should return after Done is closed.
+func Connect() (*Channel, error) {
+	var name = os.Getenv(envName)
+	if name == "" {
+		return nil, ErrNotPlugin
+	}
+	var v = js.Global().Get(registryKey)
+	if v.Type() == js.TypeObject {
+		v = v.Get(name)
+	}
+	if v.Type() != js.TypeObject {
+		return nil, fmt.Errorf("%w: %s is not registered", ErrNotPlugin, name)
+	}
+	var c = newChannel(name, v, pluginSide, hostSide)
+	c.methods[stopMethod] = func(js.Value) (any, error) {
+		return nil, nil
+	}
+	return c, nil
+}
+
+func queueKey(side string) string {
+	return side + "Queue"
+}
+
+// Connected reports whether the other side has connected to the channel.
+func (c *Channel) Connected() bool {
+	return c.v.Get(c.remote).Type() == js.TypeFunction
+}
+
+// Done is closed when the channel is closed.
+func (c *Channel) Done() <-chan struct{} {
+	return c.done
+}
+
+// Close closes the channel, pending calls return ErrClosed.
+func (c *Channel) Close() {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return
+	}
+	c.closed = true
+	var calls = c.calls
+	c.calls = nil
+	c.mu.Unlock()
+
+	for _, ch := range calls {
+		close(ch)
+	}
+	if c.v.Get(c.local).Equal(c.receive.Value) {
+		c.v.Delete(c.local)
+	}
+	c.receive.Release()
+	close(c.done)
+}
+
+func (c *Channel) send(msg js.Value) error {
+	c.mu.Lock()
+	var closed = c.closed
+	c.mu.Unlock()
+	if closed {
+		return ErrClosed
+	}
+	var receiver = c.v.Get(c.remote)
+	if receiver.Type() != js.TypeFunction {
+		var queue = c.v.Get(queueKey(c.remote))
+		if queue.Type() != js.TypeObject {
+			queue = js.Global().Get("Array").New()
+			c.v.Set(queueKey(c.remote), queue)
+		}
+		queue.Call("push", msg)
+		return nil
+	}
+	js.Global().Call("setTimeout", receiver, 0, msg)
+	return nil
+}
+
+func (c *Channel) dispatch(msg js.Value) {
+	if msg.Type() != js.TypeObject {
+		return
+	}
+	switch {
+	case msg.Get("event").Type() == js.TypeString:
+		var event = msg.Get("event").String()
+		c.mu.Lock()
+		var listeners = append([]func(js.Value){}, c.events[event]...)
+		c.mu.Unlock()
+		var data = msg.Get("data")
+		for _, fn := range listeners {
+			go fn(data)
+		}
+	case msg.Get("id").Type() != js.TypeNumber:
+		// Calls and responses without an id are malformed, they are dropped.
+	case msg.Get("method").Type() == js.TypeString:
+		go c.serve(msg.Get("id").Int(), msg.Get("method").String(), msg.Get("params"))
+	default:
+		c.mu.Lock()
+		var ch = c.calls[msg.Get("id").Int()]
+		c.mu.Unlock()
+		if ch != nil {
+			// Only the first response is used, a duplicate must not block the event loop.
+			select {
+			case ch <- msg:
+			default:
+			}
+		}
+	}
+}
+
+// serve runs the handler of a method, and sends the response.
+func (c *Channel) serve(id int, method string, params js.Value) {
+	c.mu.Lock()
+	var handler = c.methods[method]
+	c.mu.Unlock()
+
+	var resp = js.Global().Get("Object").New()
+	resp.Set("id", id)
+	var result, err = protect(method, handler, params)
+	if err == nil && result != nil {
+		var v js.Value
+		if v, err = jsc.ValueOf(result); err == nil {
+			resp.Set("result", v)
+		}
+	}
+	if err != nil {
+		resp.Set("error", err.Error())
+	}
+	c.send(resp)
+
+	if method == stopMethod && c.local == pluginSide {
+		c.Close()
+	}
+}
+
+// protect calls the handler, a panic is returned as an error.
+func protect(method string, handler func(js.Value) (any, error), params js.Value) (result any, err error) {
+	if handler == nil {
+		return nil, fmt.Errorf("%w: %s", ErrMethodNotFound, method)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("%s: panic: %v", method, r)
+		}
+	}()
+	return handler(params)
+}
+
+// Call calls a method on the other side, and waits for the result.
+//
+// It blocks, so it should not be called from a javascript callback.
+func (c *Channel) Call(method string, params any) (js.Value, error) {
+	var p, err = jsc.ValueOf(params)
+	if err != nil {
+		return js.Undefined(), err
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return js.Undefined(), ErrClosed
+	}
+	c.nextID++
+	var id = c.nextID
+	var ch = make(chan js.Value, 1)
+	c.calls[id] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.calls, id)
+		c.mu.Unlock()
+	}()
+
+	var msg = js.Global().Get("Object").New()
+	msg.Set("id", id)
+	msg.Set("method", method)
+	msg.Set("params", p)
+	if err = c.send(msg); err != nil {
+		return js.Undefined(), err
+	}
+
+	var timeout <-chan time.Time
+	if c.Timeout > 0 {
+		var t = time.NewTimer(c.Timeout)
+		defer t.Stop()
+		timeout = t.C
+	}
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			return js.Undefined(), ErrClosed
+		}
+		if e := resp.Get("error"); e.Type() == js.TypeString {
+			return js.Undefined(), fmt.Errorf("%w: %s: %s", ErrRemote, method, e.String())
+		}
+		return resp.Get("result"), nil
+	case <-timeout:
+		return js.Undefined(), fmt.Errorf("%w: %s", ErrTimeout, method)
+	}
+}
+
+// Emit sends an event to the other side, without waiting for it to be handled.
+func (c *Channel) Emit(event string, data any) error {
+	var v, err = jsc.ValueOf(data)
+	if err != nil {
+		return err
+	}
+	var msg = js.Global().Get("Object").New()
+	msg.Set("event", event)
+	msg.Set("data", v)
+	return c.send(msg)
+}
+
+// Handle sets the handler of a method, params are decoded with jsc.
+func Handle[Params, Result any](c *Channel, method string, fn func(params Params) (Result, error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.methods[method] = func(v js.Value) (any, error) {
+		var params, err = decode[Params](method, v)
+		if err != nil {
+			return nil, err
+		}
+		return fn(params)
+	}
+}
+
+// Call calls a method on the other side, and decodes the result with jsc.
+func Call[Result any](c *Channel, method string, params any) (Result, error) {
+	var v, err = c.Call(method, params)
+	if err != nil {
+		var result Result
+		return result, err
+	}
+	return decode[Result](method, v)
+}
+
+// On adds a listener for events from the other side, data is decoded with jsc.
+//
+// Events which can not be decoded are dropped, and a panic in fn does not affect other listeners.
+func On[T any](c *Channel, event string, fn func(data T)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.events[event] = append(c.events[event], func(v js.Value) {
+		protect(event, func(v js.Value) (any, error) {
+			var data, err = decode[T](event, v)
+			if err == nil {
+				fn(data)
+			}
+			return nil, err
+		}, v)
+	})
+}
+
+// decode scans the value with jsc, which panics when the value has the wrong type.
+// A panic is returned as an error.
+func decode[T any](method string, v js.Value) (result T, err error) {
+	_, err = protect(method, func(v js.Value) (any, error) {
+		if err := scan(v, &result); err != nil {
+			return nil, fmt.Errorf("%s: %w", method, err)
+		}
+		return nil, nil
+	}, v)
+	return result, err
+}
+
+func scan[T any](src js.Value, dst *T) error {
+	if src.IsUndefined() || src.IsNull() {
+		return nil
+	}
+	if v, ok := any(dst).(*js.Value); ok {
+		*v = src
+		return nil
+	}
+	return jsc.Scan(src, dst)
+}
@@ -0,0 +1,71 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package plugins
+
+import (
+	"testing"
+
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+func TestDecode(t *testing.T) {
+	var n, err = decode[int]("count", js.ValueOf(2))
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2, got %d, %v", n, err)
+	}
+	if _, err = decode[int]("count", js.ValueOf("two")); err == nil {
+		t.Fatal("expected an error for a string")
+	}
+}
+
+func TestOnBadData(t *testing.T) {
+	var c = newChannel("test", js.Global().Get("Object").New(), hostSide, pluginSide)
+	defer c.Close()
+
+	var got []int
+	On(c, "count", func(n int) {
+		got = append(got, n)
+	})
+	On(c, "count", func(n int) {
+		panic("listener")
+	})
+	for _, fn := range c.events["count"] {
+		fn(js.ValueOf("two"))
+		fn(js.ValueOf(2))
+	}
+	if len(got) != 1 || got[0] != 2 {
+		t.Fatalf("expected [2], got %v", got)
+	}
+}
+
+func TestDispatchBadID(t *testing.T) {
+	var c = newChannel("test", js.Global().Get("Object").New(), hostSide, pluginSide)
+	defer c.Close()
+
+	var msg = js.Global().Get("Object").New()
+	msg.Set("id", "1")
+	c.dispatch(msg)
+	msg.Set("method", "count")
+	c.dispatch(msg)
+}
+
+func TestDispatchDuplicateResponse(t *testing.T) {
+	var c = newChannel("test", js.Global().Get("Object").New(), hostSide, pluginSide)
+	defer c.Close()
+
+	var ch = make(chan js.Value, 1)
+	c.calls[1] = ch
+	var msg = js.Global().Get("Object").New()
+	msg.Set("id", 1)
+	msg.Set("result", "first")
+	c.dispatch(msg)
+	var dup = js.Global().Get("Object").New()
+	dup.Set("id", 1)
+	dup.Set("result", "second")
+	c.dispatch(dup)
+
+	if got := (<-ch).Get("result").String(); got != "first" {
+		t.Fatalf("expected the first response, got %q", got)
+	}
+}
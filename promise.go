@@ -1,6 +1,6 @@
 package jsext
 
-import "syscall/js"
+import js "github.com/Nigel2392/jsext/v2/jsv"
 
 type Promise struct {
 	// The underlying javascript value of the Promise.
@@ -77,3 +77,55 @@ func (w Promise) Catch(f func(error)) Promise {
 	var promise = w.MarshalJS().Call("catch", js.FuncOf(fn))
 	return Promise{Value(promise)}
 }
+
+// Await blocks until the Promise settles, and returns its value.
+func (w Promise) Await() (Value, error) {
+	var v, err = Await(w.MarshalJS())
+	return Value(v), err
+}
+
+// Await blocks the calling goroutine until the promise settles, and returns its value.
+//
+// A rejection is returned as a js.Error.
+// Await must not be called from a JavaScript callback, the promise can not settle while it blocks.
+func Await(promise js.Value) (js.Value, error) {
+	var (
+		done = make(chan js.Value, 1)
+		fail = make(chan js.Value, 1)
+	)
+	var resolve = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		done <- firstArg(args)
+		return nil
+	})
+	var reject = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		fail <- firstArg(args)
+		return nil
+	})
+	defer resolve.Release()
+	defer reject.Release()
+	promise.Call("then", resolve, reject)
+	select {
+	case v := <-done:
+		return v, nil
+	case reason := <-fail:
+		return js.Undefined(), rejection(reason)
+	}
+}
+
+func firstArg(args []js.Value) js.Value {
+	if len(args) == 0 {
+		return js.Undefined()
+	}
+	return args[0]
+}
+
+// rejection returns the reason of a rejected promise as a js.Error.
+func rejection(reason js.Value) error {
+	switch reason.Type() {
+	case js.TypeObject:
+		return js.Error{Value: reason}
+	case js.TypeString:
+		return js.Error{Value: js.Global().Get("Error").New(reason.String())}
+	}
+	return js.Error{Value: js.Global().Get("Error").New("promise rejected with " + reason.Type().String())}
+}
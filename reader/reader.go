@@ -3,7 +3,8 @@ package reader
 import (
 	"errors"
 	"io"
-	"syscall/js"
+
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 // Taken from go/src/net/http/roundtrip_js.go
@@ -0,0 +1,99 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package ssr
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Nigel2392/jsext/v2/jse"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+// The in-memory document is shared, so renders run one at a time.
+var mu sync.Mutex
+
+// Page is a complete HTML document.
+type Page struct {
+	Lang  string
+	Title string
+	Head  []*jse.Element
+	Body  []*jse.Element
+	// State is written as JSON at the end of the body, see LoadState.
+	State any
+	// Scripts are the sources of scripts loaded at the end of the body, like the wasm loader.
+	Scripts []string
+}
+
+// RenderFunc renders the element returned by build in an empty document.
+func RenderFunc(build func() *jse.Element) string {
+	mu.Lock()
+	defer mu.Unlock()
+	js.ResetDocument()
+	return Render(build())
+}
+
+// RenderPage renders a complete document, the page is built in an empty document.
+//
+// Elements added to the head while building, such as injected stylesheets, are rendered as well.
+func RenderPage(build func() (Page, error)) (string, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	js.ResetDocument()
+
+	var page, err = build()
+	if err != nil {
+		return "", err
+	}
+	var document = js.Global().Get("document")
+	var head, body = document.Get("head"), document.Get("body")
+	if page.Lang != "" {
+		document.Get("documentElement").Call("setAttribute", "lang", page.Lang)
+	}
+	var meta = document.Call("createElement", "meta")
+	meta.Call("setAttribute", "charset", "utf-8")
+	head.Call("prepend", meta)
+	if page.Title != "" {
+		document.Set("title", page.Title)
+	}
+	for _, e := range page.Head {
+		head.Call("appendChild", e.JSValue())
+	}
+	for _, e := range page.Body {
+		body.Call("appendChild", e.JSValue())
+	}
+	if page.State != nil {
+		var script, err = StateScript(page.State)
+		if err != nil {
+			return "", err
+		}
+		body.Call("appendChild", script.JSValue())
+	}
+	for _, src := range page.Scripts {
+		var script = document.Call("createElement", "script")
+		script.Call("setAttribute", "src", src)
+		body.Call("appendChild", script)
+	}
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n")
+	b.WriteString(document.Get("documentElement").Get("outerHTML").String())
+	return b.String(), nil
+}
+
+// Handler serves the page built for each request.
+func Handler(build func(r *http.Request) (Page, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var html, err = RenderPage(func() (Page, error) {
+			return build(r)
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(html))
+	})
+}
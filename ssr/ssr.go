@@ -0,0 +1,138 @@
+// Package ssr renders jse elements to HTML, so pages can be rendered on a server
+// and shown before the WebAssembly module has loaded.
+//
+// On a server the elements are built in the in-memory document of the jsv package.
+// In the browser, Hydrate attaches listeners and state to the server markup of a root,
+// the nodes are kept as they are. Elements are marked with Ref on the server, and found with Find.
+// Mount instead replaces the server markup with elements built in the browser.
+package ssr
+
+import (
+	"encoding/json"
+
+	"github.com/Nigel2392/jsext/v2/errs"
+	"github.com/Nigel2392/jsext/v2/jse"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+const (
+	// RootAttribute marks the root elements which are hydrated or mounted in the browser.
+	RootAttribute = "data-jsext-root"
+	// RefAttribute marks the elements which are found with Find when hydrating.
+	RefAttribute = "data-jsext-ref"
+	// StateElementID is the id of the script element which holds the state of the page.
+	StateElementID = "jsext-state"
+)
+
+const (
+	ErrNoRoot  = errs.Error("root not found")
+	ErrNoState = errs.Error("page state not found")
+)
+
+// Render returns the HTML of the element.
+func Render(e *jse.Element) string {
+	return e.JSValue().Get("outerHTML").String()
+}
+
+// RenderChildren returns the HTML of the children of the element.
+func RenderChildren(e *jse.Element) string {
+	return e.JSValue().Get("innerHTML").String()
+}
+
+// Root wraps the children in an element which can be hydrated or mounted with the same id.
+func Root(id string, children ...*jse.Element) *jse.Element {
+	var root = jse.NewElement("div")
+	root.SetAttr(RootAttribute, id)
+	root.AppendChild(children...)
+	return root
+}
+
+// Ref marks the element with a name, so it can be found with Find after the page is loaded.
+func Ref(e *jse.Element, name string) *jse.Element {
+	e.SetAttr(RefAttribute, name)
+	return e
+}
+
+// Find returns the element under root which was marked with the name by Ref, or nil.
+func Find(root *jse.Element, name string) *jse.Element {
+	var refs = root.JSValue().Call("querySelectorAll", "["+RefAttribute+"]")
+	return matchAttribute(refs, RefAttribute, name)
+}
+
+// Hydrate calls attach with the server-rendered root, to add listeners and state to the existing nodes.
+//
+// The server markup is kept as it is, so attach should only change what the server could not render.
+//
+//	// Server
+//	ssr.Root("counter", ssr.Ref(jse.NewElement("button", "0"), "button"))
+//
+//	// Browser
+//	ssr.Hydrate("counter", func(root *jse.Element) error {
+//		var button = ssr.Find(root, "button")
+//		button.AddEventListener("click", increment)
+//		return nil
+//	})
+func Hydrate(id string, attach func(root *jse.Element) error) (*jse.Element, error) {
+	var root = findRoot(id)
+	if root == nil {
+		return nil, ErrNoRoot
+	}
+	return root, attach(root)
+}
+
+// Mount replaces the server-rendered children of the root with the element returned by build.
+//
+// Unlike Hydrate the server markup is discarded, and the listeners and state live on the new elements.
+// Use LoadState to build the same content as the server.
+func Mount(id string, build func() *jse.Element) (*jse.Element, error) {
+	var root = findRoot(id)
+	if root == nil {
+		return nil, ErrNoRoot
+	}
+	var e = build()
+	root.JSValue().Call("replaceChildren", e.JSValue())
+	return e, nil
+}
+
+// findRoot returns the root with the id, or nil.
+func findRoot(id string) *jse.Element {
+	var roots = js.Global().Get("document").Call("querySelectorAll", "["+RootAttribute+"]")
+	return matchAttribute(roots, RootAttribute, id)
+}
+
+// matchAttribute returns the first of the elements with the value as attribute, or nil.
+//
+// Values are compared instead of put in a selector, so they do not need to be escaped.
+func matchAttribute(elements js.Value, attr, value string) *jse.Element {
+	for i := 0; i < elements.Length(); i++ {
+		var v = elements.Index(i)
+		if v.Call("getAttribute", attr).String() == value {
+			var e = jse.Element(v)
+			return &e
+		}
+	}
+	return nil
+}
+
+// StateScript returns a script element holding v as JSON, it can be read in the browser with LoadState.
+func StateScript(v any) (*jse.Element, error) {
+	var data, err = json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var script = jse.NewElement("script")
+	script.SetAttr("type", "application/json")
+	script.SetAttr("id", StateElementID)
+	// json.Marshal escapes <, > and &, so the data cannot close the script element.
+	script.JSValue().Set("textContent", string(data))
+	return script, nil
+}
+
+// LoadState decodes the state written by StateScript into v.
+func LoadState(v any) error {
+	var script = js.Global().Get("document").Call("getElementById", StateElementID)
+	if script.IsNull() {
+		return ErrNoState
+	}
+	return json.Unmarshal([]byte(script.Get("textContent").String()), v)
+}
@@ -0,0 +1,78 @@
+//go:build !(js && wasm)
+// +build !js !wasm
+
+package ssr
+
+import (
+	"testing"
+
+	"github.com/Nigel2392/jsext/v2"
+	"github.com/Nigel2392/jsext/v2/jse"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+// load renders the page on the "server", and loads its markup into a new document.
+func load(build func() *jse.Element) js.Value {
+	var html = RenderFunc(build)
+	js.ResetDocument()
+	var body = js.Global().Get("document").Get("body")
+	body.Set("innerHTML", html)
+	return body
+}
+
+func TestHydrate(t *testing.T) {
+	var body = load(func() *jse.Element {
+		return Root("counter", Ref(jse.NewElement("button", "0"), "button"))
+	})
+	var server = body.Call("querySelector", "button")
+
+	var clicks int
+	var _, err = Hydrate("counter", func(root *jse.Element) error {
+		var button = Find(root, "button")
+		if button == nil {
+			t.Fatal("button not found")
+		}
+		button.AddEventListener("click", func(this *jse.Element, event jsext.Event) {
+			clicks++
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !body.Call("querySelector", "button").Equal(server) {
+		t.Fatal("the server markup was replaced")
+	}
+	js.Click(server)
+	if clicks != 1 {
+		t.Fatalf("expected 1 click, got %d", clicks)
+	}
+}
+
+func TestMount(t *testing.T) {
+	var id = `a "quoted" \\ id`
+	var body = load(func() *jse.Element {
+		return Root(id, jse.NewElement("p", "server"))
+	})
+	var _, err = Mount(id, func() *jse.Element {
+		return jse.NewElement("p", "browser")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := body.Get("textContent").String(); text != "browser" {
+		t.Fatalf("expected the browser markup, got %q", text)
+	}
+}
+
+func TestNoRoot(t *testing.T) {
+	load(func() *jse.Element {
+		return Root("other")
+	})
+	if _, err := Hydrate("missing", func(*jse.Element) error { return nil }); err != ErrNoRoot {
+		t.Fatalf("expected ErrNoRoot, got %v", err)
+	}
+	if _, err := Mount(`"]`, func() *jse.Element { return nil }); err != ErrNoRoot {
+		t.Fatalf("expected ErrNoRoot, got %v", err)
+	}
+}
@@ -1,9 +1,8 @@
 package state
 
 import (
-	"syscall/js"
-
 	"github.com/Nigel2392/jsext/v2/errs"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 type statefulElement[T any] struct {
@@ -0,0 +1,202 @@
+package jsext
+
+import "github.com/Nigel2392/jsext/v2/css"
+
+// Typed setters for the most used properties, they take a css.Value instead of a string
+// and return the style, so calls can be chained:
+//
+//	el.Style().SetWidth(css.Percent(50)).SetPadding(css.Rem(1)).SetColor(css.Hsl(210, 0.5, 0.4))
+//
+// Other properties can be set with SetValue.
+
+func (s Style) SetWidth(v css.Value) Style {
+	return s.SetValue("width", v)
+}
+
+func (s Style) SetHeight(v css.Value) Style {
+	return s.SetValue("height", v)
+}
+
+func (s Style) SetMinWidth(v css.Value) Style {
+	return s.SetValue("min-width", v)
+}
+
+func (s Style) SetMinHeight(v css.Value) Style {
+	return s.SetValue("min-height", v)
+}
+
+func (s Style) SetMaxWidth(v css.Value) Style {
+	return s.SetValue("max-width", v)
+}
+
+func (s Style) SetMaxHeight(v css.Value) Style {
+	return s.SetValue("max-height", v)
+}
+
+func (s Style) SetTop(v css.Value) Style {
+	return s.SetValue("top", v)
+}
+
+func (s Style) SetRight(v css.Value) Style {
+	return s.SetValue("right", v)
+}
+
+func (s Style) SetBottom(v css.Value) Style {
+	return s.SetValue("bottom", v)
+}
+
+func (s Style) SetLeft(v css.Value) Style {
+	return s.SetValue("left", v)
+}
+
+func (s Style) SetInset(v css.Value) Style {
+	return s.SetValue("inset", v)
+}
+
+func (s Style) SetMargin(v css.Value) Style {
+	return s.SetValue("margin", v)
+}
+
+func (s Style) SetMarginTop(v css.Value) Style {
+	return s.SetValue("margin-top", v)
+}
+
+func (s Style) SetMarginRight(v css.Value) Style {
+	return s.SetValue("margin-right", v)
+}
+
+func (s Style) SetMarginBottom(v css.Value) Style {
+	return s.SetValue("margin-bottom", v)
+}
+
+func (s Style) SetMarginLeft(v css.Value) Style {
+	return s.SetValue("margin-left", v)
+}
+
+func (s Style) SetPadding(v css.Value) Style {
+	return s.SetValue("padding", v)
+}
+
+func (s Style) SetPaddingTop(v css.Value) Style {
+	return s.SetValue("padding-top", v)
+}
+
+func (s Style) SetPaddingRight(v css.Value) Style {
+	return s.SetValue("padding-right", v)
+}
+
+func (s Style) SetPaddingBottom(v css.Value) Style {
+	return s.SetValue("padding-bottom", v)
+}
+
+func (s Style) SetPaddingLeft(v css.Value) Style {
+	return s.SetValue("padding-left", v)
+}
+
+func (s Style) SetGap(v css.Value) Style {
+	return s.SetValue("gap", v)
+}
+
+func (s Style) SetRowGap(v css.Value) Style {
+	return s.SetValue("row-gap", v)
+}
+
+func (s Style) SetColumnGap(v css.Value) Style {
+	return s.SetValue("column-gap", v)
+}
+
+func (s Style) SetFontSize(v css.Value) Style {
+	return s.SetValue("font-size", v)
+}
+
+func (s Style) SetLineHeight(v css.Value) Style {
+	return s.SetValue("line-height", v)
+}
+
+func (s Style) SetLetterSpacing(v css.Value) Style {
+	return s.SetValue("letter-spacing", v)
+}
+
+func (s Style) SetTextIndent(v css.Value) Style {
+	return s.SetValue("text-indent", v)
+}
+
+func (s Style) SetBorderWidth(v css.Value) Style {
+	return s.SetValue("border-width", v)
+}
+
+func (s Style) SetBorderRadius(v css.Value) Style {
+	return s.SetValue("border-radius", v)
+}
+
+func (s Style) SetOutlineWidth(v css.Value) Style {
+	return s.SetValue("outline-width", v)
+}
+
+func (s Style) SetOutlineOffset(v css.Value) Style {
+	return s.SetValue("outline-offset", v)
+}
+
+func (s Style) SetColor(v css.Value) Style {
+	return s.SetValue("color", v)
+}
+
+func (s Style) SetBackgroundColor(v css.Value) Style {
+	return s.SetValue("background-color", v)
+}
+
+func (s Style) SetBorderColor(v css.Value) Style {
+	return s.SetValue("border-color", v)
+}
+
+func (s Style) SetOutlineColor(v css.Value) Style {
+	return s.SetValue("outline-color", v)
+}
+
+func (s Style) SetCaretColor(v css.Value) Style {
+	return s.SetValue("caret-color", v)
+}
+
+func (s Style) SetAccentColor(v css.Value) Style {
+	return s.SetValue("accent-color", v)
+}
+
+func (s Style) SetOpacity(v css.Value) Style {
+	return s.SetValue("opacity", v)
+}
+
+func (s Style) SetZIndex(v css.Value) Style {
+	return s.SetValue("z-index", v)
+}
+
+func (s Style) SetFlexGrow(v css.Value) Style {
+	return s.SetValue("flex-grow", v)
+}
+
+func (s Style) SetFlexShrink(v css.Value) Style {
+	return s.SetValue("flex-shrink", v)
+}
+
+func (s Style) SetFlexBasis(v css.Value) Style {
+	return s.SetValue("flex-basis", v)
+}
+
+func (s Style) SetTransform(v css.Value) Style {
+	return s.SetValue("transform", v)
+}
+
+func (s Style) SetTransitionDuration(v css.Value) Style {
+	return s.SetValue("transition-duration", v)
+}
+
+func (s Style) SetTransitionDelay(v css.Value) Style {
+	return s.SetValue("transition-delay", v)
+}
+
+func (s Style) SetAnimationDuration(v css.Value) Style {
+	return s.SetValue("animation-duration", v)
+}
+
+func (s Style) SetAnimationDelay(v css.Value) Style {
+	return s.SetValue("animation-delay", v)
+}
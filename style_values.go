@@ -0,0 +1,102 @@
+package jsext
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Nigel2392/jsext/v2/css"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
+
+// StyleMap maps CSS properties to their values.
+//
+// Properties are in kebab-case or camelCase, values are strings or css.Value.
+type StyleMap map[string]any
+
+// SetValue sets a typed CSS value on the style.
+//
+//	style.SetValue("width", css.Calc(css.Percent(100), "-", css.Px(20)))
+func (s Style) SetValue(property string, value css.Value) Style {
+	s.Value().Call("setProperty", propertyName(property), value.String())
+	return s
+}
+
+// Apply sets all properties in the map on the style.
+func (s Style) Apply(m StyleMap) Style {
+	for property, value := range m {
+		var str string
+		switch v := value.(type) {
+		case string:
+			str = v
+		case css.Value:
+			str = v.String()
+		default:
+			str = fmt.Sprint(v)
+		}
+		s.Value().Call("setProperty", propertyName(property), str)
+	}
+	return s
+}
+
+// Property returns the inline value of the property.
+func (s Style) Property(property string) string {
+	return s.Value().Call("getPropertyValue", propertyName(property)).String()
+}
+
+// Parse returns the inline value of the property as a typed value.
+func (s Style) Parse(property string) css.Value {
+	return css.ParseValue(s.Property(property))
+}
+
+// ComputedStyle is the result of getComputedStyle, its values are read-only.
+type ComputedStyle js.Value
+
+func (c ComputedStyle) MarshalJS() js.Value {
+	return js.Value(c)
+}
+
+// Get returns the computed value of the property.
+func (c ComputedStyle) Get(property string) string {
+	return js.Value(c).Call("getPropertyValue", propertyName(property)).String()
+}
+
+// Value returns the computed value of the property as a typed value.
+func (c ComputedStyle) Value(property string) css.Value {
+	return css.ParseValue(c.Get(property))
+}
+
+// Length returns the computed value of a length property, usually in pixels.
+func (c ComputedStyle) Length(property string) (css.Length, error) {
+	return css.ParseLength(c.Get(property))
+}
+
+// Color returns the computed value of a color property.
+func (c ComputedStyle) Color(property string) (css.AlphaColor, error) {
+	return css.Parse(c.Get(property))
+}
+
+// ComputedStyle returns the computed style of the element, or of a pseudo element like "::before".
+func (e Element) ComputedStyle(pseudo ...string) ComputedStyle {
+	if len(pseudo) > 0 {
+		return ComputedStyle(js.Global().Call("getComputedStyle", e.JSValue(), pseudo[0]))
+	}
+	return ComputedStyle(js.Global().Call("getComputedStyle", e.JSValue()))
+}
+
+// propertyName converts camelCase property names to kebab-case, custom properties are left as they are.
+func propertyName(property string) string {
+	if strings.HasPrefix(property, "--") || strings.ToLower(property) == property {
+		return property
+	}
+	var b strings.Builder
+	for i, r := range property {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -2,11 +2,11 @@ package try
 
 import (
 	"strings"
-	"syscall/js"
 
 	"github.com/Nigel2392/jsext/v2/errs"
 	"github.com/Nigel2392/jsext/v2/jsc"
 	"github.com/Nigel2392/jsext/v2/jsrand"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 // Catch is a function that takes a js.Func and any number of arguments.
@@ -1,9 +1,6 @@
-//go:build js && wasm
-// +build js,wasm
-
 package jsext
 
-import "syscall/js"
+import js "github.com/Nigel2392/jsext/v2/jsv"
 
 // Wrapper for javascript values to make life easier.
 type Value js.Value
@@ -72,19 +72,15 @@ func Open(url string, protocols ...string) (*WebSocket, error) {
 	var done = make(chan struct{})
 	var err = make(chan error)
 
-	var openFunc = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+	var openListener = jsext.Listen(ws.value, "open", func(this js.Value, event jsext.Event) {
 		ws.open = true
 		done <- struct{}{}
-		return nil
 	})
-	ws.value.Call("addEventListener", "open", openFunc)
 
-	var closeFunc = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+	var closeListener = jsext.Listen(ws.value, "close", func(this js.Value, event jsext.Event) {
 		ws.open = false
-		err <- eventToError(args[0])
-		return nil
+		err <- eventToError(event.JSValue())
 	})
-	ws.value.Call("addEventListener", "close", closeFunc)
 
 	select {
 	case <-done:
@@ -93,13 +89,13 @@ func Open(url string, protocols ...string) (*WebSocket, error) {
 	case e := <-err:
 		close(done)
 		close(err)
-		ws.value.Call("removeEventListener", "close", closeFunc)
-		ws.value.Call("removeEventListener", "open", openFunc)
+		closeListener.Remove()
+		openListener.Remove()
 		return ws, e
 	}
 
-	ws.value.Call("removeEventListener", "close", closeFunc)
-	ws.value.Call("removeEventListener", "open", openFunc)
+	closeListener.Remove()
+	openListener.Remove()
 
 	if ws.ReadyState() != SockOpen {
 		return ws, errs.Error("websocket: failed to open")
@@ -142,6 +138,20 @@ func eventToError(event js.Value) error {
 	return errs.Error(reason)
 }
 
+// AddEventListener adds an event listener to the websocket.
+//
+// The returned Listener can be used to remove it again.
+func (w *WebSocket) AddEventListener(event string, f func(w *WebSocket, e jsext.Event), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return jsext.Listen(w.value, event, func(_ js.Value, e jsext.Event) {
+		f(w, e)
+	}, opts...)
+}
+
+// Listeners returns the registry of listeners added with AddEventListener.
+func (w *WebSocket) Listeners() *jsext.Listeners {
+	return jsext.ListenersOf(w.value)
+}
+
 func (w *WebSocket) IsOpen() bool {
 	return w.open
 }
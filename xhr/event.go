@@ -1,6 +1,6 @@
 package xhr
 
-import "syscall/js"
+import js "github.com/Nigel2392/jsext/v2/jsv"
 
 type ProgressEvent js.Value
 
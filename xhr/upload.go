@@ -1,6 +1,9 @@
 package xhr
 
-import "syscall/js"
+import (
+	"github.com/Nigel2392/jsext/v2"
+	js "github.com/Nigel2392/jsext/v2/jsv"
+)
 
 type XMLHttpRequestUpload struct {
 	Value *js.Value
@@ -14,6 +17,20 @@ func (x *XMLHttpRequestUpload) Set(key string, value interface{}) {
 	x.Value.Set(key, value)
 }
 
+// AddEventListener adds an event listener to the upload.
+//
+// The returned Listener can be used to remove it again.
+func (x *XMLHttpRequestUpload) AddEventListener(event string, f func(event ProgressEvent), opts ...jsext.ListenerOptions) *jsext.Listener {
+	return jsext.Listen(*x.Value, event, func(_ js.Value, e jsext.Event) {
+		f(ProgressEvent(e.JSValue()))
+	}, opts...)
+}
+
+// Listeners returns the registry of listeners added with AddEventListener.
+func (x *XMLHttpRequestUpload) Listeners() *jsext.Listeners {
+	return jsext.ListenersOf(*x.Value)
+}
+
 func (x *XMLHttpRequestUpload) Abort(f func(event ProgressEvent)) js.Func {
 	if f == nil {
 		x.Set("onabort", nil)
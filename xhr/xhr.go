@@ -1,11 +1,11 @@
 package xhr
 
 import (
-	"syscall/js"
 	"time"
 
 	"github.com/Nigel2392/jsext/v2/encoding"
 	"github.com/Nigel2392/jsext/v2/errs"
+	js "github.com/Nigel2392/jsext/v2/jsv"
 )
 
 /*